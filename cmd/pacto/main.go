@@ -4,9 +4,11 @@ import (
 	"os"
 
 	"pacto/internal/app"
+	"pacto/internal/plugins"
 )
 
 func main() {
+	plugins.RunSandboxHelper()
 	os.Exit(run(os.Args[1:]))
 }
 
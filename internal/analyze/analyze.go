@@ -7,6 +7,7 @@ import (
 
 	"pacto/internal/model"
 	"pacto/internal/parser"
+	"pacto/internal/states"
 )
 
 type Options struct {
@@ -21,14 +22,18 @@ type Input struct {
 	Mode      string
 	Plans     []parser.ParsedPlan
 	Claims    map[string][]model.ClaimResult
-	Warnings  map[string][]string
+	// Checks holds claim results contributed by plugin status checks; they
+	// count toward verification and confidence like extracted claims.
+	Checks   map[string][]model.ClaimResult
+	Warnings map[string][]string
+	States   states.Machine
 }
 
 func Build(in Input, opts Options) model.StatusReport {
 	plans := make([]model.PlanStatus, 0, len(in.Plans))
 	for _, p := range in.Plans {
 		planKey := p.Ref.State + "/" + p.Ref.Slug
-		claims := in.Claims[planKey]
+		claims := append(append([]model.ClaimResult{}, in.Claims[planKey]...), in.Checks[planKey]...)
 		warn := append([]string{}, p.ParseWarnings...)
 		warn = append(warn, in.Warnings[planKey]...)
 		for _, c := range claims {
@@ -53,7 +58,7 @@ func Build(in Input, opts Options) model.StatusReport {
 
 		declared := p.DeclaredStatus
 		if strings.TrimSpace(declared) == "" {
-			declared = deriveStatusFromState(p.Ref.State)
+			declared = deriveStatusFromState(in.States, p.Ref.State)
 		}
 		derived := deriveFromSignals(p, blocked)
 		if p.LatestDeltaTime != nil {
@@ -95,7 +100,7 @@ func Build(in Input, opts Options) model.StatusReport {
 		return plans[i].StateFolder < plans[j].StateFolder
 	})
 
-	summary := summarize(plans)
+	summary := Summarize(plans)
 	plansRoot := in.PlansRoot
 	if plansRoot == "" {
 		plansRoot = in.Root
@@ -111,11 +116,13 @@ func Build(in Input, opts Options) model.StatusReport {
 		RepoRoot:    repoRoot,
 		Mode:        in.Mode,
 		Summary:     summary,
+		States:      in.States.IDs(),
 		Plans:       plans,
 	}
 }
 
-func summarize(plans []model.PlanStatus) model.Summary {
+// Summarize totals plans by state and verification.
+func Summarize(plans []model.PlanStatus) model.Summary {
 	byState := map[string]int{}
 	byVerif := map[string]int{}
 	pending := 0
@@ -227,7 +234,7 @@ func classifyConfidence(p parser.ParsedPlan, claims []model.ClaimResult) string
 	return "low"
 }
 
-func deriveStatusFromState(state string) string {
+func deriveStatusFromState(sm states.Machine, state string) string {
 	switch state {
 	case "current":
 		return "In Progress"
@@ -238,6 +245,9 @@ func deriveStatusFromState(state string) string {
 	case "outdated":
 		return "Outdated"
 	default:
+		if st, ok := sm.Get(state); ok && sm.Has(state) {
+			return st.Title
+		}
 		return "unknown"
 	}
 }
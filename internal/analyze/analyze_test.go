@@ -60,3 +60,25 @@ func TestBuildUsesFallbackNextActions(t *testing.T) {
 		t.Fatalf("NextActions[0]=%q, want %q", p.NextActions[0], "first task")
 	}
 }
+
+func TestBuildFoldsPluginChecksIntoVerification(t *testing.T) {
+	in := Input{
+		Root:  ".",
+		Mode:  "compat",
+		Plans: []parser.ParsedPlan{{Ref: model.PlanRef{State: "current", Slug: "a"}}},
+		Claims: map[string][]model.ClaimResult{
+			"current/a": {{ClaimType: model.ClaimPath, SourceText: "x", Result: "verified"}},
+		},
+		Checks: map[string][]model.ClaimResult{
+			"current/a": {{ClaimType: model.ClaimPlugin, SourceText: "migration exists", Result: "unverified", Source: "acme/migrations"}},
+		},
+	}
+
+	p := Build(in, Options{MaxNextActions: 3, MaxBlockers: 3}).Plans[0]
+	if p.Verification != "partial" {
+		t.Fatalf("Verification=%q, want partial", p.Verification)
+	}
+	if len(p.Claims) != 2 || p.Claims[1].Source != "acme/migrations" {
+		t.Fatalf("expected plugin claim appended, got %#v", p.Claims)
+	}
+}
@@ -0,0 +1,472 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"pacto/internal/discovery"
+	"pacto/internal/fsutil"
+	"pacto/internal/i18n"
+	"pacto/internal/journal"
+	"pacto/internal/states"
+	"pacto/internal/ui"
+)
+
+var reAgeDays = regexp.MustCompile(`^([0-9]+)([dw])$`)
+
+type archiveOptions struct {
+	root      string
+	state     string
+	olderThan string
+	reason    string
+	dryRun    bool
+}
+
+type archiveCandidate struct {
+	state    string
+	slug     string
+	dir      string
+	activity time.Time
+}
+
+func RunArchive(args []string) int {
+	opts := archiveOptions{}
+	fs := flag.NewFlagSet("archive", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.StringVar(&opts.root, "root", "", "Project root path (auto-discovers when omitted)")
+	fs.StringVar(&opts.state, "state", "done", "State to scan when using --older-than")
+	fs.StringVar(&opts.olderThan, "older-than", "", "Archive every plan in --state without activity for this long (e.g. 90d, 12w, 720h)")
+	fs.StringVar(&opts.reason, "reason", "", "Optional reason to record in plan README")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "Show plans that would be archived without writing files")
+	normalizedArgs, normErr := normalizeArgs(args, map[string]bool{
+		"--root": true, "-root": true, "--state": true, "-state": true,
+		"--older-than": true, "-older-than": true, "--reason": true, "-reason": true,
+	})
+	if normErr != nil {
+		fmt.Fprintf(os.Stderr, "parse args: %v\n", normErr)
+		return 2
+	}
+	if err := fs.Parse(normalizedArgs); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "parse flags: %v\n", err)
+		return 2
+	}
+	pos := fs.Args()
+	bulk := strings.TrimSpace(opts.olderThan) != ""
+	if bulk && len(pos) > 0 {
+		fmt.Fprintln(os.Stderr, "archive accepts either <state> <slug> or --older-than, not both")
+		return 2
+	}
+	if !bulk && len(pos) != 2 {
+		fmt.Fprintln(os.Stderr, "archive requires <state> <slug> or --older-than <age> [--state <state>]")
+		return 2
+	}
+
+	plansRoot, err := resolvePlansRootForAction(opts.root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
+		return 2
+	}
+	sm, code, ok := loadStateMachine(plansRoot)
+	if !ok {
+		return code
+	}
+	lang := effectiveLanguage(filepath.Dir(plansRoot))
+
+	lock, code, ok := lockWorkspace(plansRoot, lang)
+	if !ok {
+		return code
+	}
+	defer lock.Release()
+
+	var candidates []archiveCandidate
+	if bulk {
+		age, err := parseAge(opts.olderThan)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --older-than %q: %v\n", opts.olderThan, err)
+			return 2
+		}
+		state := strings.ToLower(strings.TrimSpace(opts.state))
+		if code := requireStates(sm, state); code != 0 {
+			return code
+		}
+		candidates, err = findArchiveCandidates(sm.Dir(plansRoot, state), state, time.Now().Add(-age))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "scan plans: %v\n", err)
+			return 3
+		}
+	} else {
+		state := strings.ToLower(strings.TrimSpace(pos[0]))
+		slug := strings.TrimSpace(pos[1])
+		if code := requireStates(sm, state); code != 0 {
+			return code
+		}
+		if !slugRe.MatchString(slug) {
+			fmt.Fprintf(os.Stderr, "invalid slug %q (use lowercase letters, numbers, dashes)\n", slug)
+			return 2
+		}
+		dir := filepath.Join(sm.Dir(plansRoot, state), slug)
+		if _, err := os.Stat(filepath.Join(dir, "README.md")); err != nil {
+			fmt.Fprintf(os.Stderr, "plan not found: %s/%s\n", state, slug)
+			return 2
+		}
+		candidates = []archiveCandidate{{state: state, slug: slug, dir: dir, activity: lastActivity(dir)}}
+	}
+
+	year := strconv.Itoa(time.Now().Year())
+	if len(candidates) == 0 {
+		fmt.Println(ui.Dim(tr(lang, "No plans to archive.", "No hay planes para archivar.")))
+		return 0
+	}
+	for _, c := range candidates {
+		dst := filepath.Join(plansRoot, discovery.ArchiveDir, year, c.slug)
+		if _, err := os.Stat(dst); err == nil {
+			fmt.Fprintf(os.Stderr, "archive destination already exists: %s\n", dst)
+			return 2
+		}
+	}
+
+	if opts.dryRun {
+		fmt.Println(ui.ActionHeader(tr(lang, "Dry Run", "Simulación"), tr(lang, "archive", "archivar")))
+		for _, c := range candidates {
+			fmt.Println(ui.Bullet(fmt.Sprintf("%s/%s -> %s/%s/%s (%s %s)", c.state, c.slug, discovery.ArchiveDir, year, c.slug, tr(lang, "last activity", "última actividad"), c.activity.Format("2006-01-02"))))
+		}
+		return 0
+	}
+
+	rec := newJournal(plansRoot, "archive", args)
+	refs := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		refs = append(refs, c.state+"/"+c.slug)
+	}
+	defer commitJournal(rec, "archived "+strings.Join(refs, ", "))
+
+	archiveYearDir := filepath.Join(plansRoot, discovery.ArchiveDir, year)
+	if err := rec.MkdirAll(archiveYearDir, 0o775); err != nil {
+		fmt.Fprintf(os.Stderr, "create archive dir: %v\n", err)
+		return 3
+	}
+	moves := make([]indexMove, 0, len(candidates))
+	for _, c := range candidates {
+		dst := filepath.Join(archiveYearDir, c.slug)
+		if err := rec.Rename(c.dir, dst); err != nil {
+			fmt.Fprintf(os.Stderr, "archive %s/%s: %v\n", c.state, c.slug, err)
+			return 3
+		}
+		readme := filepath.Join(dst, "README.md")
+		if err := markPlanArchived(rec, sm, readme, c.state, opts.reason, lang); err != nil {
+			return reportWriteError("update archived README", err, lang)
+		}
+		moves = append(moves, indexMove{
+			fromState: c.state, fromRef: c.slug,
+			toState: discovery.ArchiveDir, toRef: year + "/" + c.slug,
+			title: readPlanTitle(readme), slug: c.slug,
+		})
+	}
+	if err := rewriteIndexForMoves(rec, plansRoot, moves, lang); err != nil {
+		return reportWriteError("update root README", err, lang)
+	}
+
+	fmt.Println(ui.ActionHeader(tr(lang, "Archived Plans", "Planes archivados"), fmt.Sprintf("%d", len(candidates))))
+	for _, m := range moves {
+		fmt.Println(pathLine("archived", filepath.Join(plansRoot, discovery.ArchiveDir, filepath.FromSlash(m.toRef))))
+	}
+	fmt.Println(pathLine("updated", filepath.Join(plansRoot, "README.md")))
+	return 0
+}
+
+func RunUnarchive(args []string) int {
+	fs := flag.NewFlagSet("unarchive", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	root := fs.String("root", "", "Project root path (auto-discovers when omitted)")
+	to := fs.String("to", "", "Destination state (defaults to the state recorded at archive time)")
+	year := fs.String("year", "", "Archive year to restore from when the slug exists in several years")
+	reason := fs.String("reason", "", "Optional reason to record in plan README")
+	normalizedArgs, normErr := normalizeArgs(args, map[string]bool{
+		"--root": true, "-root": true, "--to": true, "-to": true,
+		"--year": true, "-year": true, "--reason": true, "-reason": true,
+	})
+	if normErr != nil {
+		fmt.Fprintf(os.Stderr, "parse args: %v\n", normErr)
+		return 2
+	}
+	if err := fs.Parse(normalizedArgs); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "parse flags: %v\n", err)
+		return 2
+	}
+	if len(fs.Args()) != 1 {
+		fmt.Fprintln(os.Stderr, "unarchive requires <slug>")
+		return 2
+	}
+	slug := strings.TrimSpace(fs.Args()[0])
+	if !slugRe.MatchString(slug) {
+		fmt.Fprintf(os.Stderr, "invalid slug %q (use lowercase letters, numbers, dashes)\n", slug)
+		return 2
+	}
+
+	plansRoot, err := resolvePlansRootForAction(*root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
+		return 2
+	}
+	sm, code, ok := loadStateMachine(plansRoot)
+	if !ok {
+		return code
+	}
+	lang := effectiveLanguage(filepath.Dir(plansRoot))
+	lock, code, ok := lockWorkspace(plansRoot, lang)
+	if !ok {
+		return code
+	}
+	defer lock.Release()
+
+	archived, err := discovery.FindArchived(plansRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "scan archive: %v\n", err)
+		return 3
+	}
+	archiveRoot := filepath.Join(plansRoot, discovery.ArchiveDir)
+	matches := make([]string, 0, 1)
+	for _, p := range archived {
+		if p.Slug != slug {
+			continue
+		}
+		rel, _ := filepath.Rel(archiveRoot, p.Dir)
+		rel = filepath.ToSlash(rel)
+		if y := strings.TrimSpace(*year); y != "" && rel != y+"/"+slug {
+			continue
+		}
+		matches = append(matches, rel)
+	}
+	if len(matches) == 0 {
+		fmt.Fprintf(os.Stderr, "archived plan not found: %s\n", slug)
+		return 2
+	}
+	if len(matches) > 1 {
+		fmt.Fprintf(os.Stderr, "slug %q is archived more than once (%s); pass --year\n", slug, strings.Join(matches, ", "))
+		return 2
+	}
+	ref := matches[0]
+	srcDir := filepath.Join(archiveRoot, filepath.FromSlash(ref))
+	readme := filepath.Join(srcDir, "README.md")
+
+	toState := strings.ToLower(strings.TrimSpace(*to))
+	if toState == "" {
+		toState = readArchivedFrom(readme)
+		if !sm.Has(toState) {
+			toState = "done"
+		}
+	}
+	if code := requireStates(sm, toState); code != 0 {
+		return code
+	}
+	dstDir := filepath.Join(sm.Dir(plansRoot, toState), slug)
+	if _, err := os.Stat(dstDir); err == nil {
+		fmt.Fprintf(os.Stderr, "destination already exists: %s\n", dstDir)
+		return 2
+	}
+
+	rec := newJournal(plansRoot, "unarchive", args)
+	defer commitJournal(rec, fmt.Sprintf("%s/%s -> %s/%s", discovery.ArchiveDir, ref, toState, slug))
+	if err := os.MkdirAll(filepath.Dir(dstDir), 0o775); err != nil {
+		fmt.Fprintf(os.Stderr, "create state dir: %v\n", err)
+		return 3
+	}
+	if err := rec.Rename(srcDir, dstDir); err != nil {
+		fmt.Fprintf(os.Stderr, "move plan directory: %v\n", err)
+		return 3
+	}
+	readme = filepath.Join(dstDir, "README.md")
+	if err := markPlanUnarchived(rec, sm, readme, toState, *reason, lang); err != nil {
+		return reportWriteError("update README", err, lang)
+	}
+	move := indexMove{fromState: discovery.ArchiveDir, fromRef: ref, toState: toState, toRef: slug, title: readPlanTitle(readme), slug: slug}
+	if err := rewriteIndexForMoves(rec, plansRoot, []indexMove{move}, lang); err != nil {
+		return reportWriteError("update root README", err, lang)
+	}
+
+	fmt.Println(ui.ActionHeader(tr(lang, "Unarchived Plan", "Plan desarchivado"), fmt.Sprintf("%s/%s -> %s/%s", discovery.ArchiveDir, ref, toState, slug)))
+	fmt.Println(pathLine("updated", readme))
+	fmt.Println(pathLine("updated", filepath.Join(plansRoot, "README.md")))
+	return 0
+}
+
+// indexMove describes one link relocation in the root README. Refs are paths
+// relative to the state folder, e.g. "2026/slug" for dated archive entries.
+type indexMove struct {
+	fromState string
+	fromRef   string
+	toState   string
+	toRef     string
+	title     string
+	slug      string
+}
+
+func rewriteIndexForMoves(rec *journal.Recorder, plansRoot string, moves []indexMove, lang i18n.Language) error {
+	rootReadme := filepath.Join(plansRoot, "README.md")
+	b, snap, err := fsutil.ReadSnapshot(rootReadme)
+	if err != nil {
+		return err
+	}
+	text := string(b)
+	sm := stateMachine(plansRoot)
+	counts, err := countPlans(plansRoot, sm)
+	if err != nil {
+		return err
+	}
+	if counts[discovery.ArchiveDir] > 0 {
+		text = ensureCountRow(text, sm, indexState(sm, states.Archive))
+	}
+	text = updateCountsTable(text, sm, counts)
+	for _, m := range moves {
+		title := m.title
+		if title == "" {
+			title = slugToTitle(m.slug)
+		}
+		to := indexState(sm, m.toState)
+		text = removePlanLinkFromSection(text, indexState(sm, m.fromState), m.fromRef)
+		text, err = upsertLinkInSection(text, to, title, fmt.Sprintf("./%s/%s/", to.Folder, m.toRef))
+		if err != nil {
+			return err
+		}
+	}
+	text = updateLastUpdate(text, time.Now().Format("2006-01-02"), lang)
+	return rec.WriteChecked(snap, []byte(text), 0o664)
+}
+
+// markPlanArchived sets the archived status and records the state the plan
+// came from, in a single write so undo sees one change per file.
+func markPlanArchived(rec *journal.Recorder, sm states.Machine, readme, fromState, reason string, lang i18n.Language) error {
+	b, snap, err := fsutil.ReadSnapshot(readme)
+	if err != nil {
+		return err
+	}
+	text := withPlanStatus(string(b), sm, discovery.ArchiveDir, fromState, reason, lang)
+	lines := strings.Split(text, "\n")
+	marker := tr(lang, "**Archived From:** ", "**Archivado desde:** ") + fromState + " (" + time.Now().Format("2006-01-02") + ")  "
+	for i, ln := range lines {
+		t := strings.TrimSpace(ln)
+		if strings.HasPrefix(t, "**Status:**") || strings.HasPrefix(t, "**Estado:**") {
+			out := make([]string, 0, len(lines)+1)
+			out = append(out, lines[:i+1]...)
+			out = append(out, marker)
+			out = append(out, lines[i+1:]...)
+			return rec.WriteChecked(snap, []byte(strings.Join(out, "\n")), 0o664)
+		}
+	}
+	return rec.WriteChecked(snap, []byte(marker+"\n"+text), 0o664)
+}
+
+func readArchivedFrom(readme string) string {
+	b, err := os.ReadFile(readme)
+	if err != nil {
+		return ""
+	}
+	for _, ln := range strings.Split(string(b), "\n") {
+		t := strings.TrimSpace(ln)
+		for _, prefix := range []string{"**Archived From:**", "**Archivado desde:**"} {
+			if strings.HasPrefix(t, prefix) {
+				fields := strings.Fields(strings.TrimPrefix(t, prefix))
+				if len(fields) > 0 {
+					return strings.ToLower(fields[0])
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// markPlanUnarchived sets the restored status and drops the archived-from
+// marker in a single write.
+func markPlanUnarchived(rec *journal.Recorder, sm states.Machine, readme, toState, reason string, lang i18n.Language) error {
+	b, snap, err := fsutil.ReadSnapshot(readme)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(withPlanStatus(string(b), sm, toState, discovery.ArchiveDir, reason, lang), "\n")
+	out := make([]string, 0, len(lines))
+	for _, ln := range lines {
+		t := strings.TrimSpace(ln)
+		if strings.HasPrefix(t, "**Archived From:**") || strings.HasPrefix(t, "**Archivado desde:**") {
+			continue
+		}
+		out = append(out, ln)
+	}
+	return rec.WriteChecked(snap, []byte(strings.Join(out, "\n")), 0o664)
+}
+
+func findArchiveCandidates(stateDir, state string, cutoff time.Time) ([]archiveCandidate, error) {
+	ents, err := os.ReadDir(stateDir)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]archiveCandidate, 0)
+	for _, e := range ents {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(stateDir, e.Name())
+		if _, err := os.Stat(filepath.Join(dir, "README.md")); err != nil {
+			continue
+		}
+		activity := lastActivity(dir)
+		if activity.After(cutoff) {
+			continue
+		}
+		out = append(out, archiveCandidate{state: state, slug: e.Name(), dir: dir, activity: activity})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].slug < out[j].slug })
+	return out, nil
+}
+
+// lastActivity is the newest modification time of any file in a plan folder.
+func lastActivity(dir string) time.Time {
+	var latest time.Time
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest
+}
+
+func parseAge(raw string) (time.Duration, error) {
+	v := strings.ToLower(strings.TrimSpace(raw))
+	if m := reAgeDays.FindStringSubmatch(v); len(m) == 3 {
+		n, _ := strconv.Atoi(m[1])
+		if n == 0 {
+			return 0, fmt.Errorf("age must be positive")
+		}
+		days := n
+		if m[2] == "w" {
+			days = n * 7
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("use <n>d, <n>w or a Go duration such as 720h")
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("age must be positive")
+	}
+	return d, nil
+}
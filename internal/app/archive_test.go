@@ -0,0 +1,132 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunArchiveAndUnarchiveUpdateIndex(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	captureOutput(t, func() {
+		if code := RunNew([]string{"done", "archive-sample", "--root", root}); code != 0 {
+			t.Fatalf("RunNew returned %d", code)
+		}
+	})
+
+	_, stderr := captureOutput(t, func() {
+		if code := RunArchive([]string{"done", "archive-sample", "--root", root}); code != 0 {
+			t.Fatalf("RunArchive returned %d", code)
+		}
+	})
+	if stderr != "" {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+	year := strconv.Itoa(time.Now().Year())
+	archivedReadme := filepath.Join(plansRoot, "archive", year, "archive-sample", "README.md")
+	b, err := os.ReadFile(archivedReadme)
+	if err != nil {
+		t.Fatalf("expected archived plan: %v", err)
+	}
+	if !strings.Contains(string(b), "**Status:** Archived (Archive)") || !strings.Contains(string(b), "**Archived From:** done") {
+		t.Fatalf("expected archived README markers, got %q", string(b))
+	}
+	idx, _ := os.ReadFile(filepath.Join(plansRoot, "README.md"))
+	index := string(idx)
+	if !strings.Contains(index, "| ✅ **Done** | 0 |") || !strings.Contains(index, "| 🗄️ **Archive** | 1 |") {
+		t.Fatalf("expected counts updated, got %q", index)
+	}
+	if strings.Contains(index, "./done/archive-sample/") || !strings.Contains(index, "./archive/"+year+"/archive-sample/") {
+		t.Fatalf("expected link moved to archive section, got %q", index)
+	}
+
+	_, stderr = captureOutput(t, func() {
+		if code := RunUnarchive([]string{"archive-sample", "--root", root}); code != 0 {
+			t.Fatalf("RunUnarchive returned %d", code)
+		}
+	})
+	if stderr != "" {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+	b, err = os.ReadFile(filepath.Join(plansRoot, "done", "archive-sample", "README.md"))
+	if err != nil {
+		t.Fatalf("expected plan restored to done: %v", err)
+	}
+	if strings.Contains(string(b), "Archived From") || !strings.Contains(string(b), "Completed (Done)") {
+		t.Fatalf("expected archive markers cleared, got %q", string(b))
+	}
+	idx, _ = os.ReadFile(filepath.Join(plansRoot, "README.md"))
+	index = string(idx)
+	if !strings.Contains(index, "| 🗄️ **Archive** | 0 |") || !strings.Contains(index, "./done/archive-sample/") {
+		t.Fatalf("expected index restored, got %q", index)
+	}
+}
+
+func TestRunArchiveOlderThanSelectsStalePlans(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	captureOutput(t, func() {
+		for _, slug := range []string{"stale-plan", "fresh-plan"} {
+			if code := RunNew([]string{"done", slug, "--root", root}); code != 0 {
+				t.Fatalf("RunNew returned %d", code)
+			}
+		}
+	})
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+	old := time.Now().Add(-200 * 24 * time.Hour)
+	staleDir := filepath.Join(plansRoot, "done", "stale-plan")
+	ents, _ := os.ReadDir(staleDir)
+	for _, e := range ents {
+		if err := os.Chtimes(filepath.Join(staleDir, e.Name()), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout, _ := captureOutput(t, func() {
+		if code := RunArchive([]string{"--older-than", "90d", "--state", "done", "--root", root, "--dry-run"}); code != 0 {
+			t.Fatalf("dry-run returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, "done/stale-plan") || strings.Contains(stdout, "fresh-plan") {
+		t.Fatalf("expected only stale plan in dry-run, got %q", stdout)
+	}
+	if _, err := os.Stat(staleDir); err != nil {
+		t.Fatalf("dry-run should not move plans: %v", err)
+	}
+
+	captureOutput(t, func() {
+		if code := RunArchive([]string{"--older-than", "90d", "--state", "done", "--root", root}); code != 0 {
+			t.Fatalf("RunArchive returned %d", code)
+		}
+	})
+	if _, err := os.Stat(staleDir); !os.IsNotExist(err) {
+		t.Fatalf("expected stale plan archived, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(plansRoot, "done", "fresh-plan")); err != nil {
+		t.Fatalf("expected fresh plan kept: %v", err)
+	}
+
+	for _, age := range []string{"0d", "0w", "0s"} {
+		_, stderr := captureOutput(t, func() {
+			if code := RunArchive([]string{"--older-than", age, "--state", "done", "--root", root}); code != 2 {
+				t.Fatalf("--older-than %s returned %d, want 2", age, code)
+			}
+		})
+		if !strings.Contains(stderr, "age must be positive") {
+			t.Fatalf("expected positive age error for %s, got %q", age, stderr)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(plansRoot, "done", "fresh-plan")); err != nil {
+		t.Fatalf("a zero age must not archive anything: %v", err)
+	}
+}
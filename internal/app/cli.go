@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"pacto/internal/i18n"
+	"pacto/internal/plugins"
 )
 
 func Run(args []string) int {
@@ -17,6 +18,8 @@ func Run(args []string) int {
 	setGlobalLangOverride(langArg)
 	defer setGlobalLangOverride("")
 	args, allowGuardrails := stripAllowGuardrailArg(args)
+	guardrailAllow = allowGuardrails
+	defer func() { guardrailAllow = nil }()
 	args, noColorUsed := stripNoColorArg(args)
 	if noColorUsed {
 		_ = os.Setenv("NO_COLOR", "1")
@@ -27,12 +30,23 @@ func Run(args []string) int {
 	}
 
 	cmd := strings.ToLower(strings.TrimSpace(args[0]))
-	rest := args[1:]
-	lang := effectiveLanguage("")
+	rest, err := rewriteWorkspaceArg(cmd, args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 2
+	}
 	if code, handled := runGuardrailsIfNeeded(cmd, rest, allowGuardrails, hasVerboseArg(rest)); handled {
 		return code
 	}
+	commandOutcome = plugins.Outcome{}
+	code := runCommand(cmd, rest)
+	refreshLiveContextIfNeeded(cmd, rest, code)
+	runPostHooksIfNeeded(cmd, rest, code, hasVerboseArg(rest))
+	return code
+}
 
+func runCommand(cmd string, rest []string) int {
+	lang := effectiveLanguage("")
 	switch cmd {
 	case "-h", "--help", "help":
 		return runHelp(rest)
@@ -69,6 +83,12 @@ func Run(args []string) int {
 			return 0
 		}
 		return RunInstall(rest)
+	case "uninstall":
+		if wantsHelp(rest) {
+			fmt.Print(HelpForLang("uninstall", lang))
+			return 0
+		}
+		return RunUninstall(rest)
 	case "update":
 		if wantsHelp(rest) {
 			fmt.Print(HelpForLang("update", lang))
@@ -87,13 +107,64 @@ func Run(args []string) int {
 			return 0
 		}
 		return RunMove(rest)
+	case "rename":
+		if wantsHelp(rest) {
+			fmt.Print(HelpForLang("rename", lang))
+			return 0
+		}
+		return RunRename(rest)
+	case "split":
+		if wantsHelp(rest) {
+			fmt.Print(HelpForLang("split", lang))
+			return 0
+		}
+		return RunSplit(rest)
+	case "merge":
+		if wantsHelp(rest) {
+			fmt.Print(HelpForLang("merge", lang))
+			return 0
+		}
+		return RunMerge(rest)
+	case "archive":
+		if wantsHelp(rest) {
+			fmt.Print(HelpForLang("archive", lang))
+			return 0
+		}
+		return RunArchive(rest)
+	case "unarchive":
+		if wantsHelp(rest) {
+			fmt.Print(HelpForLang("unarchive", lang))
+			return 0
+		}
+		return RunUnarchive(rest)
+	case "undo":
+		if wantsHelp(rest) {
+			fmt.Print(HelpForLang("undo", lang))
+			return 0
+		}
+		return RunUndo(rest)
+	case "log":
+		if wantsHelp(rest) {
+			fmt.Print(HelpForLang("log", lang))
+			return 0
+		}
+		return RunLog(rest)
 	case "plugin":
 		if wantsHelp(rest) {
 			fmt.Print(HelpForLang("plugin", lang))
 			return 0
 		}
 		return RunPlugin(rest)
+	case "config":
+		if wantsHelp(rest) {
+			fmt.Print(HelpForLang("config", lang))
+			return 0
+		}
+		return RunConfig(rest)
 	default:
+		if code, ok := runPluginCommand(cmd, rest); ok {
+			return code
+		}
 		fmt.Fprint(os.Stderr, UnknownCommandMessage(cmd))
 		fmt.Print(RootHelpLang(lang))
 		return 2
@@ -0,0 +1,220 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pacto/internal/config"
+)
+
+func RunConfig(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: pacto config <get|set|list> [options]")
+		return 2
+	}
+	sub := strings.ToLower(strings.TrimSpace(args[0]))
+	rest := args[1:]
+	switch sub {
+	case "get":
+		return runConfigGet(rest)
+	case "set":
+		return runConfigSet(rest)
+	case "list":
+		return runConfigList(rest)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown config subcommand: %s\n", sub)
+		return 2
+	}
+}
+
+type configFlags struct {
+	root       string
+	configPath string
+	showOrigin bool
+	format     string
+	scope      string
+}
+
+func parseConfigFlags(name string, args []string, withValue map[string]bool) (configFlags, []string, int, bool) {
+	var opts configFlags
+	fs := flag.NewFlagSet("config "+name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.StringVar(&opts.root, "root", ".", "Directory to resolve configuration from")
+	fs.StringVar(&opts.configPath, "config", "", "Optional path to .pacto-engine.yaml")
+	switch name {
+	case "set":
+		fs.StringVar(&opts.scope, "scope", config.OriginProject, "Layer to write: user|project|engine")
+	default:
+		fs.BoolVar(&opts.showOrigin, "show-origin", false, "Print the layer and file or variable each value comes from")
+	}
+	if name == "list" {
+		fs.StringVar(&opts.format, "format", "table", "Output format: table|json")
+	}
+	normalized, err := normalizeArgs(args, withValue)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "parse args: %v\n", err)
+		return opts, nil, 2, false
+	}
+	if err := fs.Parse(normalized); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return opts, nil, 0, false
+		}
+		fmt.Fprintf(os.Stderr, "parse flags: %v\n", err)
+		return opts, nil, 2, false
+	}
+	abs, err := filepath.Abs(opts.root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
+		return opts, nil, 2, false
+	}
+	opts.root = abs
+	return opts, fs.Args(), 0, true
+}
+
+var configValueFlags = map[string]bool{"--root": true, "-root": true, "--config": true, "-config": true, "--format": true, "-format": true, "--scope": true, "-scope": true}
+
+func resolveConfig(opts configFlags) (config.Resolved, int, bool) {
+	res, err := config.Resolve(opts.configPath, opts.root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load config: %v\n", err)
+		return res, 2, false
+	}
+	for _, w := range res.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	return res, 0, true
+}
+
+func formatOrigin(v config.Value) string {
+	switch v.Origin {
+	case config.OriginDefault:
+		return v.Origin
+	case config.OriginEnv:
+		return v.Origin + ":" + v.Source
+	default:
+		return v.Origin + ":" + displayPath(v.Source)
+	}
+}
+
+func runConfigGet(args []string) int {
+	opts, pos, code, ok := parseConfigFlags("get", args, configValueFlags)
+	if !ok {
+		return code
+	}
+	if len(pos) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: pacto config get <key> [--show-origin] [--root <path>] [--config <path>]")
+		return 2
+	}
+	res, code, ok := resolveConfig(opts)
+	if !ok {
+		return code
+	}
+	v, found := res.Values[pos[0]]
+	if !found {
+		fmt.Fprintf(os.Stderr, "unknown config key: %s (allowed: %s)\n", pos[0], strings.Join(config.Keys, ", "))
+		return 2
+	}
+	if opts.showOrigin {
+		fmt.Printf("%s\t%s\n", formatOrigin(v), v.Value)
+		return 0
+	}
+	fmt.Println(v.Value)
+	return 0
+}
+
+func runConfigList(args []string) int {
+	opts, pos, code, ok := parseConfigFlags("list", args, configValueFlags)
+	if !ok {
+		return code
+	}
+	if len(pos) > 0 {
+		fmt.Fprintln(os.Stderr, "config list does not accept positional args")
+		return 2
+	}
+	res, code, ok := resolveConfig(opts)
+	if !ok {
+		return code
+	}
+	values := make([]config.Value, 0, len(config.Keys))
+	for _, k := range config.Keys {
+		values = append(values, res.Values[k])
+	}
+	switch strings.ToLower(strings.TrimSpace(opts.format)) {
+	case "json":
+		b, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "render config: %v\n", err)
+			return 3
+		}
+		fmt.Println(string(b))
+	case "table":
+		for _, v := range values {
+			if opts.showOrigin {
+				fmt.Printf("%s\t%s=%s\n", formatOrigin(v), v.Key, v.Value)
+				continue
+			}
+			fmt.Printf("%s=%s\n", v.Key, v.Value)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "invalid --format value %q (allowed: table|json)\n", opts.format)
+		return 2
+	}
+	return 0
+}
+
+func runConfigSet(args []string) int {
+	opts, pos, code, ok := parseConfigFlags("set", args, configValueFlags)
+	if !ok {
+		return code
+	}
+	if len(pos) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: pacto config set <key> <value> [--scope user|project|engine] [--root <path>] [--config <path>]")
+		return 2
+	}
+	if _, err := config.Normalize(pos[0], pos[1]); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 2
+	}
+	var path string
+	switch opts.scope {
+	case config.OriginUser:
+		path = config.UserPath()
+		if path == "" {
+			fmt.Fprintln(os.Stderr, "cannot locate the user config directory; set XDG_CONFIG_HOME")
+			return 2
+		}
+	case config.OriginProject:
+		path = config.ProjectPath(opts.root)
+		if path == "" {
+			_, projectRoot, found := resolvePlanRootFrom(opts.root)
+			if !found {
+				fmt.Fprintf(os.Stderr, "no pacto workspace found from %s; run pacto init or use --scope user\n", displayPath(opts.root))
+				return 2
+			}
+			path = filepath.Join(projectRoot, ".pacto", "config.yaml")
+		}
+	case config.OriginEngine:
+		if pos[0] == "ui.language" {
+			fmt.Fprintln(os.Stderr, "ui.language cannot be set in .pacto-engine.yaml; use --scope user|project")
+			return 2
+		}
+		path = config.EnginePath(opts.configPath, opts.root)
+	default:
+		fmt.Fprintf(os.Stderr, "invalid --scope value %q (allowed: user|project|engine)\n", opts.scope)
+		return 2
+	}
+	if err := config.Set(path, pos[0], pos[1]); err != nil {
+		fmt.Fprintf(os.Stderr, "write %s: %v\n", displayPath(path), err)
+		return 3
+	}
+	fmt.Println(pathLine("updated", path))
+	if name := config.EnvName(pos[0]); strings.TrimSpace(os.Getenv(name)) != "" {
+		fmt.Fprintf(os.Stderr, "warning: %s is set and overrides this value\n", name)
+	}
+	return 0
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunConfigSetGetAndShowOrigin(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if code := RunInit([]string{"--root", root, "--no-interactive", "--no-install"}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	oldWD, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(oldWD) }()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = captureOutput(t, func() {
+		if code := Run([]string{"config", "set", "fail_on", "partial"}); code != 0 {
+			t.Fatalf("config set returned %d", code)
+		}
+		if code := Run([]string{"config", "set", "mode", "strict", "--scope", "user"}); code != 0 {
+			t.Fatalf("config set --scope user returned %d", code)
+		}
+	})
+	b, err := os.ReadFile(filepath.Join(root, ".pacto", "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "fail_on: partial") || !strings.Contains(string(b), "language:") {
+		t.Fatalf("expected fail_on merged into project config, got %q", b)
+	}
+
+	t.Setenv("PACTO_FORMAT", "json")
+	stdout, _ := captureOutput(t, func() {
+		if code := Run([]string{"config", "list", "--show-origin"}); code != 0 {
+			t.Fatalf("config list returned %d", code)
+		}
+	})
+	for _, want := range []string{
+		"project:.pacto/config.yaml\tfail_on=partial\n",
+		"user:",
+		"\tmode=strict\n",
+		"env:PACTO_FORMAT\tformat=json\n",
+		"default\tstate=all\n",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("expected %q in list output, got %q", want, stdout)
+		}
+	}
+
+	stdout, _ = captureOutput(t, func() {
+		if code := Run([]string{"config", "get", "fail_on"}); code != 0 {
+			t.Fatalf("config get returned %d", code)
+		}
+	})
+	if stdout != "partial\n" {
+		t.Fatalf("config get = %q, want partial", stdout)
+	}
+
+	_, stderr := captureOutput(t, func() {
+		if code := Run([]string{"config", "set", "fail_on", "sometimes"}); code != 2 {
+			t.Fatalf("config set with invalid value returned %d, want 2", code)
+		}
+	})
+	if !strings.Contains(stderr, "allowed: none|unverified|partial|blocked") {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+}
+
+func TestRunConfigSetRelativeRepoRootResolvesAgainstProject(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if code := RunInit([]string{"--root", root, "--no-interactive", "--no-install"}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	writeTestPlan(t, filepath.Join(root, ".pacto", "plans"), "current", "auth", "# auth\n", "Status: In Progress\n- `src/auth.go`\n")
+	if err := os.MkdirAll(filepath.Join(root, "src"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "src", "auth.go"), []byte("package src\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	oldWD, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(oldWD) }()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _ := captureOutput(t, func() {
+		if code := Run([]string{"config", "set", "repo_root", "."}); code != 0 {
+			t.Fatalf("config set returned %d", code)
+		}
+		if code := Run([]string{"config", "get", "repo_root"}); code != 0 {
+			t.Fatalf("config get returned %d", code)
+		}
+	})
+	if !strings.HasSuffix(stdout, root+"\n") {
+		t.Fatalf("expected repo_root %s, got %q", root, stdout)
+	}
+
+	stdout, _ = captureOutput(t, func() {
+		if code := RunStatus([]string{"--format", "json"}); code != 0 {
+			t.Fatalf("RunStatus returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, `"repo_root": "`+root+`"`) || !strings.Contains(stdout, `"result": "verified"`) {
+		t.Fatalf("expected status to verify against %s, got %q", root, stdout)
+	}
+}
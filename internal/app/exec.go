@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"pacto/internal/fsutil"
 	"pacto/internal/ui"
 )
 
@@ -44,16 +45,6 @@ func RunExec(args []string) int {
 
 	state := strings.ToLower(strings.TrimSpace(pos[0]))
 	slug := strings.TrimSpace(pos[1])
-	if !isValidState(state) {
-		fmt.Fprintf(os.Stderr, tr(lang, "invalid state %q (allowed: current|to-implement|done|outdated)\n", "estado inválido %q (permitidos: current|to-implement|done|outdated)\n"), state)
-		return 2
-	}
-	if state != "current" {
-		fmt.Fprintf(os.Stderr, tr(lang, "exec only supports state %q\n", "exec solo soporta el estado %q\n"), "current")
-		fmt.Fprintf(os.Stderr, tr(lang, "next action: move the plan to current, then retry exec\n", "siguiente acción: mueve el plan a current y vuelve a intentar exec\n"))
-		fmt.Fprintf(os.Stderr, tr(lang, "trigger: pacto move %s %s current\n", "comando: pacto move %s %s current\n"), state, slug)
-		return 2
-	}
 	if !slugRe.MatchString(slug) {
 		fmt.Fprintf(os.Stderr, "invalid slug %q (use lowercase letters, numbers, dashes)\n", slug)
 		return 2
@@ -64,6 +55,20 @@ func RunExec(args []string) int {
 		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
 		return 2
 	}
+	sm, code, ok := loadStateMachine(plansRoot)
+	if !ok {
+		return code
+	}
+	if !sm.Has(state) {
+		fmt.Fprintf(os.Stderr, tr(lang, "invalid state %q (allowed: %s)\n", "estado inválido %q (permitidos: %s)\n"), state, sm.AllowedList())
+		return 2
+	}
+	if active := sm.Active().ID; state != active {
+		fmt.Fprintf(os.Stderr, tr(lang, "exec only supports state %q\n", "exec solo soporta el estado %q\n"), active)
+		fmt.Fprintf(os.Stderr, tr(lang, "next action: move the plan to %s, then retry exec\n", "siguiente acción: mueve el plan a %s y vuelve a intentar exec\n"), active)
+		fmt.Fprintf(os.Stderr, tr(lang, "trigger: pacto move %s %s %s\n", "comando: pacto move %s %s %s\n"), state, slug, active)
+		return 2
+	}
 
 	ref, err := resolvePlanRef(plansRoot, state, slug)
 	if err != nil {
@@ -71,8 +76,14 @@ func RunExec(args []string) int {
 		return 2
 	}
 
+	lock, code, ok := lockWorkspace(plansRoot, lang)
+	if !ok {
+		return code
+	}
+	defer lock.Release()
+
 	planPath := ref.PlanDocs[0]
-	orig, err := os.ReadFile(planPath)
+	orig, snap, err := fsutil.ReadSnapshot(planPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "read plan doc: %v\n", err)
 		return 3
@@ -121,9 +132,11 @@ func RunExec(args []string) int {
 		return 0
 	}
 
-	if err := os.WriteFile(planPath, []byte(updated), 0o664); err != nil {
-		fmt.Fprintf(os.Stderr, "write plan doc: %v\n", err)
-		return 3
+	rec := newJournal(plansRoot, "exec", args)
+	defer commitJournal(rec, fmt.Sprintf("%s/%s: %s", state, slug, strings.Join(actions, ", ")))
+	recordTransition(state, state, slug)
+	if err := rec.WriteChecked(snap, []byte(updated), 0o664); err != nil {
+		return reportWriteError("write plan doc", err, lang)
 	}
 
 	fmt.Println(ui.ActionHeader(tr(lang, "Executed Plan", "Plan ejecutado"), state+"/"+slug))
@@ -140,7 +153,7 @@ func parseExecArgs(args []string) (execOptions, []string, int, bool) {
 	fs.SetOutput(os.Stderr)
 	fs.Usage = func() {
 		fmt.Fprintln(os.Stderr, "Usage:")
-		fmt.Fprintln(os.Stderr, "  pacto exec <current|to-implement|done|outdated> <slug> [--root <path>] [--step <phase.task>] [--note <text>] [--blocker <text>] [--evidence <claim>] [--dry-run]")
+		fmt.Fprintln(os.Stderr, "  pacto exec <state> <slug> [--root <path>] [--step <phase.task>] [--note <text>] [--blocker <text>] [--evidence <claim>] [--dry-run]")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Options:")
 		fs.PrintDefaults()
@@ -207,7 +220,7 @@ func resolvePlanRef(plansRoot, state, slug string) (planRef struct {
 	Readme   string
 	PlanDocs []string
 }, err error) {
-	dir := filepath.Join(plansRoot, state, slug)
+	dir := filepath.Join(stateMachine(plansRoot).Dir(plansRoot, state), slug)
 	readme := filepath.Join(dir, "README.md")
 	if _, err := os.Stat(readme); err != nil {
 		return planRef, fmt.Errorf("plan not found: %s/%s", state, slug)
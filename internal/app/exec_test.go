@@ -1,6 +1,7 @@
 package app
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -125,6 +126,40 @@ func TestRunExecRejectsNonCurrentState(t *testing.T) {
 	}
 }
 
+func TestRunExecUsesConfiguredActiveState(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	cfgPath := filepath.Join(root, ".pacto", "config.yaml")
+	cfg, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg = append(cfg, []byte("states:\n  - id: backlog\n  - id: doing\n    active: true\n  - id: shipped\n")...)
+	if err := os.WriteFile(cfgPath, cfg, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+	plan := "# Plan\n\n## Phase 1: Setup\n\n- [ ] 1.1 a task\n"
+	writeTestPlan(t, plansRoot, "doing", "in-flight", "# In Flight\n", plan)
+	writeTestPlan(t, plansRoot, "backlog", "queued", "# Queued\n", plan)
+
+	captureOutput(t, func() {
+		if code := RunExec([]string{"doing", "in-flight", "--root", root}); code != 0 {
+			t.Fatalf("RunExec on the active state returned %d, want 0", code)
+		}
+	})
+	_, stderr := captureOutput(t, func() {
+		if code := RunExec([]string{"backlog", "queued", "--root", root}); code != 2 {
+			t.Fatalf("RunExec returned %d, want 2", code)
+		}
+	})
+	if !strings.Contains(stderr, `exec only supports state "doing"`) || !strings.Contains(stderr, "trigger: pacto move backlog queued doing") {
+		t.Fatalf("expected the active state in the messages, got %q", stderr)
+	}
+}
+
 func TestRunExecRejectsInvalidStepFormat(t *testing.T) {
 	root := t.TempDir()
 	if code := RunInit([]string{"--root", root}); code != 0 {
@@ -214,3 +249,49 @@ func TestRunExecRejectsPlanWithoutPhaseRefs(t *testing.T) {
 		t.Fatalf("expected phase task contract error, got %q", stderr)
 	}
 }
+
+func TestRunExecConcurrentUpdatesAreNotLost(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+
+	planDir := filepath.Join(root, ".pacto", "plans", "current", "parallel-exec")
+	if err := os.MkdirAll(planDir, 0o775); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(planDir, "README.md"), []byte("# Parallel Exec\n"), 0o664); err != nil {
+		t.Fatal(err)
+	}
+	planPath := filepath.Join(planDir, "PLAN_PARALLEL_EXEC.md")
+	plan := "# Plan: Parallel Exec\n\n## Phase 1: Work\n\n- [ ] 1.1 a\n- [ ] 1.2 b\n- [ ] 1.3 c\n- [ ] 1.4 d\n"
+	if err := os.WriteFile(planPath, []byte(plan), 0o664); err != nil {
+		t.Fatal(err)
+	}
+
+	codes := make(chan int, 4)
+	captureOutput(t, func() {
+		for i := 1; i <= 4; i++ {
+			step := fmt.Sprintf("1.%d", i)
+			go func() {
+				codes <- RunExec([]string{"current", "parallel-exec", "--root", root, "--step", step, "--note", "agent " + step})
+			}()
+		}
+		for i := 0; i < 4; i++ {
+			if code := <-codes; code != 0 {
+				t.Errorf("RunExec returned %d", code)
+			}
+		}
+	})
+
+	b, err := os.ReadFile(planPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+	for i := 1; i <= 4; i++ {
+		if !strings.Contains(got, fmt.Sprintf("- [x] 1.%d", i)) || !strings.Contains(got, fmt.Sprintf("agent 1.%d", i)) {
+			t.Fatalf("expected update for step 1.%d to survive, got %q", i, got)
+		}
+	}
+}
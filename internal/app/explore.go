@@ -1,16 +1,17 @@
 package app
 
 import (
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
-	"sort"
 	"strings"
 	"time"
 
+	"pacto/internal/fsutil"
 	"pacto/internal/i18n"
 	"pacto/internal/ui"
 )
@@ -18,14 +19,29 @@ import (
 var (
 	reCreatedAt = regexp.MustCompile(`(?m)^\*\*(?:Created At|Creado):\*\*\s*(.+)$`)
 	reUpdatedAt = regexp.MustCompile(`(?m)^\*\*(?:Updated At|Actualizado):\*\*\s*(.+)$`)
+	reIdeaStat  = regexp.MustCompile(`(?m)^\*\*(?:Status|Estado):\*\*\s*(.+)$`)
+	rePromoted  = regexp.MustCompile(`(?m)^\*\*(?:Promoted To|Promovida a):\*\*\s*(.+)$`)
 )
 
+const ideaStatusOpen = "open"
+
 type exploreOptions struct {
-	root  string
-	title string
-	note  string
-	list  bool
-	show  string
+	root    string
+	title   string
+	note    string
+	list    bool
+	show    string
+	promote string
+	state   string
+
+	status   string
+	tags     string
+	owner    string
+	priority string
+	tag      string
+	sortBy   string
+	search   string
+	format   string
 }
 
 func RunExplore(args []string) int {
@@ -44,15 +60,19 @@ func RunExplore(args []string) int {
 
 	switch {
 	case opts.list:
-		return runExploreList(root, lang)
+		return runExploreList(root, opts, lang)
+	case strings.TrimSpace(opts.search) != "":
+		return runExploreSearch(root, opts, lang)
 	case strings.TrimSpace(opts.show) != "":
 		return runExploreShow(root, strings.TrimSpace(opts.show), lang)
+	case strings.TrimSpace(opts.promote) != "":
+		return runExplorePromote(root, opts, args, lang)
 	default:
 		if len(pos) != 1 {
 			fmt.Fprintln(os.Stderr, tr(lang, "explore requires a slug, or use --list/--show", "explore requiere un slug, o usar --list/--show"))
 			return 2
 		}
-		return runExploreCreateOrUpdate(root, pos[0], opts.title, opts.note, lang)
+		return runExploreCreateOrUpdate(root, pos[0], opts, lang)
 	}
 }
 
@@ -62,9 +82,11 @@ func parseExploreArgs(args []string) (exploreOptions, []string, int, bool) {
 	fs.SetOutput(os.Stderr)
 	fs.Usage = func() {
 		fmt.Fprintln(os.Stderr, "Usage:")
-		fmt.Fprintln(os.Stderr, "  pacto explore <slug> [--title <title>] [--note <note>] [--root <path>]")
-		fmt.Fprintln(os.Stderr, "  pacto explore --list [--root <path>]")
+		fmt.Fprintln(os.Stderr, "  pacto explore <slug> [--title <title>] [--note <note>] [--status <status>] [--tags <csv>] [--owner <name>] [--priority <p>] [--root <path>]")
+		fmt.Fprintln(os.Stderr, "  pacto explore --list [--status <status>] [--tag <tag>] [--owner <name>] [--priority <p>] [--sort slug|created|updated|priority] [--format table|json] [--root <path>]")
+		fmt.Fprintln(os.Stderr, "  pacto explore --search <query> [--format table|json] [--root <path>]")
 		fmt.Fprintln(os.Stderr, "  pacto explore --show <slug> [--root <path>]")
+		fmt.Fprintln(os.Stderr, "  pacto explore --promote <slug> [--state to-implement] [--title <title>] [--root <path>]")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Options:")
 		fs.PrintDefaults()
@@ -75,6 +97,16 @@ func parseExploreArgs(args []string) (exploreOptions, []string, int, bool) {
 	fs.StringVar(&opts.note, "note", "", "Append exploration note and refresh update timestamp")
 	fs.BoolVar(&opts.list, "list", false, "List saved ideas")
 	fs.StringVar(&opts.show, "show", "", "Show a saved idea by slug")
+	fs.StringVar(&opts.promote, "promote", "", "Create a plan from a saved idea and mark the idea promoted")
+	fs.StringVar(&opts.state, "state", "to-implement", "Plan state used by --promote")
+	fs.StringVar(&opts.status, "status", "", "Idea status to set, or --list filter: open|parked|rejected|promoted")
+	fs.StringVar(&opts.tags, "tags", "", "Comma-separated tags to set on the idea")
+	fs.StringVar(&opts.owner, "owner", "", "Idea owner to set, or --list filter")
+	fs.StringVar(&opts.priority, "priority", "", "Idea priority to set, or --list filter: high|medium|low")
+	fs.StringVar(&opts.tag, "tag", "", "--list filter: only ideas with this tag")
+	fs.StringVar(&opts.sortBy, "sort", "slug", "--list order: slug|created|updated|priority")
+	fs.StringVar(&opts.search, "search", "", "Full-text search over idea titles and notes")
+	fs.StringVar(&opts.format, "format", "table", "Output format for --list/--search: table|json")
 
 	normalizedArgs, normErr := normalizeExploreArgs(args)
 	if normErr != nil {
@@ -103,12 +135,48 @@ func parseExploreArgs(args []string) (exploreOptions, []string, int, bool) {
 		fmt.Fprintln(os.Stderr, "--show does not accept --title")
 		return exploreOptions{}, nil, 2, false
 	}
+	if strings.TrimSpace(opts.promote) != "" && (opts.list || strings.TrimSpace(opts.show) != "" || strings.TrimSpace(opts.note) != "" || len(fs.Args()) > 0) {
+		fmt.Fprintln(os.Stderr, "--promote does not accept a slug argument, --list, --show or --note")
+		return exploreOptions{}, nil, 2, false
+	}
+	if strings.TrimSpace(opts.search) != "" && (opts.list || strings.TrimSpace(opts.show) != "" || strings.TrimSpace(opts.promote) != "" || len(fs.Args()) > 0) {
+		fmt.Fprintln(os.Stderr, "--search cannot be combined with a slug, --list, --show or --promote")
+		return exploreOptions{}, nil, 2, false
+	}
+	if opts.list && strings.TrimSpace(opts.tags) != "" {
+		fmt.Fprintln(os.Stderr, "--list filters by --tag, not --tags")
+		return exploreOptions{}, nil, 2, false
+	}
+	opts.status = strings.ToLower(strings.TrimSpace(opts.status))
+	opts.priority = strings.ToLower(strings.TrimSpace(opts.priority))
+	opts.tag = strings.ToLower(strings.TrimSpace(opts.tag))
+	opts.format = strings.ToLower(strings.TrimSpace(opts.format))
+	if err := validateIdeaStatus(opts.status); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exploreOptions{}, nil, 2, false
+	}
+	if err := validateIdeaPriority(opts.priority); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exploreOptions{}, nil, 2, false
+	}
+	if opts.format != "table" && opts.format != "json" {
+		fmt.Fprintf(os.Stderr, "unsupported format %q (allowed: table|json)\n", opts.format)
+		return exploreOptions{}, nil, 2, false
+	}
 
 	return opts, fs.Args(), 0, true
 }
 
 func normalizeExploreArgs(args []string) ([]string, error) {
-	withValue := map[string]bool{"--root": true, "-root": true, "--title": true, "-title": true, "--note": true, "-note": true, "--show": true, "-show": true}
+	withValue := map[string]bool{
+		"--root": true, "-root": true, "--title": true, "-title": true,
+		"--note": true, "-note": true, "--show": true, "-show": true,
+		"--promote": true, "-promote": true, "--state": true, "-state": true,
+		"--status": true, "-status": true, "--tags": true, "-tags": true,
+		"--owner": true, "-owner": true, "--priority": true, "-priority": true,
+		"--tag": true, "-tag": true, "--sort": true, "-sort": true,
+		"--search": true, "-search": true, "--format": true, "-format": true,
+	}
 	return normalizeArgs(args, withValue)
 }
 
@@ -126,12 +194,17 @@ func resolveExploreRoot(rawRoot string) (string, error) {
 	return cwd, nil
 }
 
-func runExploreCreateOrUpdate(root, slug, title, note string, lang i18n.Language) int {
+func runExploreCreateOrUpdate(root, slug string, opts exploreOptions, lang i18n.Language) int {
+	title, note := opts.title, opts.note
 	slug = strings.TrimSpace(slug)
 	if !slugRe.MatchString(slug) {
 		fmt.Fprintf(os.Stderr, "invalid slug %q (use lowercase letters, numbers, dashes)\n", slug)
 		return 2
 	}
+	if opts.status == "promoted" {
+		fmt.Fprintln(os.Stderr, tr(lang, "use --promote to promote an idea into a plan", "usa --promote para promover una idea a plan"))
+		return 2
+	}
 
 	ideasRoot := filepath.Join(root, ".pacto", "ideas")
 	ideaDir := filepath.Join(ideasRoot, slug)
@@ -152,6 +225,7 @@ func runExploreCreateOrUpdate(root, slug, title, note string, lang i18n.Language
 		if strings.TrimSpace(note) != "" {
 			text = appendExploreNote(text, strings.TrimSpace(note), now)
 		}
+		text = applyIdeaMeta(text, opts, lang)
 		if err := os.WriteFile(readmePath, []byte(text), 0o664); err != nil {
 			fmt.Fprintf(os.Stderr, "write idea readme: %v\n", err)
 			return 3
@@ -164,7 +238,8 @@ func runExploreCreateOrUpdate(root, slug, title, note string, lang i18n.Language
 		return 3
 	}
 
-	if strings.TrimSpace(note) == "" {
+	metaChanged := opts.status != "" || strings.TrimSpace(opts.tags) != "" || strings.TrimSpace(opts.owner) != "" || opts.priority != ""
+	if strings.TrimSpace(note) == "" && !metaChanged {
 		fmt.Println(ui.ActionHeader(tr(lang, "Idea Exists", "Idea existente"), slug))
 		fmt.Println(pathLine("skipped", readmePath))
 		return 0
@@ -175,8 +250,11 @@ func runExploreCreateOrUpdate(root, slug, title, note string, lang i18n.Language
 		fmt.Fprintf(os.Stderr, "read idea readme: %v\n", err)
 		return 3
 	}
-	updated := appendExploreNote(string(b), strings.TrimSpace(note), now)
-	updated = setUpdatedAt(updated, now)
+	updated := string(b)
+	if strings.TrimSpace(note) != "" {
+		updated = appendExploreNote(updated, strings.TrimSpace(note), now)
+	}
+	updated = applyIdeaMeta(setUpdatedAt(updated, now), opts, lang)
 	if err := os.WriteFile(readmePath, []byte(updated), 0o664); err != nil {
 		fmt.Fprintf(os.Stderr, "update idea readme: %v\n", err)
 		return 3
@@ -186,56 +264,80 @@ func runExploreCreateOrUpdate(root, slug, title, note string, lang i18n.Language
 	return 0
 }
 
-func runExploreList(root string, lang i18n.Language) int {
-	ideasRoot := filepath.Join(root, ".pacto", "ideas")
-	ents, err := os.ReadDir(ideasRoot)
+func runExploreList(root string, opts exploreOptions, lang i18n.Language) int {
+	all, err := loadIdeas(root)
 	if err != nil {
-		if os.IsNotExist(err) {
-			fmt.Println(ui.Dim(tr(lang, "No ideas found.", "No se encontraron ideas.")))
-			return 0
-		}
 		fmt.Fprintf(os.Stderr, "read ideas: %v\n", err)
 		return 3
 	}
+	filter := ideaFilter{status: opts.status, tag: opts.tag, owner: strings.TrimSpace(opts.owner), priority: opts.priority}
+	ideas := make([]idea, 0, len(all))
+	for _, it := range all {
+		if filter.match(it) {
+			ideas = append(ideas, it)
+		}
+	}
+	if err := sortIdeas(ideas, strings.ToLower(strings.TrimSpace(opts.sortBy))); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	return printIdeas(ideas, opts.format, lang)
+}
 
-	type row struct {
-		slug      string
-		title     string
-		createdAt string
-		updatedAt string
+func runExploreSearch(root string, opts exploreOptions, lang i18n.Language) int {
+	all, err := loadIdeas(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read ideas: %v\n", err)
+		return 3
 	}
-	rows := make([]row, 0)
-	for _, e := range ents {
-		if !e.IsDir() {
-			continue
+	ideas := make([]idea, 0)
+	for _, it := range all {
+		if m := searchIdea(it, opts.search); len(m) > 0 {
+			it.Matches = m
+			ideas = append(ideas, it)
 		}
-		readmePath := filepath.Join(ideasRoot, e.Name(), "README.md")
-		b, err := os.ReadFile(readmePath)
-		if err != nil {
-			continue
-		}
-		content := string(b)
-		rows = append(rows, row{
-			slug:      e.Name(),
-			title:     extractTitle(content),
-			createdAt: extractStamp(reCreatedAt, content),
-			updatedAt: extractStamp(reUpdatedAt, content),
-		})
 	}
+	return printIdeas(ideas, opts.format, lang)
+}
 
-	if len(rows) == 0 {
+func printIdeas(ideas []idea, format string, lang i18n.Language) int {
+	if format == "json" {
+		if ideas == nil {
+			ideas = []idea{}
+		}
+		enc, _ := json.MarshalIndent(map[string]any{"ideas": ideas}, "", "  ")
+		fmt.Println(string(enc))
+		return 0
+	}
+	if len(ideas) == 0 {
 		fmt.Println(ui.Dim(tr(lang, "No ideas found.", "No se encontraron ideas.")))
 		return 0
 	}
 
-	sort.Slice(rows, func(i, j int) bool { return rows[i].slug < rows[j].slug })
 	fmt.Println(ui.Title(tr(lang, "Ideas", "Ideas")))
 	fmt.Println("")
-	for _, r := range rows {
-		fmt.Printf("%s\n", ui.Bullet(r.slug))
-		fmt.Printf("  %s: %s\n", tr(lang, "title", "título"), r.title)
-		fmt.Printf("  %s: %s\n", tr(lang, "created", "creado"), r.createdAt)
-		fmt.Printf("  %s: %s\n", tr(lang, "updated", "actualizado"), r.updatedAt)
+	for _, it := range ideas {
+		status := it.Status
+		if it.PromotedTo != "" {
+			status += " -> " + it.PromotedTo
+		}
+		fmt.Printf("%s\n", ui.Bullet(it.Slug))
+		fmt.Printf("  %s: %s\n", tr(lang, "title", "título"), it.Title)
+		fmt.Printf("  %s: %s\n", tr(lang, "created", "creado"), it.CreatedAt)
+		fmt.Printf("  %s: %s\n", tr(lang, "updated", "actualizado"), it.UpdatedAt)
+		fmt.Printf("  %s: %s\n", tr(lang, "status", "estado"), status)
+		if len(it.Tags) > 0 {
+			fmt.Printf("  %s: %s\n", tr(lang, "tags", "etiquetas"), strings.Join(it.Tags, ", "))
+		}
+		if it.Owner != "" {
+			fmt.Printf("  %s: %s\n", tr(lang, "owner", "responsable"), it.Owner)
+		}
+		if it.Priority != "" {
+			fmt.Printf("  %s: %s\n", tr(lang, "priority", "prioridad"), it.Priority)
+		}
+		for _, m := range it.Matches {
+			fmt.Printf("  %s %s\n", ui.Dim(tr(lang, "match:", "coincidencia:")), m)
+		}
 	}
 	return 0
 }
@@ -262,6 +364,17 @@ func runExploreShow(root, slug string, lang i18n.Language) int {
 	fmt.Printf("%s: %s\n", tr(lang, "Title", "Título"), extractTitle(content))
 	fmt.Printf("%s: %s\n", tr(lang, "Created At", "Creado"), extractStamp(reCreatedAt, content))
 	fmt.Printf("%s: %s\n", tr(lang, "Updated At", "Actualizado"), extractStamp(reUpdatedAt, content))
+	it := parseIdea(slug, readmePath, content)
+	fmt.Printf("%s: %s\n", tr(lang, "Status", "Estado"), ideaStatusLine(content))
+	if len(it.Tags) > 0 {
+		fmt.Printf("%s: %s\n", tr(lang, "Tags", "Etiquetas"), strings.Join(it.Tags, ", "))
+	}
+	if it.Owner != "" {
+		fmt.Printf("%s: %s\n", tr(lang, "Owner", "Responsable"), it.Owner)
+	}
+	if it.Priority != "" {
+		fmt.Printf("%s: %s\n", tr(lang, "Priority", "Prioridad"), it.Priority)
+	}
 	return 0
 }
 
@@ -324,3 +437,167 @@ func extractStamp(re *regexp.Regexp, content string) string {
 	}
 	return "-"
 }
+
+func ideaStatus(content string) string {
+	if m := reIdeaStat.FindStringSubmatch(content); len(m) == 2 {
+		if st := strings.ToLower(strings.TrimSpace(m[1])); st != "" {
+			return st
+		}
+	}
+	return ideaStatusOpen
+}
+
+// ideaStatusLine renders the status plus the promoted plan ref when present.
+func ideaStatusLine(content string) string {
+	st := ideaStatus(content)
+	if ref := promotedRef(content); ref != "" {
+		return st + " -> " + ref
+	}
+	return st
+}
+
+// promotedRef returns the plan ref ("state/slug") linked from the idea's
+// **Promoted To:** line.
+func promotedRef(content string) string {
+	v := firstMatch(rePromoted, content)
+	if !strings.HasPrefix(v, "[") {
+		return ""
+	}
+	if end := strings.Index(v, "]"); end > 1 {
+		return v[1:end]
+	}
+	return ""
+}
+
+// ideaNotes returns the bullets of the idea's Notes section.
+func ideaNotes(content string) []string {
+	out := make([]string, 0)
+	in := false
+	for _, ln := range strings.Split(content, "\n") {
+		t := strings.TrimSpace(ln)
+		if strings.HasPrefix(t, "## ") {
+			in = t == "## Notes" || t == "## Notas"
+			continue
+		}
+		if in && strings.HasPrefix(t, "- ") {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func runExplorePromote(root string, opts exploreOptions, args []string, lang i18n.Language) int {
+	slug := strings.TrimSpace(opts.promote)
+	if !slugRe.MatchString(slug) {
+		fmt.Fprintf(os.Stderr, "invalid slug %q (use lowercase letters, numbers, dashes)\n", slug)
+		return 2
+	}
+	ideaReadme := filepath.Join(root, ".pacto", "ideas", slug, "README.md")
+	b, ideaSnap, err := fsutil.ReadSnapshot(ideaReadme)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "idea not found: %s\n", slug)
+			return 2
+		}
+		fmt.Fprintf(os.Stderr, "read idea: %v\n", err)
+		return 3
+	}
+	content := string(b)
+	if ideaStatus(content) == "promoted" {
+		fmt.Fprintf(os.Stderr, "idea already promoted: %s\n", ideaStatusLine(content))
+		return 2
+	}
+
+	plansRoot, ok := resolvePlanRoot(root)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "resolve root: could not resolve plans root from %s (expected .pacto/plans)\n", root)
+		return 2
+	}
+	title := strings.TrimSpace(opts.title)
+	if title == "" {
+		title = extractTitle(content)
+	}
+	state := strings.ToLower(strings.TrimSpace(opts.state))
+	req, code, ok := buildNewRequest(newOptions{root: plansRoot, title: title, owner: "Platform Team"}, state, slug, true)
+	if !ok {
+		return code
+	}
+	backLink, err := filepath.Rel(req.planDir, ideaReadme)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "link idea: %v\n", err)
+		return 3
+	}
+	var desc strings.Builder
+	desc.WriteString(fmt.Sprintf(tr(lang, "Promoted from idea [%s](%s).\n\n", "Promovido desde la idea [%s](%s).\n\n"), slug, filepath.ToSlash(backLink)))
+	desc.WriteString(tr(lang, "## Context\n\n", "## Contexto\n\n"))
+	notes := ideaNotes(content)
+	if len(notes) == 0 {
+		notes = []string{tr(lang, "- No notes recorded.", "- Sin notas registradas.")}
+	}
+	desc.WriteString(strings.Join(notes, "\n"))
+	req.description = desc.String()
+
+	lock, code, ok := lockWorkspace(plansRoot, lang)
+	if !ok {
+		return code
+	}
+	defer lock.Release()
+	ref := req.state + "/" + req.slug
+	rec := newJournal(plansRoot, "explore", args)
+	defer commitJournal(rec, "promoted idea "+slug+" to "+ref)
+	if code := createPlanScaffold(rec, req); code != 0 {
+		return code
+	}
+	if err := updateRootIndex(rec, plansRoot, req.state, req.slug, req.title, req.date, lang); err != nil {
+		return reportWriteError("update root README", err, lang)
+	}
+
+	forward, err := filepath.Rel(filepath.Dir(ideaReadme), req.readmePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "link plan: %v\n", err)
+		return 3
+	}
+	now := time.Now().Format("2006-01-02 15:04")
+	content = markIdeaPromoted(content, ref, filepath.ToSlash(forward), now, lang)
+	if err := rec.WriteChecked(ideaSnap, []byte(content), 0o664); err != nil {
+		return reportWriteError("update idea readme", err, lang)
+	}
+
+	fmt.Println(ui.ActionHeader(tr(lang, "Promoted Idea", "Idea promovida"), slug+" -> "+ref))
+	fmt.Println(pathLine("created", req.readmePath))
+	fmt.Println(pathLine("created", req.planPath))
+	fmt.Println(pathLine("updated", filepath.Join(plansRoot, "README.md")))
+	fmt.Println(pathLine("updated", ideaReadme))
+	return 0
+}
+
+func markIdeaPromoted(content, ref, link, now string, lang i18n.Language) string {
+	content = appendExploreNote(content, tr(lang, "Promoted to plan ", "Promovida al plan ")+ref+".", now)
+	content = setUpdatedAt(content, now)
+	meta := readIdeaMeta(content)
+	meta.status = "promoted"
+	meta.promotedTo = "[" + ref + "](" + link + ")"
+	return writeIdeaMeta(content, meta, lang)
+}
+
+// applyIdeaMeta writes the metadata flags of a create/update run; ideas
+// without a status become open.
+func applyIdeaMeta(content string, opts exploreOptions, lang i18n.Language) string {
+	meta := readIdeaMeta(content)
+	if opts.status != "" {
+		meta.status = opts.status
+	}
+	if meta.status == "" {
+		meta.status = ideaStatusOpen
+	}
+	if strings.TrimSpace(opts.tags) != "" {
+		meta.tags = splitTags(opts.tags)
+	}
+	if v := strings.TrimSpace(opts.owner); v != "" {
+		meta.owner = v
+	}
+	if opts.priority != "" {
+		meta.priority = opts.priority
+	}
+	return writeIdeaMeta(content, meta, lang)
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -76,3 +77,130 @@ func TestRunExploreListAndShow(t *testing.T) {
 		t.Fatalf("expected show output, got %q", stdout)
 	}
 }
+
+func TestRunExplorePromoteCreatesPlanAndMarksIdea(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	captureOutput(t, func() {
+		if code := RunExplore([]string{"cache-layer", "--title", "Cache Layer", "--note", "measure hit ratio first", "--root", root}); code != 0 {
+			t.Fatalf("RunExplore create returned %d", code)
+		}
+		if code := RunExplore([]string{"--promote", "cache-layer", "--root", root}); code != 0 {
+			t.Fatalf("RunExplore promote returned %d", code)
+		}
+	})
+
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+	b, err := os.ReadFile(filepath.Join(plansRoot, "to-implement", "cache-layer", "README.md"))
+	if err != nil {
+		t.Fatalf("expected promoted plan: %v", err)
+	}
+	plan := string(b)
+	if !strings.Contains(plan, "# Cache Layer") || !strings.Contains(plan, "## Context") || !strings.Contains(plan, "measure hit ratio first") {
+		t.Fatalf("expected title and context carried over, got %q", plan)
+	}
+	if !strings.Contains(plan, "(../../../ideas/cache-layer/README.md)") {
+		t.Fatalf("expected back-link to idea, got %q", plan)
+	}
+	idx, err := os.ReadFile(filepath.Join(plansRoot, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(idx), "./to-implement/cache-layer/") {
+		t.Fatalf("expected index link, got %q", string(idx))
+	}
+
+	b, err = os.ReadFile(filepath.Join(root, ".pacto", "ideas", "cache-layer", "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	idea := string(b)
+	if !strings.Contains(idea, "**Status:** promoted") || !strings.Contains(idea, "[to-implement/cache-layer](../../plans/to-implement/cache-layer/README.md)") {
+		t.Fatalf("expected idea marked promoted with forward link, got %q", idea)
+	}
+
+	stdout, _ := captureOutput(t, func() {
+		if code := RunExplore([]string{"--list", "--root", root}); code != 0 {
+			t.Fatalf("RunExplore list returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, "promoted -> to-implement/cache-layer") {
+		t.Fatalf("expected promotion status in list, got %q", stdout)
+	}
+
+	_, stderr := captureOutput(t, func() {
+		if code := RunExplore([]string{"--promote", "cache-layer", "--root", root}); code != 2 {
+			t.Fatalf("second promote returned %d, want 2", code)
+		}
+	})
+	if !strings.Contains(stderr, "already promoted") {
+		t.Fatalf("expected already promoted error, got %q", stderr)
+	}
+}
+
+func TestRunExploreMetadataFiltersAndSearch(t *testing.T) {
+	root := t.TempDir()
+	captureOutput(t, func() {
+		if code := RunExplore([]string{"cache-layer", "--note", "Redis caching for the API", "--tags", "perf,Backend", "--priority", "low", "--owner", "ana", "--root", root}); code != 0 {
+			t.Fatalf("RunExplore cache-layer returned %d", code)
+		}
+		if code := RunExplore([]string{"query-plan", "--note", "Index hot tables", "--tags", "perf", "--priority", "high", "--root", root}); code != 0 {
+			t.Fatalf("RunExplore query-plan returned %d", code)
+		}
+		if code := RunExplore([]string{"dark-mode", "--status", "parked", "--tags", "ui", "--root", root}); code != 0 {
+			t.Fatalf("RunExplore dark-mode returned %d", code)
+		}
+	})
+
+	b, err := os.ReadFile(filepath.Join(root, ".pacto", "ideas", "cache-layer", "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "**Status:** open  \n**Tags:** backend, perf  \n**Owner:** ana  \n**Priority:** low") {
+		t.Fatalf("expected metadata block, got %q", string(b))
+	}
+
+	stdout, _ := captureOutput(t, func() {
+		if code := RunExplore([]string{"--list", "--tag", "perf", "--sort", "priority", "--format", "json", "--root", root}); code != 0 {
+			t.Fatalf("RunExplore list returned %d", code)
+		}
+	})
+	var out struct {
+		Ideas []idea `json:"ideas"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("invalid json %q: %v", stdout, err)
+	}
+	if len(out.Ideas) != 2 || out.Ideas[0].Slug != "query-plan" || out.Ideas[1].Slug != "cache-layer" {
+		t.Fatalf("expected perf ideas sorted by priority, got %+v", out.Ideas)
+	}
+
+	stdout, _ = captureOutput(t, func() {
+		if code := RunExplore([]string{"--list", "--status", "parked", "--root", root}); code != 0 {
+			t.Fatalf("RunExplore list parked returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, "dark-mode") || strings.Contains(stdout, "cache-layer") {
+		t.Fatalf("expected only parked ideas, got %q", stdout)
+	}
+
+	stdout, _ = captureOutput(t, func() {
+		if code := RunExplore([]string{"--search", "redis api", "--root", root}); code != 0 {
+			t.Fatalf("RunExplore search returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, "cache-layer") || !strings.Contains(stdout, "Redis caching for the API") || strings.Contains(stdout, "query-plan") {
+		t.Fatalf("expected search match in cache-layer only, got %q", stdout)
+	}
+
+	_, stderr := captureOutput(t, func() {
+		if code := RunExplore([]string{"dark-mode", "--status", "promoted", "--root", root}); code != 2 {
+			t.Fatalf("manual promoted status returned %d, want 2", code)
+		}
+	})
+	if !strings.Contains(stderr, "--promote") {
+		t.Fatalf("expected hint to use --promote, got %q", stderr)
+	}
+}
@@ -5,16 +5,18 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"pacto/internal/model"
 	"pacto/internal/plugins"
 )
 
 func shouldRunGuardrails(cmd string, args []string) bool {
-	if wantsHelp(args) {
+	if wantsHelp(args) || os.Getenv(plugins.InHookEnv) != "" {
 		return false
 	}
 	switch cmd {
-	case "init", "new", "move", "install", "update":
+	case "init", "new", "move", "install", "update", "undo", "archive", "unarchive", "rename", "split", "merge":
 		if hasBoolFlag(args, "--dry-run") {
 			return false
 		}
@@ -22,7 +24,7 @@ func shouldRunGuardrails(cmd string, args []string) bool {
 	case "exec":
 		return !hasBoolFlag(args, "--dry-run")
 	case "explore":
-		if hasBoolFlag(args, "--list") || hasStringFlag(args, "--show") {
+		if hasBoolFlag(args, "--list") || hasStringFlag(args, "--show") || hasStringFlag(args, "--search") {
 			return false
 		}
 		return true
@@ -74,19 +76,42 @@ func findProjectRootForPlugins(cwd string) (string, bool) {
 	return "", false
 }
 
-func runGuardrailsIfNeeded(cmd string, args []string, allow map[string]bool, verbose bool) (int, bool) {
-	if !shouldRunGuardrails(cmd, args) {
-		return 0, false
-	}
+// guardrailAllow holds the --allow-guardrail ids of the running command for
+// guardrails evaluated inside it, such as status report hooks.
+var guardrailAllow map[string]bool
+
+// commandOutcome collects what the running command changed so post and
+// onError hooks can see it.
+var commandOutcome plugins.Outcome
+
+func recordTouched(paths []string) {
+	commandOutcome.Touched = append(commandOutcome.Touched, paths...)
+}
+
+func recordTransition(fromState, toState, slug string) {
+	commandOutcome.FromState = fromState
+	commandOutcome.ToState = toState
+	commandOutcome.Slug = slug
+}
+
+func loadGuardrailPlugins() (string, []plugins.Plugin, []error) {
 	cwd, err := filepath.Abs(".")
 	if err != nil {
-		return 0, false
+		return "", nil, nil
 	}
 	projectRoot, ok := findProjectRootForPlugins(cwd)
 	if !ok {
-		return 0, false
+		return "", nil, nil
 	}
 	active, errs := plugins.LoadActive(projectRoot)
+	return projectRoot, active, errs
+}
+
+func runGuardrailsIfNeeded(cmd string, args []string, allow map[string]bool, verbose bool) (int, bool) {
+	if !shouldRunGuardrails(cmd, args) {
+		return 0, false
+	}
+	projectRoot, active, errs := loadGuardrailPlugins()
 	if len(errs) > 0 {
 		for _, e := range errs {
 			fmt.Fprintf(os.Stderr, "plugin error: %v\n", e)
@@ -96,41 +121,260 @@ func runGuardrailsIfNeeded(cmd string, args []string, allow map[string]bool, ver
 	if len(active) == 0 {
 		return 0, false
 	}
-	violations := plugins.EvaluateGuardrails(active, plugins.HookRequest{
+	for _, id := range plugins.Unapproved(projectRoot, active) {
+		fmt.Fprintf(os.Stderr, "warning: plugin %s is enabled without approved permissions; run `pacto plugin enable %s` to review them\n", id, id)
+	}
+	req := newHookRequest(cmd, args, projectRoot, verbose)
+	req.Allow = allow
+	if cmd == "status" {
+		// JSON protocol status guardrails run once the report exists.
+		req.Protocol = plugins.ProtocolEnv
+	}
+	results := plugins.RunGuardrails(active, req)
+	auditGuardrails(projectRoot, cmd, args, results)
+	if reportGuardrailResults(results, verbose, true) {
+		fmt.Fprintln(os.Stderr, "Use --allow-guardrail <id> to bypass specific guardrails for this run.")
+		return 3, true
+	}
+	return 0, false
+}
+
+// runPluginCommand dispatches cmd to the active plugin declaring it and
+// reports false when no plugin does.
+func runPluginCommand(cmd string, args []string) (int, bool) {
+	projectRoot, active, _ := loadGuardrailPlugins()
+	entry, ok := plugins.FindCommand(active, cmd)
+	if !ok {
+		return 0, false
+	}
+	if wantsHelp(args) {
+		fmt.Print(HelpForLang(cmd, effectiveLanguage(projectRoot)))
+		return 0, true
+	}
+	req := newHookRequest(cmd, args, projectRoot, hasVerboseArg(args))
+	code, err := plugins.RunCommand(entry, req, os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "plugin command %s (%s): %v\n", cmd, entry.PluginID(), err)
+	}
+	return code, true
+}
+
+// pluginCommandHelp returns the commands of the active plugins of the
+// workspace containing the working directory.
+func pluginCommandHelp() []CommandHelp {
+	_, active, _ := loadGuardrailPlugins()
+	out := make([]CommandHelp, 0)
+	for _, e := range plugins.ListCommands(active) {
+		c := e.Command
+		desc := strings.TrimSpace(c.Description)
+		if desc != "" {
+			desc += " "
+		}
+		out = append(out, CommandHelp{
+			Name:        c.Name,
+			Summary:     c.Summary,
+			Usage:       c.Usage,
+			Description: desc + "(plugin " + e.PluginID() + ")",
+			Examples:    c.Examples,
+		})
+	}
+	return out
+}
+
+// runStatusReportHooks runs the JSON protocol status guardrails with the built
+// report, then merges their annotations and next actions into it.
+func runStatusReportHooks(rep *model.StatusReport, args []string, verbose bool) (int, bool) {
+	if !shouldRunGuardrails("status", args) {
+		return 0, true
+	}
+	projectRoot, active, errs := loadGuardrailPlugins()
+	if len(errs) > 0 || len(active) == 0 {
+		return 0, true
+	}
+	req := newHookRequest("status", args, projectRoot, verbose)
+	req.Allow = guardrailAllow
+	req.Protocol = plugins.ProtocolJSON
+	req.Report = rep
+	results := plugins.RunGuardrails(active, req)
+	auditGuardrails(projectRoot, "status", args, results)
+	if reportGuardrailResults(results, verbose, false) {
+		fmt.Fprintln(os.Stderr, "Use --allow-guardrail <id> to bypass specific guardrails for this run.")
+		return 3, false
+	}
+	plugins.MergeIntoReport(rep, results)
+	return 0, true
+}
+
+// auditGuardrails appends the results to the audit log. A failing log never
+// changes the command's outcome.
+func auditGuardrails(projectRoot, cmd string, args []string, results []plugins.GuardrailViolation) {
+	if err := plugins.AppendAudit(projectRoot, cmd, args, results, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: guardrail audit log: %v\n", err)
+	}
+}
+
+// reportGuardrailResults prints warnings and blocking failures and reports
+// whether any deny was not bypassed with --allow-guardrail.
+func reportGuardrailResults(results []plugins.GuardrailViolation, verbose, printNext bool) bool {
+	blocked := false
+	for _, v := range results {
+		switch {
+		case v.Decision == plugins.DecisionAllow:
+		case v.Decision == plugins.DecisionWarn:
+			fmt.Fprintf(os.Stderr, "guardrail warning: %s\n", v.FullID())
+			if strings.TrimSpace(v.Message) != "" {
+				fmt.Fprintf(os.Stderr, "  %s\n", strings.TrimSpace(v.Message))
+			}
+		case v.Allowed:
+			continue
+		default:
+			blocked = true
+			status := "failed"
+			if v.TimedOut {
+				status = "timed out"
+			}
+			fmt.Fprintf(os.Stderr, "guardrail blocked: %s (%s)\n", v.FullID(), status)
+			if strings.TrimSpace(v.Message) != "" {
+				fmt.Fprintf(os.Stderr, "  %s\n", strings.TrimSpace(v.Message))
+			}
+			if verbose {
+				if strings.TrimSpace(v.Stdout) != "" {
+					fmt.Fprintf(os.Stderr, "  stdout:\n%s\n", v.Stdout)
+				}
+				if strings.TrimSpace(v.Stderr) != "" {
+					fmt.Fprintf(os.Stderr, "  stderr:\n%s\n", v.Stderr)
+				}
+			}
+		}
+		if printNext {
+			printHookNextActions(v)
+		}
+	}
+	return blocked
+}
+
+func printHookNextActions(v plugins.GuardrailViolation) {
+	for _, n := range v.NextActions {
+		if strings.TrimSpace(n.Text) == "" {
+			continue
+		}
+		if n.Plan != "" {
+			fmt.Fprintf(os.Stderr, "  next (%s): %s\n", n.Plan, n.Text)
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "  next: %s\n", n.Text)
+	}
+}
+
+// newHookRequest fills the parsed arguments, resolved plans root and target
+// plan ref sent to JSON protocol hooks.
+func newHookRequest(cmd string, args []string, projectRoot string, verbose bool) plugins.HookRequest {
+	flags, pos := splitHookArgs(args)
+	req := plugins.HookRequest{
 		Command:     cmd,
 		Args:        args,
 		ProjectRoot: projectRoot,
-		Allow:       allow,
 		Verbose:     verbose,
-	})
-	blocked := false
-	for _, v := range violations {
-		if v.Allowed {
+		Flags:       flags,
+		Positionals: pos,
+		Language:    string(effectiveLanguage(projectRoot)),
+	}
+	if cfg, err := plugins.ReadActiveConfig(projectRoot); err == nil {
+		req.Parallelism = cfg.Parallelism
+		req.Budget = time.Duration(cfg.BudgetMS) * time.Millisecond
+	}
+	base := projectRoot
+	r := flags["plans-root"]
+	if r == "" {
+		r = flags["root"]
+	}
+	if r != "" {
+		if abs, err := filepath.Abs(r); err == nil {
+			base = abs
+		}
+	}
+	if plansRoot, ok := resolvePlanRoot(base); ok {
+		req.PlansRoot = plansRoot
+		if len(pos) >= 2 && stateMachine(plansRoot).Has(pos[0]) && slugRe.MatchString(pos[1]) {
+			req.Plans = []string{pos[0] + "/" + pos[1]}
+		}
+	}
+	return req
+}
+
+// hookBoolFlags lists the boolean flags of pacto commands so hook requests can
+// tell them apart from flags that take a value.
+var hookBoolFlags = map[string]bool{
+	"allow-minimal-root": true, "artifacts": true, "check": true, "dry-run": true,
+	"force": true, "include-archive": true, "list": true, "no-enable": true,
+	"no-install": true, "no-interactive": true, "verbose": true, "with-agents": true,
+	"yes": true,
+}
+
+func splitHookArgs(args []string) (map[string]string, []string) {
+	flags := map[string]string{}
+	pos := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "-") || a == "-" {
+			pos = append(pos, a)
 			continue
 		}
-		blocked = true
-		status := "failed"
+		name := strings.TrimLeft(a, "-")
+		if k, v, ok := strings.Cut(name, "="); ok {
+			flags[k] = v
+			continue
+		}
+		if hookBoolFlags[name] || i+1 >= len(args) {
+			flags[name] = "true"
+			continue
+		}
+		flags[name] = args[i+1]
+		i++
+	}
+	return flags, pos
+}
+
+// runPostHooksIfNeeded runs post hooks after a successful command and onError
+// hooks after a failed one. Their failures are reported but never change the
+// command's exit code.
+func runPostHooksIfNeeded(cmd string, args []string, code int, verbose bool) {
+	if !shouldRunGuardrails(cmd, args) {
+		return
+	}
+	projectRoot, active, errs := loadGuardrailPlugins()
+	if len(errs) > 0 || len(active) == 0 {
+		return
+	}
+	phase := plugins.PhasePost
+	if code != 0 {
+		phase = plugins.PhaseOnError
+	}
+	outcome := commandOutcome
+	outcome.ExitCode = code
+	req := newHookRequest(cmd, args, projectRoot, verbose)
+	req.Phase = phase
+	req.Outcome = outcome
+	results := plugins.RunGuardrails(active, req)
+	auditGuardrails(projectRoot, cmd, args, results)
+	for _, v := range results {
+		if v.Decision == plugins.DecisionAllow {
+			printHookNextActions(v)
+			continue
+		}
+		status := fmt.Sprintf("exit %d", v.ExitCode)
 		if v.TimedOut {
 			status = "timed out"
 		}
-		fmt.Fprintf(os.Stderr, "guardrail blocked: %s (%s)\n", v.FullID(), status)
+		fmt.Fprintf(os.Stderr, "warning: %s hook failed: %s (%s)\n", phase, v.FullID(), status)
 		if strings.TrimSpace(v.Message) != "" {
 			fmt.Fprintf(os.Stderr, "  %s\n", strings.TrimSpace(v.Message))
 		}
-		if verbose {
-			if strings.TrimSpace(v.Stdout) != "" {
-				fmt.Fprintf(os.Stderr, "  stdout:\n%s\n", v.Stdout)
-			}
-			if strings.TrimSpace(v.Stderr) != "" {
-				fmt.Fprintf(os.Stderr, "  stderr:\n%s\n", v.Stderr)
-			}
+		if verbose && strings.TrimSpace(v.Stderr) != "" {
+			fmt.Fprintf(os.Stderr, "  stderr:\n%s\n", v.Stderr)
 		}
+		printHookNextActions(v)
 	}
-	if blocked {
-		fmt.Fprintln(os.Stderr, "Use --allow-guardrail <id> to bypass specific guardrails for this run.")
-		return 3, true
-	}
-	return 0, false
 }
 
 func stripAllowGuardrailArg(args []string) ([]string, map[string]bool) {
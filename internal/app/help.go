@@ -33,6 +33,13 @@ func RootHelpLang(lang i18n.Language) string {
 		b.WriteString("  " + padRight(c.Name, 8) + c.Summary + "\n")
 	}
 	b.WriteString("\n")
+	if cmds := pluginCommandHelp(); len(cmds) > 0 {
+		b.WriteString(tr(lang, "Plugin commands:", "Comandos de plugins:") + "\n")
+		for _, c := range cmds {
+			b.WriteString("  " + padRight(c.Name, 8) + c.Summary + "\n")
+		}
+		b.WriteString("\n")
+	}
 	b.WriteString(tr(lang, "Use \"pacto help <command>\" for command details.\n", "Usa \"pacto help <command>\" para ver detalles del comando.\n"))
 	return b.String()
 }
@@ -42,7 +49,7 @@ func HelpFor(name string) string {
 }
 
 func HelpForLang(name string, lang i18n.Language) string {
-	for _, c := range commandCatalog() {
+	for _, c := range append(commandCatalog(), pluginCommandHelp()...) {
 		if c.Name == name {
 			var b strings.Builder
 			b.WriteString(tr(lang, "Command: ", "Comando: ") + c.Name + "\n\n")
@@ -71,38 +78,43 @@ func commandCatalog() []CommandHelp {
 		{
 			Name:        "status",
 			Summary:     "Verify plan status, blockers, and evidence claims.",
-			Usage:       "pacto status [--root <path>] [--repo-root <path>] [--mode compat|strict] [--format table|json] [--fail-on policy]",
-			Description: "Scans plans from plans root, verifies claims against repo root, and renders interactive TUI in terminals. In non-TTY mode, emits table/json report for automation.",
+			Usage:       "pacto status [--root <path> | --workspace <path>] [--repo-root <path>] [--mode compat|strict] [--format table|json] [--fail-on policy] [--recursive]",
+			Description: "Scans plans from plans root, verifies claims against repo root, and renders interactive TUI in terminals. In non-TTY mode, emits table/json report for automation. --recursive merges nested .pacto workspaces, tagging each plan with its workspace and verifying it against that workspace's directory.",
 			Examples: []string{
 				"pacto status",
 				"pacto status # from nested directory",
 				"pacto status --root . --repo-root .",
 				"pacto status --mode strict --format table",
 				"pacto status --format json --fail-on partial",
+				"pacto status --recursive --format json",
 			},
 		},
 		{
 			Name:        "new",
 			Summary:     "Create a new plan scaffold and update root index.",
-			Usage:       "pacto new <current|to-implement|done|outdated> <slug> [--title ...] [--owner ...] [--root <path>] [--allow-minimal-root]",
-			Description: "Generates plan folder with README + PLAN file from template and updates root README counters, links, and last update date. If --root is omitted, auto-discovers from current directory and parents.",
+			Usage:       "pacto new <state> <slug> [--title ...] [--owner ...] [--root <path> | --workspace <path>] [--allow-minimal-root]",
+			Description: "Generates plan folder with README + PLAN file from template and updates root README counters, links, and last update date. If --root is omitted, auto-discovers from current directory and parents. --workspace picks a nested workspace relative to the outermost one.",
 			Examples: []string{
 				"pacto new to-implement polling-contactos-v2",
 				"pacto new to-implement polling-contactos-v2 # from nested directory",
 				"pacto new current api-contract-refresh --title \"API Contract Refresh\" --owner \"Backend Team\"",
 				"pacto new to-implement sandbox --root ./samples/mock-pacto-repo --allow-minimal-root",
+				"pacto new to-implement rate-limits --workspace services/api",
 			},
 		},
 		{
 			Name:        "explore",
 			Summary:     "Capture and revisit ideas without implementation.",
-			Usage:       "pacto explore <slug> [--title ...] [--note ...] [--root <path>] | --list | --show <slug>",
-			Description: "Creates and manages idea workspaces in .pacto/ideas with Created At and Updated At timestamps.",
+			Usage:       "pacto explore <slug> [--title ...] [--note ...] [--status ...] [--tags <csv>] [--owner ...] [--priority ...] [--root <path>] | --list [filters] [--sort ...] [--format table|json] | --search <query> | --show <slug> | --promote <slug> [--state to-implement]",
+			Description: "Creates and manages idea workspaces in .pacto/ideas with Created At and Updated At timestamps, a status (open|parked|rejected|promoted), tags, owner and priority. `--list` filters by --status/--tag/--owner/--priority and sorts by slug|created|updated|priority; `--search` matches all terms against titles and notes. `--promote` turns an idea into a plan (notes become its Context) and marks the idea promoted with a link to the plan.",
 			Examples: []string{
 				"pacto explore auth-refresh --title \"Auth refresh ideas\"",
 				"pacto explore auth-refresh --note \"Compare token vs session approach\"",
-				"pacto explore --list",
+				"pacto explore auth-refresh --tags auth,security --priority high --owner platform",
+				"pacto explore --list --tag auth --sort priority --format json",
+				"pacto explore --search \"token session\"",
 				"pacto explore --show auth-refresh",
+				"pacto explore --promote auth-refresh --state current",
 			},
 		},
 		{
@@ -121,12 +133,24 @@ func commandCatalog() []CommandHelp {
 		{
 			Name:        "install",
 			Summary:     "Install Pacto skills and command prompts for AI tools.",
-			Usage:       "pacto install [--tools <all|none|csv>] [--force]",
-			Description: "Generates managed Pacto skills and command files for supported tools (codex,cursor,claude,opencode). If --tools is omitted, tools are auto-detected from project directories.",
+			Usage:       "pacto install [--tools <all|none|csv>] [--force] | --check [--diff] [--tools <all|none|csv>]",
+			Description: "Generates managed Pacto skills and command files for supported tools (codex,cursor,claude,opencode,windsurf,cline,roo,copilot,aider,gemini) and for tools declared in .pacto/adapters/*.yaml. If --tools is omitted, tools are auto-detected from project directories. --check reports missing, outdated and hand-modified artifacts without writing and exits 1 on drift; --diff prints a unified diff. Overrides in .pacto/workflows/<workflow>.yaml are merged into skills and commands, and affected artifacts are flagged. With integrations.liveContext.enabled in .pacto/config.yaml, skills include a size-limited Live Plan Context section that plan-changing commands refresh.",
 			Examples: []string{
 				"pacto install",
 				"pacto install --tools codex,cursor",
 				"pacto install --tools all",
+				"pacto install --tools copilot,gemini",
+				"pacto install --check --diff",
+			},
+		},
+		{
+			Name:        "uninstall",
+			Summary:     "Remove Pacto skills and command prompts for AI tools.",
+			Usage:       "pacto uninstall --tools <all|csv> [--agents] [--dry-run]",
+			Description: "Reverses pacto install: deletes files that only hold a managed block, strips the managed block from files with user content, and prunes directories left empty. --agents also strips the managed hand-off block from root AGENTS.md. Unmanaged files are kept.",
+			Examples: []string{
+				"pacto uninstall --tools cursor --dry-run",
+				"pacto uninstall --tools all --agents",
 			},
 		},
 		{
@@ -146,8 +170,8 @@ func commandCatalog() []CommandHelp {
 		{
 			Name:        "exec",
 			Summary:     "Execute plan tasks and append execution evidence.",
-			Usage:       "pacto exec <current|to-implement|done|outdated> <slug> [--root <path>] [--step <phase.task>] [--note <text>] [--blocker <text>] [--evidence <claim>] [--dry-run]",
-			Description: "Runs guided execution updates on plan markdown artifacts only (no source-code edits). Execution is allowed only for plans in `current` state. Task refs use phase format `<phase>.<task>` (for example, `1.2`).",
+			Usage:       "pacto exec <state> <slug> [--root <path>] [--step <phase.task>] [--note <text>] [--blocker <text>] [--evidence <claim>] [--dry-run]",
+			Description: "Runs guided execution updates on plan markdown artifacts only (no source-code edits). Execution is allowed only for plans in the active state (`current` by default). Task refs use phase format `<phase>.<task>` (for example, `1.2`).",
 			Examples: []string{
 				"pacto exec current improve-auth-flow",
 				"pacto exec current improve-auth-flow --step 1.2 --note \"Validated staging behavior\" --evidence src/auth/flow.go",
@@ -158,26 +182,116 @@ func commandCatalog() []CommandHelp {
 			Name:        "move",
 			Summary:     "Move a plan slice between states.",
 			Usage:       "pacto move <from-state> <slug> <to-state> [--root <path>] [--reason <text>] [--force]",
-			Description: "Performs explicit state transitions (to-implement/current/done/outdated), updates plan README status, and refreshes plans index links/counts.",
+			Description: "Performs explicit state transitions (to-implement/current/done/outdated, or the states declared in `.pacto/config.yaml`), rejects transitions not allowed by the target state's `from` list, updates plan README status, and refreshes plans index links/counts.",
 			Examples: []string{
 				"pacto move to-implement improve-auth-flow current",
 				"pacto move current improve-auth-flow done --reason \"Tasks complete and evidence verified\"",
 			},
 		},
+		{
+			Name:        "rename",
+			Summary:     "Rename a plan slug and rewrite links to it.",
+			Usage:       "pacto rename <state> <old-slug> <new-slug> [--title <text>] [--root <path>] [--dry-run]",
+			Description: "Renames the plan folder and PLAN_<TOPIC> docs, updates the root README link, rewrites references in other plans, and leaves a MOVED.md redirect note in the old folder.",
+			Examples: []string{
+				"pacto rename to-implement auth-v2 auth-token-refresh",
+				"pacto rename current auth-v2 auth-token-refresh --title \"Auth Token Refresh\"",
+			},
+		},
+		{
+			Name:        "split",
+			Summary:     "Move selected phases of a plan into a new plan.",
+			Usage:       "pacto split <state> <slug> <new-slug> [new-state] --phases <list> [--title <text>] [--root <path>] [--dry-run]",
+			Description: "Moves the selected `## Phase N` sections into a new plan, renumbers phases and task refs in both plans, leaves a redirect note where the phases were, and adds the new plan to the root README.",
+			Examples: []string{
+				"pacto split current auth-refresh auth-refresh-ui --phases 3,4",
+				"pacto split current auth-refresh auth-refresh-ui to-implement --phases 3-4 --title \"Auth Refresh UI\"",
+			},
+		},
+		{
+			Name:        "merge",
+			Summary:     "Merge one plan into another.",
+			Usage:       "pacto merge <state> <slug> <into-state> <into-slug> [--root <path>] [--dry-run]",
+			Description: "Appends the source plan's phases (renumbered) and execution notes, blockers and evidence to the target plan, replaces the source folder with a MOVED.md redirect note, and rewrites links in the root README and other plans.",
+			Examples: []string{
+				"pacto merge to-implement auth-ui current auth-refresh",
+			},
+		},
+		{
+			Name:        "archive",
+			Summary:     "Archive plans into dated archive folders.",
+			Usage:       "pacto archive <state> <slug> [--root <path>] [--reason <text>] [--dry-run] | --older-than <age> [--state done]",
+			Description: "Moves plans into `archive/<YYYY>/<slug>`, records the source state in the plan README, and updates root README counts and the Archive section. With --older-than, archives every plan in --state whose files have not changed for that long (`90d`, `12w`, `720h`).",
+			Examples: []string{
+				"pacto archive done improve-auth-flow",
+				"pacto archive --older-than 90d --state done --dry-run",
+				"pacto archive --older-than 12w --state outdated",
+			},
+		},
+		{
+			Name:        "unarchive",
+			Summary:     "Restore an archived plan to a state folder.",
+			Usage:       "pacto unarchive <slug> [--to <state>] [--year <YYYY>] [--root <path>] [--reason <text>]",
+			Description: "Moves an archived plan back to the state it was archived from (or --to), restores its README status, and updates root README links and counts.",
+			Examples: []string{
+				"pacto unarchive improve-auth-flow",
+				"pacto unarchive improve-auth-flow --to to-implement --year 2025",
+			},
+		},
+		{
+			Name:        "undo",
+			Summary:     "Revert the most recent plan mutations from the journal.",
+			Usage:       "pacto undo [--steps N] [--root <path>] [--force] [--dry-run]",
+			Description: "Replays before-images recorded in `.pacto/journal/` by mutating commands (`new`, `exec`, `move`, `rename`, `split`, `merge`, `archive`, `unarchive`) in reverse order. Refuses to run when files changed after the recorded command unless --force is set.",
+			Examples: []string{
+				"pacto undo",
+				"pacto undo --steps 3 --dry-run",
+				"pacto undo --force",
+			},
+		},
+		{
+			Name:        "log",
+			Summary:     "Show the journal of plan mutations.",
+			Usage:       "pacto log [--root <path>] [--limit N] [--format table|json]",
+			Description: "Lists journal entries newest first with command, summary, touched files and whether they were undone.",
+			Examples: []string{
+				"pacto log",
+				"pacto log --limit 5 --format json",
+			},
+		},
 		{
 			Name:        "plugin",
 			Summary:     "Manage local Pacto plugins and activation state.",
-			Usage:       "pacto plugin <list|list-available|install|validate|enable|disable> [options]",
-			Description: "Lists built-in plugins shipped by pacto, installs built-ins into `.pacto/plugins`, validates manifests, and updates enabled plugin IDs in `.pacto/config.yaml`.",
+			Usage:       "pacto plugin <list|list-available|install|upgrade|verify|validate|migrate|schema|report|enable|disable> [options]",
+			Description: "Lists built-in plugins shipped by pacto, installs built-ins, directories or tarballs into `.pacto/plugins` pinned in `.pacto/plugins.lock.json`, verifies and upgrades pinned plugins, validates manifests against their JSON Schema and version/dependency constraints, migrates manifests to `pacto/v1beta1`, shows plugin sandbox permissions and asks for approval on enable, summarizes the guardrail audit log in `.pacto/guardrails.audit.jsonl`, and updates enabled plugin IDs in `.pacto/config.yaml`.",
 			Examples: []string{
 				"pacto plugin list-available",
 				"pacto plugin install git-sync",
+				"pacto plugin install done-requires-verified --yes",
+				"pacto plugin install ./vendor/acme-guardrails",
+				"pacto plugin install acme-guardrails-0.2.0.tar.gz --sha256 <hex>",
+				"pacto plugin verify",
+				"pacto plugin upgrade acme-guardrails --dry-run",
 				"pacto plugin list",
 				"pacto plugin validate",
-				"pacto plugin enable acme-guardrails",
+				"pacto plugin migrate --all --dry-run",
+				"pacto plugin report acme-guardrails --since 7d",
+				"pacto plugin enable acme-guardrails --yes",
 				"pacto plugin disable acme-guardrails",
 			},
 		},
+		{
+			Name:        "config",
+			Summary:     "Show and change layered configuration values.",
+			Usage:       "pacto config get <key> [--show-origin] | set <key> <value> [--scope user|project|engine] | list [--show-origin] [--format table|json]",
+			Description: "Values resolve from built-in defaults, then $XDG_CONFIG_HOME/pacto/config.yaml, project .pacto/config.yaml, .pacto-engine.yaml (or --config), PACTO_* environment variables (e.g. PACTO_FAIL_ON, PACTO_LIMITS_MAX_BLOCKERS) and finally command flags. --show-origin prints the layer and file or variable each value comes from. set writes the project config unless --scope says otherwise.",
+			Examples: []string{
+				"pacto config list --show-origin",
+				"pacto config get fail_on --show-origin",
+				"pacto config set fail_on partial",
+				"pacto config set ui.language es --scope user",
+			},
+		},
 		{
 			Name:        "help",
 			Summary:     "Show root or command-specific help.",
@@ -0,0 +1,273 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"pacto/internal/i18n"
+)
+
+var (
+	reIdeaTags     = regexp.MustCompile(`(?m)^\*\*(?:Tags|Etiquetas):\*\*\s*(.+)$`)
+	reIdeaOwner    = regexp.MustCompile(`(?m)^\*\*(?:Owner|Responsable):\*\*\s*(.+)$`)
+	reIdeaPriority = regexp.MustCompile(`(?m)^\*\*(?:Priority|Prioridad):\*\*\s*(.+)$`)
+
+	ideaStatuses   = []string{"open", "parked", "rejected", "promoted"}
+	ideaPriorities = []string{"high", "medium", "low"}
+)
+
+type idea struct {
+	Slug       string   `json:"slug"`
+	Title      string   `json:"title"`
+	Status     string   `json:"status"`
+	PromotedTo string   `json:"promoted_to,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Owner      string   `json:"owner,omitempty"`
+	Priority   string   `json:"priority,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+	Path       string   `json:"path"`
+	Matches    []string `json:"matches,omitempty"`
+	notes      []string
+}
+
+// ideaMeta holds the header fields written below **Updated At:**.
+type ideaMeta struct {
+	status     string
+	promotedTo string
+	tags       []string
+	owner      string
+	priority   string
+}
+
+func parseIdea(slug, path, content string) idea {
+	it := idea{
+		Slug:      slug,
+		Title:     extractTitle(content),
+		Status:    ideaStatus(content),
+		Tags:      splitTags(firstMatch(reIdeaTags, content)),
+		Owner:     firstMatch(reIdeaOwner, content),
+		Priority:  strings.ToLower(firstMatch(reIdeaPriority, content)),
+		CreatedAt: extractStamp(reCreatedAt, content),
+		UpdatedAt: extractStamp(reUpdatedAt, content),
+		Path:      path,
+		notes:     ideaNotes(content),
+	}
+	it.PromotedTo = promotedRef(content)
+	return it
+}
+
+func loadIdeas(root string) ([]idea, error) {
+	ideasRoot := filepath.Join(root, ".pacto", "ideas")
+	ents, err := os.ReadDir(ideasRoot)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	out := make([]idea, 0, len(ents))
+	for _, e := range ents {
+		if !e.IsDir() {
+			continue
+		}
+		readmePath := filepath.Join(ideasRoot, e.Name(), "README.md")
+		b, err := os.ReadFile(readmePath)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(root, readmePath)
+		if err != nil {
+			rel = readmePath
+		}
+		out = append(out, parseIdea(e.Name(), filepath.ToSlash(rel), string(b)))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Slug < out[j].Slug })
+	return out, nil
+}
+
+func readIdeaMeta(content string) ideaMeta {
+	meta := ideaMeta{
+		promotedTo: firstMatch(rePromoted, content),
+		tags:       splitTags(firstMatch(reIdeaTags, content)),
+		owner:      firstMatch(reIdeaOwner, content),
+		priority:   strings.ToLower(firstMatch(reIdeaPriority, content)),
+	}
+	if reIdeaStat.MatchString(content) {
+		meta.status = ideaStatus(content)
+	}
+	return meta
+}
+
+// writeIdeaMeta replaces the metadata block under **Updated At:** so fields
+// always appear in the same order.
+func writeIdeaMeta(content string, meta ideaMeta, lang i18n.Language) string {
+	lines := make([]string, 0, 5)
+	if meta.status != "" {
+		lines = append(lines, tr(lang, "**Status:** ", "**Estado:** ")+meta.status)
+	}
+	if meta.promotedTo != "" {
+		lines = append(lines, tr(lang, "**Promoted To:** ", "**Promovida a:** ")+meta.promotedTo)
+	}
+	if len(meta.tags) > 0 {
+		lines = append(lines, tr(lang, "**Tags:** ", "**Etiquetas:** ")+strings.Join(meta.tags, ", "))
+	}
+	if meta.owner != "" {
+		lines = append(lines, tr(lang, "**Owner:** ", "**Responsable:** ")+meta.owner)
+	}
+	if meta.priority != "" {
+		lines = append(lines, tr(lang, "**Priority:** ", "**Prioridad:** ")+meta.priority)
+	}
+	for i := range lines {
+		if i < len(lines)-1 {
+			lines[i] += "  "
+		}
+	}
+
+	src := strings.Split(content, "\n")
+	out := make([]string, 0, len(src)+len(lines))
+	for _, ln := range src {
+		t := strings.TrimSpace(ln)
+		if isIdeaMetaLine(t) {
+			continue
+		}
+		if reUpdatedAt.MatchString(t) {
+			if len(lines) > 0 {
+				t += "  "
+			}
+			out = append(out, t)
+			out = append(out, lines...)
+			continue
+		}
+		out = append(out, ln)
+	}
+	return strings.Join(out, "\n")
+}
+
+func isIdeaMetaLine(t string) bool {
+	for _, re := range []*regexp.Regexp{reIdeaStat, rePromoted, reIdeaTags, reIdeaOwner, reIdeaPriority} {
+		if re.MatchString(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateIdeaStatus(v string) error {
+	if v == "" || containsString(ideaStatuses, v) {
+		return nil
+	}
+	return fmt.Errorf("invalid status %q (allowed: %s)", v, strings.Join(ideaStatuses, "|"))
+}
+
+func validateIdeaPriority(v string) error {
+	if v == "" || containsString(ideaPriorities, v) {
+		return nil
+	}
+	return fmt.Errorf("invalid priority %q (allowed: %s)", v, strings.Join(ideaPriorities, "|"))
+}
+
+type ideaFilter struct {
+	status   string
+	tag      string
+	owner    string
+	priority string
+}
+
+func (f ideaFilter) match(it idea) bool {
+	if f.status != "" && it.Status != f.status {
+		return false
+	}
+	if f.tag != "" && !containsString(it.Tags, f.tag) {
+		return false
+	}
+	if f.owner != "" && !strings.EqualFold(it.Owner, f.owner) {
+		return false
+	}
+	if f.priority != "" && it.Priority != f.priority {
+		return false
+	}
+	return true
+}
+
+func sortIdeas(ideas []idea, key string) error {
+	switch key {
+	case "", "slug":
+		sort.SliceStable(ideas, func(i, j int) bool { return ideas[i].Slug < ideas[j].Slug })
+	case "created":
+		sort.SliceStable(ideas, func(i, j int) bool { return ideas[i].CreatedAt > ideas[j].CreatedAt })
+	case "updated":
+		sort.SliceStable(ideas, func(i, j int) bool { return ideas[i].UpdatedAt > ideas[j].UpdatedAt })
+	case "priority":
+		rank := func(p string) int {
+			for i, v := range ideaPriorities {
+				if v == p {
+					return i
+				}
+			}
+			return len(ideaPriorities)
+		}
+		sort.SliceStable(ideas, func(i, j int) bool { return rank(ideas[i].Priority) < rank(ideas[j].Priority) })
+	default:
+		return fmt.Errorf("invalid sort %q (allowed: slug|created|updated|priority)", key)
+	}
+	return nil
+}
+
+// searchIdea returns the title/notes lines containing every query term.
+func searchIdea(it idea, query string) []string {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+	matches := make([]string, 0)
+	for _, ln := range append([]string{it.Title}, it.notes...) {
+		lower := strings.ToLower(ln)
+		all := true
+		for _, t := range terms {
+			if !strings.Contains(lower, t) {
+				all = false
+				break
+			}
+		}
+		if all {
+			matches = append(matches, ln)
+		}
+	}
+	return matches
+}
+
+func splitTags(raw string) []string {
+	out := make([]string, 0)
+	seen := map[string]bool{}
+	for _, p := range strings.Split(raw, ",") {
+		t := strings.ToLower(strings.TrimSpace(p))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func firstMatch(re *regexp.Regexp, content string) string {
+	if m := re.FindStringSubmatch(content); len(m) == 2 {
+		return strings.TrimSpace(m[1])
+	}
+	return ""
+}
+
+func containsString(items []string, v string) bool {
+	for _, it := range items {
+		if it == v {
+			return true
+		}
+	}
+	return false
+}
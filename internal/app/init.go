@@ -13,6 +13,7 @@ import (
 	"pacto/internal/i18n"
 	"pacto/internal/integrations"
 	"pacto/internal/onboarding"
+	"pacto/internal/states"
 	initui "pacto/internal/tui/init"
 	"pacto/internal/ui"
 )
@@ -216,7 +217,7 @@ func parseInitOptions(args []string) (initOptions, int, bool) {
 	fs.BoolVar(&opts.force, "force", false, "Overwrite init-managed files when they already exist")
 	fs.StringVar(&opts.lang, "lang", "", "Output language override: en|es")
 	fs.BoolVar(&opts.noInteractive, "no-interactive", false, "Disable Bubble Tea onboarding and use fallback profile resolution")
-	fs.StringVar(&opts.tools, "tools", "", "Tools to configure during init: all, none, or comma-separated IDs (codex,cursor,claude,opencode)")
+	fs.StringVar(&opts.tools, "tools", "", "Tools to configure during init: all, none, or comma-separated IDs (codex,cursor,claude,opencode,windsurf,cline,roo,copilot,aider,gemini)")
 	fs.BoolVar(&opts.yes, "yes", false, "Auto-approve install preview in interactive mode")
 	fs.BoolVar(&opts.noInstall, "no-install", false, "Skip skill/command installation during init")
 	fs.BoolVar(&opts.dryRun, "dry-run", false, "Show intended actions without writing files")
@@ -273,8 +274,12 @@ func removedInitFlag(args []string) string {
 }
 
 func bootstrapWorkspace(plansRoot, lang string, force bool, created, updated, skipped *[]string) error {
-	for _, st := range []string{"current", "to-implement", "done", "outdated"} {
-		p := filepath.Join(plansRoot, st)
+	sm, err := states.LoadForPlansRoot(plansRoot)
+	if err != nil {
+		return fmt.Errorf("load states: %w", err)
+	}
+	for _, folder := range sm.Folders() {
+		p := filepath.Join(plansRoot, folder)
 		if info, err := os.Stat(p); err == nil {
 			if !info.IsDir() {
 				return fmt.Errorf("state path exists but is not a directory: %s", p)
@@ -289,7 +294,7 @@ func bootstrapWorkspace(plansRoot, lang string, force bool, created, updated, sk
 	}
 
 	workspaceFiles := map[string]string{
-		filepath.Join(plansRoot, "README.md"):               assets.MustTemplateLang(lang, "README.md"),
+		filepath.Join(plansRoot, "README.md"):               ensureStateIndex(assets.MustTemplateLang(lang, "README.md"), sm, i18n.NormalizeLanguage(lang)),
 		filepath.Join(plansRoot, "PACTO.md"):                assets.MustTemplateLang(lang, "PACTO.md"),
 		filepath.Join(plansRoot, "PLANTILLA_PACTO_PLAN.md"): assets.MustTemplateLang(lang, "PLANTILLA_PACTO_PLAN.md"),
 		filepath.Join(plansRoot, "SLASH_COMMANDS.md"):       assets.MustTemplateLang(lang, "SLASH_COMMANDS.md"),
@@ -332,8 +337,9 @@ func writeManagedFile(path, content string, force bool) (created, updated, skipp
 }
 
 func applyInstallPlan(projectRoot string, tools []string, force bool) (created []string, updated []string, skipped []string, failed []string) {
+	live := liveContext(projectRoot)
 	for _, toolID := range tools {
-		results := integrations.GenerateForTool(projectRoot, toolID, force)
+		results := integrations.GenerateForTool(projectRoot, toolID, force, live)
 		for _, r := range results {
 			if r.Err != nil {
 				failed = append(failed, fmt.Sprintf("tool=%s kind=%s workflow=%s err=%v", r.Tool, r.Kind, r.WorkflowID, r.Err))
@@ -422,6 +428,16 @@ func printInitSummary(lang i18n.Language, plansRoot string, profile onboarding.P
 	fmt.Printf("  %s: +%d  ~%d  =%d\n", tr(lang, "Files/Folders", "Archivos/Directorios"), len(created), len(updated), len(skipped))
 	fmt.Printf("  %s: %s\n", tr(lang, "Language", "Idioma"), readableLanguage(lang))
 	fmt.Printf("  %s: %s\n", tr(lang, "Technologies", "Tecnologías"), joinOrNone(append(append([]string{}, profile.Languages...), profile.CustomLanguages...), lang))
+	if len(profile.Frameworks) > 0 {
+		fmt.Printf("  %s: %s\n", tr(lang, "Frameworks", "Frameworks"), strings.Join(profile.Frameworks, ","))
+	}
+	if len(profile.Subprojects) > 0 {
+		paths := make([]string, 0, len(profile.Subprojects))
+		for _, sp := range profile.Subprojects {
+			paths = append(paths, sp.Path)
+		}
+		fmt.Printf("  %s: %s\n", tr(lang, "Subprojects", "Subproyectos"), strings.Join(paths, ","))
+	}
 	fmt.Printf("  %s: %s\n", tr(lang, "Tools", "Herramientas"), joinOrNone(profile.Tools, lang))
 	fmt.Println("")
 
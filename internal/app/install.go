@@ -18,6 +18,7 @@ import (
 	"runtime"
 	"strings"
 
+	"pacto/internal/i18n"
 	"pacto/internal/integrations"
 	"pacto/internal/ui"
 )
@@ -38,6 +39,91 @@ func RunInstall(args []string) int {
 	return runInstallLike("install", args)
 }
 
+func RunUninstall(args []string) int {
+	lang := effectiveLanguage("")
+	fs := flag.NewFlagSet("uninstall", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage:")
+		fmt.Fprintln(os.Stderr, "  pacto uninstall --tools <all|csv> [--agents] [--dry-run]")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Options:")
+		fs.PrintDefaults()
+	}
+	toolsArg := fs.String("tools", "", "Tools to remove artifacts for: all, or comma-separated IDs")
+	agents := fs.Bool("agents", false, "Also strip the managed hand-off block from root AGENTS.md")
+	dryRun := fs.Bool("dry-run", false, "Show what would be removed without writing files")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "%s: %v\n", tr(lang, "parse flags", "error parseando flags"), err)
+		return 2
+	}
+	if len(fs.Args()) > 0 || strings.TrimSpace(*toolsArg) == "" {
+		fs.Usage()
+		return 2
+	}
+
+	cwd, err := filepath.Abs(".")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve cwd: %v\n", err)
+		return 2
+	}
+	tools, err := integrations.ParseProjectToolsArg(cwd, *toolsArg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 2
+	}
+
+	header := tr(lang, "Running uninstall", "Ejecutando uninstall")
+	if *dryRun {
+		header = tr(lang, "Uninstall Dry Run", "Simulación de Uninstall")
+	}
+	fmt.Println(ui.ActionHeader(header, strings.Join(tools, ", ")))
+	results := make([]integrations.ArtifactResult, 0)
+	for _, toolID := range tools {
+		results = append(results, integrations.RemoveForTool(cwd, toolID, *dryRun)...)
+	}
+	if *agents {
+		path := filepath.Join(cwd, "AGENTS.md")
+		wr, err := integrations.RemoveMarkedBlock(path, agentsManagedStart, agentsManagedEnd, "", "", *dryRun)
+		results = append(results, integrations.ArtifactResult{Kind: "agents", Path: path, Outcome: wr.Outcome, Reason: wr.Reason, Err: err})
+	}
+
+	removed := 0
+	stripped := 0
+	skipped := 0
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: tool=%s kind=%s workflow=%s: %v\n", tr(lang, "error", "error"), r.Tool, r.Kind, r.WorkflowID, r.Err)
+			continue
+		}
+		switch r.Outcome {
+		case integrations.OutcomeRemoved:
+			removed++
+			fmt.Println(pathLine("removed", r.Path))
+		case integrations.OutcomeStripped:
+			stripped++
+			fmt.Println(pathLine("stripped", r.Path))
+		case integrations.OutcomeSkipped:
+			skipped++
+			if r.Reason == "unmanaged_exists" {
+				fmt.Fprintf(os.Stderr, "%s: %s: %s\n", tr(lang, "warning", "advertencia"), tr(lang, "kept unmanaged file", "se conserva archivo no gestionado"), displayPath(r.Path))
+			}
+		}
+	}
+
+	fmt.Printf("%s: %d  %s: %d  %s: %d  %s: %d\n", tr(lang, "Removed", "Eliminado"), removed, tr(lang, "Stripped", "Depurado"), stripped, tr(lang, "Skipped", "Omitido"), skipped, tr(lang, "Failed", "Fallido"), failed)
+	if failed > 0 {
+		return 3
+	}
+	return 0
+}
+
 func RunUpdate(args []string) int {
 	return runUpdateCommand(args)
 }
@@ -53,13 +139,16 @@ func runToolArtifactsCommand(cmd string, args []string) int {
 	fs.Usage = func() {
 		fmt.Fprintln(os.Stderr, "Usage:")
 		fmt.Fprintf(os.Stderr, "  pacto %s [--tools <all|none|csv>] [--force]\n", cmd)
+		fmt.Fprintf(os.Stderr, "  pacto %s --check [--diff] [--tools <all|none|csv>]\n", cmd)
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Options:")
 		fs.PrintDefaults()
 	}
 
-	toolsArg := fs.String("tools", "", "Tools to configure: all, none, or comma-separated list (codex,cursor,claude,opencode)")
+	toolsArg := fs.String("tools", "", "Tools to configure: all, none, or comma-separated list (codex,cursor,claude,opencode,windsurf,cline,roo,copilot,aider,gemini or an id from .pacto/adapters)")
 	force := fs.Bool("force", false, "Overwrite unmanaged existing files")
+	check := fs.Bool("check", false, "Report missing, outdated and hand-modified artifacts without writing; exit 1 on drift")
+	showDiff := fs.Bool("diff", false, "With --check, print a unified diff per drifted artifact")
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -73,6 +162,10 @@ func runToolArtifactsCommand(cmd string, args []string) int {
 		fs.Usage()
 		return 2
 	}
+	if *showDiff && !*check {
+		fmt.Fprintln(os.Stderr, tr(lang, "--diff requires --check", "--diff requiere --check"))
+		return 2
+	}
 
 	cwd, err := filepath.Abs(".")
 	if err != nil {
@@ -80,9 +173,14 @@ func runToolArtifactsCommand(cmd string, args []string) int {
 		return 2
 	}
 
+	known, adapterErrs := integrations.ProjectTools(cwd)
+	for _, err := range adapterErrs {
+		fmt.Fprintf(os.Stderr, "%s: %s: %v\n", tr(lang, "warning", "advertencia"), tr(lang, "adapter skipped", "adaptador omitido"), err)
+	}
+
 	tools := make([]string, 0)
 	if strings.TrimSpace(*toolsArg) != "" {
-		parsed, err := integrations.ParseToolsArg(*toolsArg)
+		parsed, err := integrations.ParseProjectToolsArg(cwd, *toolsArg)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			return 2
@@ -95,7 +193,7 @@ func runToolArtifactsCommand(cmd string, args []string) int {
 			return 2
 		}
 		if len(detected) == 0 {
-			fmt.Fprintf(os.Stderr, "%s --tools (%s)\n", tr(lang, "no tools detected. Use", "no se detectaron herramientas. Usa"), strings.Join(integrations.SupportedTools(), ","))
+			fmt.Fprintf(os.Stderr, "%s --tools (%s)\n", tr(lang, "no tools detected. Use", "no se detectaron herramientas. Usa"), strings.Join(known, ","))
 			return 2
 		}
 		tools = detected
@@ -106,14 +204,20 @@ func runToolArtifactsCommand(cmd string, args []string) int {
 		return 0
 	}
 
+	live := liveContext(cwd)
+	if *check {
+		return checkToolArtifacts(lang, cwd, tools, live, *showDiff)
+	}
+
 	fmt.Println(ui.ActionHeader(tr(lang, "Running "+cmd, "Ejecutando "+cmd), strings.Join(tools, ", ")))
 	created := 0
 	updated := 0
 	skipped := 0
 	failed := 0
+	overridden := 0
 
 	for _, toolID := range tools {
-		results := integrations.GenerateForTool(cwd, toolID, *force)
+		results := integrations.GenerateForTool(cwd, toolID, *force, live)
 		for _, r := range results {
 			if r.Err != nil {
 				failed++
@@ -123,28 +227,88 @@ func runToolArtifactsCommand(cmd string, args []string) int {
 			switch r.Outcome {
 			case integrations.OutcomeCreated:
 				created++
-				fmt.Println(pathLine("created", r.Path))
+				fmt.Println(pathLine("created", r.Path) + overrideNote(lang, r))
 			case integrations.OutcomeUpdated:
 				updated++
-				fmt.Println(pathLine("updated", r.Path))
+				fmt.Println(pathLine("updated", r.Path) + overrideNote(lang, r))
 			case integrations.OutcomeSkipped:
 				skipped++
 				if r.Reason == "unmanaged_exists" {
 					fmt.Fprintf(os.Stderr, "%s: %s: %s\n", tr(lang, "warning", "advertencia"), tr(lang, "skipped unmanaged file (use --force)", "archivo no gestionado omitido (usa --force)"), displayPath(r.Path))
 				} else {
-					fmt.Println(pathLine("skipped", r.Path))
+					fmt.Println(pathLine("skipped", r.Path) + overrideNote(lang, r))
 				}
 			}
+			if r.Override != "" {
+				overridden++
+			}
 		}
 	}
 
 	fmt.Printf("%s: %d  %s: %d  %s: %d  %s: %d\n", tr(lang, "Created", "Creado"), created, tr(lang, "Updated", "Actualizado"), updated, tr(lang, "Skipped", "Omitido"), skipped, tr(lang, "Failed", "Fallido"), failed)
+	if overridden > 0 {
+		fmt.Printf("%s: %d\n", tr(lang, "With workflow overrides", "Con overrides de workflow"), overridden)
+	}
 	if failed > 0 {
 		return 3
 	}
 	return 0
 }
 
+// overrideNote flags artifacts rendered with a .pacto/workflows override.
+func overrideNote(lang i18n.Language, r integrations.ArtifactResult) string {
+	if r.Override == "" {
+		return ""
+	}
+	return " " + ui.Dim("("+tr(lang, "override", "override")+": "+displayPath(r.Override)+")")
+}
+
+// checkToolArtifacts reports artifacts that differ from a fresh render.
+// It exits 1 when any drifted, so CI can fail on stale agent files.
+func checkToolArtifacts(lang i18n.Language, projectRoot string, tools []string, live string, showDiff bool) int {
+	fmt.Println(ui.ActionHeader(tr(lang, "Checking artifacts", "Revisando artefactos"), strings.Join(tools, ", ")))
+	counts := map[string]int{}
+	failed := 0
+	for _, toolID := range tools {
+		for _, c := range integrations.CheckTool(projectRoot, toolID, live) {
+			if c.Err != nil {
+				failed++
+				fmt.Fprintf(os.Stderr, "%s: tool=%s kind=%s workflow=%s: %v\n", tr(lang, "error", "error"), c.Tool, c.Kind, c.WorkflowID, c.Err)
+				continue
+			}
+			counts[c.Status]++
+			if !c.Drifted() {
+				continue
+			}
+			label := c.Status
+			if c.Status == integrations.DriftModified {
+				label = tr(lang, "modified (hand-edited)", "modificado (editado a mano)")
+			}
+			fmt.Printf("%s %s %s\n", ui.Warn("!"), displayPath(c.Path), ui.Dim(label))
+			if showDiff {
+				name := filepath.ToSlash(displayPath(c.Path))
+				fmt.Print(integrations.UnifiedDiff("a/"+name, "b/"+name, c.Current, c.Expected))
+			}
+		}
+	}
+
+	drifted := counts[integrations.DriftMissing] + counts[integrations.DriftOutdated] + counts[integrations.DriftModified] + counts[integrations.DriftUnmanaged]
+	fmt.Printf("%s: %d  %s: %d  %s: %d  %s: %d  %s: %d\n",
+		tr(lang, "OK", "OK"), counts[integrations.DriftOK],
+		tr(lang, "Missing", "Faltante"), counts[integrations.DriftMissing],
+		tr(lang, "Outdated", "Desactualizado"), counts[integrations.DriftOutdated],
+		tr(lang, "Modified", "Modificado"), counts[integrations.DriftModified],
+		tr(lang, "Unmanaged", "No gestionado"), counts[integrations.DriftUnmanaged])
+	switch {
+	case failed > 0:
+		return 3
+	case drifted > 0:
+		fmt.Fprintln(os.Stderr, tr(lang, "hint: run `pacto install` to refresh (use --force for unmanaged files)", "sugerencia: ejecuta `pacto install` para actualizar (usa --force para archivos no gestionados)"))
+		return 1
+	}
+	return 0
+}
+
 func runUpdateCommand(args []string) int {
 	lang := effectiveLanguage("")
 	fs := flag.NewFlagSet("update", flag.ContinueOnError)
@@ -13,6 +13,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"pacto/internal/integrations"
 )
 
 func TestRunInstallExplicitToolCreatesArtifacts(t *testing.T) {
@@ -41,6 +43,36 @@ func TestRunInstallExplicitToolCreatesArtifacts(t *testing.T) {
 	assertExists(t, filepath.Join(root, ".cursor", "commands", "pacto-new.md"))
 }
 
+func TestRunInstallFlagsWorkflowOverrides(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".pacto", "workflows"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".pacto", "workflows", "exec.yaml"), []byte("whenToUse: Only with a ticket.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	oldWD, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(oldWD) }()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _ := captureOutput(t, func() {
+		if code := RunInstall([]string{"--tools", "cursor"}); code != 0 {
+			t.Fatalf("RunInstall returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, "pacto-exec.md (override: .pacto/workflows/exec.yaml)") || !strings.Contains(stdout, "With workflow overrides: 2") {
+		t.Fatalf("expected override flags, got %q", stdout)
+	}
+	if strings.Contains(stdout, "pacto-status.md (override") {
+		t.Fatalf("status artifacts have no override, got %q", stdout)
+	}
+}
+
 func TestRunInstallAutoDetectsOpenCode(t *testing.T) {
 	root := t.TempDir()
 	if err := os.MkdirAll(filepath.Join(root, ".opencode"), 0o755); err != nil {
@@ -62,6 +94,99 @@ func TestRunInstallAutoDetectsOpenCode(t *testing.T) {
 	assertExists(t, filepath.Join(root, ".opencode", "commands", "pacto-status.md"))
 }
 
+func TestRunInstallCheckReportsDrift(t *testing.T) {
+	root := t.TempDir()
+	oldWD, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(oldWD) }()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	captureOutput(t, func() {
+		if code := RunInstall([]string{"--tools", "cursor"}); code != 0 {
+			t.Fatalf("RunInstall returned %d", code)
+		}
+	})
+	stdout, _ := captureOutput(t, func() {
+		if code := RunInstall([]string{"--tools", "cursor", "--check"}); code != 0 {
+			t.Fatalf("clean check returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, "Missing: 0  Outdated: 0  Modified: 0") {
+		t.Fatalf("expected clean summary, got %q", stdout)
+	}
+
+	path := filepath.Join(root, ".cursor", "commands", "pacto-status.md")
+	b, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, []byte(strings.Replace(string(b), "## Objective", "## Goal", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stdout, _ = captureOutput(t, func() {
+		if code := RunInstall([]string{"--tools", "cursor", "--check", "--diff"}); code != 1 {
+			t.Fatalf("drift check returned %d, want 1", code)
+		}
+	})
+	for _, want := range []string{"pacto-status.md modified (hand-edited)", "--- a/.cursor/commands/pacto-status.md", "-## Goal", "+## Objective", "Modified: 1"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("expected %q in output, got %q", want, stdout)
+		}
+	}
+	if b2, _ := os.ReadFile(path); !strings.Contains(string(b2), "## Goal") {
+		t.Fatal("--check must not write files")
+	}
+	if code := RunInstall([]string{"--diff"}); code != 2 {
+		t.Fatalf("--diff without --check returned %d, want 2", code)
+	}
+}
+
+func TestRunUninstallRemovesManagedArtifacts(t *testing.T) {
+	root := t.TempDir()
+	oldWD, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(oldWD) }()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	captureOutput(t, func() {
+		if code := RunInstall([]string{"--tools", "cursor"}); code != 0 {
+			t.Fatalf("RunInstall returned %d", code)
+		}
+	})
+	agents := "# Team rules\n\n" + agentsManagedStart + "\nhand-off\n" + agentsManagedEnd + "\n"
+	if err := os.WriteFile(filepath.Join(root, "AGENTS.md"), []byte(agents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	custom := filepath.Join(root, ".cursor", "commands", "custom.md")
+	if err := os.WriteFile(custom, []byte("mine\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _ := captureOutput(t, func() {
+		if code := Run([]string{"uninstall", "--tools", "cursor", "--agents"}); code != 0 {
+			t.Fatalf("uninstall returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, "Stripped: 1") {
+		t.Fatalf("expected AGENTS.md to be stripped, got %q", stdout)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".cursor", "skills")); !os.IsNotExist(err) {
+		t.Fatalf("expected skills dir to be pruned, got %v", err)
+	}
+	assertExists(t, custom)
+	b, _ := os.ReadFile(filepath.Join(root, "AGENTS.md"))
+	if string(b) != "# Team rules\n" {
+		t.Fatalf("unexpected AGENTS.md: %q", b)
+	}
+	if code := Run([]string{"uninstall"}); code != 2 {
+		t.Fatalf("uninstall without --tools returned %d, want 2", code)
+	}
+}
+
 func TestRunUpdateSkipsUnmanagedWithoutForce(t *testing.T) {
 	root := t.TempDir()
 	if err := os.MkdirAll(filepath.Join(root, ".cursor", "commands"), 0o755); err != nil {
@@ -231,3 +356,88 @@ func assertExists(t *testing.T, path string) {
 		t.Fatalf("expected path to exist: %s (%v)", path, err)
 	}
 }
+
+func TestLiveContextIsInstalledAndRefreshedAfterExec(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	cfgPath := filepath.Join(root, ".pacto", "config.yaml")
+	cfg, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg = append(cfg, []byte("integrations:\n  liveContext:\n    enabled: true\n")...)
+	if err := os.WriteFile(cfgPath, cfg, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	oldWD, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(oldWD) }()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	captureOutput(t, func() {
+		if code := RunInstall([]string{"--tools", "claude"}); code != 0 {
+			t.Fatalf("RunInstall returned %d", code)
+		}
+	})
+	skill := filepath.Join(root, ".claude", "skills", "pacto-status", "SKILL.md")
+	b, _ := os.ReadFile(skill)
+	if !strings.Contains(string(b), "## Live Plan Context") || !strings.Contains(string(b), "No plans in `current`") {
+		t.Fatalf("expected empty live context, got %q", b)
+	}
+
+	writeTestPlan(t, filepath.Join(root, ".pacto", "plans"), "current", "sample-live", "# Sample Live\n",
+		"# Plan: Sample Live\n\n## Phase 1: Setup\n\n- [ ] 1.1 first task\n- [ ] 1.2 second task\n")
+	captureOutput(t, func() {
+		if code := Run([]string{"exec", "current", "sample-live", "--note", "started"}); code != 0 {
+			t.Fatalf("exec returned %d", code)
+		}
+	})
+	b, _ = os.ReadFile(skill)
+	text := string(b)
+	if !strings.Contains(text, "### current/sample-live") || !strings.Contains(text, "- Next: ") {
+		t.Fatalf("expected refreshed live context, got %q", text)
+	}
+	if strings.Index(text, integrations.LiveContextEnd) > strings.Index(text, integrations.ManagedEnd) {
+		t.Fatal("live context must stay inside the managed block")
+	}
+	if _, err := os.Stat(filepath.Join(root, ".cursor")); !os.IsNotExist(err) {
+		t.Fatal("refresh must not install tools that were not installed")
+	}
+
+	writeTestPlan(t, filepath.Join(root, ".pacto", "plans"), "current", "other-live", "# Other Live\n", "# Plan: Other Live\n")
+	captureOutput(t, func() {
+		if code := RunInstall([]string{"--tools", "claude", "--check"}); code != 0 {
+			t.Fatalf("install --check returned %d after a plan change, want 0", code)
+		}
+	})
+}
+
+func TestLiveContextFollowsTheActiveState(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	cfgPath := filepath.Join(root, ".pacto", "config.yaml")
+	cfg, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg = append(cfg, []byte("integrations:\n  liveContext:\n    enabled: true\nstates:\n  - id: backlog\n  - id: doing\n    active: true\n  - id: shipped\n")...)
+	if err := os.WriteFile(cfgPath, cfg, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+	writeTestPlan(t, plansRoot, "doing", "sample-live", "# Sample Live\n", "# Plan: Sample Live\n\n## Phase 1: Setup\n\n- [ ] 1.1 first task\n")
+	writeTestPlan(t, plansRoot, "backlog", "later", "# Later\n", "# Plan: Later\n")
+
+	live := liveContext(root)
+	if !strings.Contains(live, "Plans in `doing`") || !strings.Contains(live, "### doing/sample-live") || strings.Contains(live, "later") {
+		t.Fatalf("expected live context for the doing state, got %q", live)
+	}
+}
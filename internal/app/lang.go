@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"pacto/internal/config"
 	"pacto/internal/i18n"
 	"pacto/internal/yamlutil"
 )
@@ -21,7 +22,14 @@ func effectiveLanguage(projectRootHint string) i18n.Language {
 			return lang
 		}
 	}
-	for _, cfgPath := range candidateConfigPaths(projectRootHint) {
+	if lang, ok := i18n.ParseLanguage(os.Getenv(config.EnvName("ui.language"))); ok {
+		return lang
+	}
+	paths := candidateConfigPaths(projectRootHint)
+	if p := config.UserPath(); p != "" {
+		paths = append(paths, p)
+	}
+	for _, cfgPath := range paths {
 		cfg, err := yamlutil.ReadFileMap(cfgPath)
 		if err != nil {
 			continue
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"pacto/internal/config"
+	"pacto/internal/integrations"
+)
+
+// liveContextCommands change plans, so they refresh the live section of
+// installed skills when integrations.liveContext is enabled.
+var liveContextCommands = map[string]bool{
+	"new": true, "exec": true, "move": true, "rename": true, "split": true,
+	"merge": true, "archive": true, "unarchive": true, "undo": true,
+}
+
+// liveContext renders the Live Plan Context section for projectRoot, or ""
+// when it is disabled or the workspace has no plans root yet.
+func liveContext(projectRoot string) string {
+	lc, err := integrations.ReadLiveContextConfig(projectRoot)
+	if err != nil || !lc.Enabled {
+		return ""
+	}
+	plansRoot, ok := resolvePlanRoot(projectRoot)
+	if !ok {
+		return ""
+	}
+	cfg, _, err := config.Load("", projectRoot)
+	if err != nil {
+		return ""
+	}
+	active := stateMachine(plansRoot).Active().ID
+	cfg = normalizeConfig(cfg)
+	cfg.PlansRoot = plansRoot
+	cfg.RepoRoot = projectRoot
+	cfg.State = active
+	rep, _, ok := buildStatusReport(cfg, nil)
+	if !ok {
+		return ""
+	}
+	return integrations.RenderLiveContext(rep, active, lc.MaxBytes)
+}
+
+// refreshLiveContextIfNeeded rewrites installed skills after a successful
+// plan-changing command. Failures only warn.
+func refreshLiveContextIfNeeded(cmd string, args []string, code int) {
+	if code != 0 || !liveContextCommands[cmd] {
+		return
+	}
+	base, err := filepath.Abs(".")
+	if err != nil {
+		return
+	}
+	flags, _ := splitHookArgs(args)
+	if r := flags["root"]; r != "" {
+		if abs, err := filepath.Abs(r); err == nil {
+			base = abs
+		}
+	}
+	_, projectRoot, ok := resolvePlanRootFrom(base)
+	if !ok {
+		return
+	}
+	live := liveContext(projectRoot)
+	if live == "" {
+		return
+	}
+	for _, r := range integrations.RefreshLiveContext(projectRoot, live) {
+		if r.Err != nil {
+			fmt.Fprintf(os.Stderr, "warning: refresh live context: %s: %v\n", displayPath(r.Path), r.Err)
+		}
+	}
+}
@@ -9,7 +9,10 @@ import (
 	"strings"
 	"time"
 
+	"pacto/internal/fsutil"
 	"pacto/internal/i18n"
+	"pacto/internal/journal"
+	"pacto/internal/states"
 	"pacto/internal/ui"
 )
 
@@ -32,10 +35,6 @@ func RunMove(args []string) int {
 	fromState := strings.ToLower(strings.TrimSpace(pos[0]))
 	slug := strings.TrimSpace(pos[1])
 	toState := strings.ToLower(strings.TrimSpace(pos[2]))
-	if !isValidState(fromState) || !isValidState(toState) {
-		fmt.Fprintf(os.Stderr, "invalid state transition %q -> %q (allowed: current|to-implement|done|outdated)\n", fromState, toState)
-		return 2
-	}
 	if fromState == toState {
 		fmt.Fprintln(os.Stderr, "source and destination states are the same")
 		return 2
@@ -50,11 +49,29 @@ func RunMove(args []string) int {
 		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
 		return 2
 	}
+	sm, code, ok := loadStateMachine(plansRoot)
+	if !ok {
+		return code
+	}
+	if code := requireStates(sm, fromState, toState); code != 0 {
+		return code
+	}
+	if code := requireTransition(sm, fromState, toState); code != 0 {
+		return code
+	}
 
 	lang := effectiveLanguage(filepath.Dir(plansRoot))
+	lock, code, ok := lockWorkspace(plansRoot, lang)
+	if !ok {
+		return code
+	}
+	defer lock.Release()
+	rec := newJournal(plansRoot, "move", args)
+	defer commitJournal(rec, fmt.Sprintf("%s/%s -> %s/%s", fromState, slug, toState, slug))
+	recordTransition(fromState, toState, slug)
 
-	srcDir := filepath.Join(plansRoot, fromState, slug)
-	dstDir := filepath.Join(plansRoot, toState, slug)
+	srcDir := filepath.Join(sm.Dir(plansRoot, fromState), slug)
+	dstDir := filepath.Join(sm.Dir(plansRoot, toState), slug)
 	if _, err := os.Stat(filepath.Join(srcDir, "README.md")); err != nil {
 		fmt.Fprintf(os.Stderr, "source plan not found: %s/%s\n", fromState, slug)
 		return 2
@@ -64,50 +81,31 @@ func RunMove(args []string) int {
 			fmt.Fprintf(os.Stderr, "destination already exists: %s (use --force to overwrite)\n", dstDir)
 			return 2
 		}
-		if err := os.RemoveAll(dstDir); err != nil {
+		if err := rec.RemoveAll(dstDir); err != nil {
 			fmt.Fprintf(os.Stderr, "remove destination: %v\n", err)
 			return 3
 		}
 	}
 
-	if err := os.Rename(srcDir, dstDir); err != nil {
+	if err := os.MkdirAll(filepath.Dir(dstDir), 0o775); err != nil {
+		fmt.Fprintf(os.Stderr, "create state dir: %v\n", err)
+		return 3
+	}
+	if err := rec.Rename(srcDir, dstDir); err != nil {
 		fmt.Fprintf(os.Stderr, "move plan directory: %v\n", err)
 		return 3
 	}
 
 	readmePath := filepath.Join(dstDir, "README.md")
-	if err := rewritePlanReadmeStatus(readmePath, toState, fromState, opts.reason); err != nil {
+	if err := rewritePlanReadmeStatus(rec, sm, readmePath, toState, fromState, opts.reason, lang); err != nil {
 		fmt.Fprintf(os.Stderr, "update moved README: %v\n", err)
 		return 3
 	}
 
 	rootReadme := filepath.Join(plansRoot, "README.md")
-	b, err := os.ReadFile(rootReadme)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "read root README: %v\n", err)
-		return 3
-	}
-	text := string(b)
-	counts, err := countPlans(plansRoot)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "count plans: %v\n", err)
-		return 3
-	}
-	text = updateCountsTable(text, counts)
-	text = removePlanLinkFromSection(text, fromState, slug)
-	title := readPlanTitle(readmePath)
-	if title == "" {
-		title = slugToTitle(slug)
-	}
-	text2, err := upsertLinkInSection(text, toState, title, fmt.Sprintf("./%s/%s/", toState, slug))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "update root section: %v\n", err)
-		return 3
-	}
-	text = updateLastUpdate(text2, time.Now().Format("2006-01-02"), lang)
-	if err := os.WriteFile(rootReadme, []byte(text), 0o664); err != nil {
-		fmt.Fprintf(os.Stderr, "write root README: %v\n", err)
-		return 3
+	move := indexMove{fromState: fromState, fromRef: slug, toState: toState, toRef: slug, title: readPlanTitle(readmePath), slug: slug}
+	if err := rewriteIndexForMoves(rec, plansRoot, []indexMove{move}, lang); err != nil {
+		return reportWriteError("update root README", err, lang)
 	}
 
 	fmt.Println(ui.ActionHeader(tr(lang, "Moved Plan", "Plan movido"), fmt.Sprintf("%s/%s -> %s/%s", fromState, slug, toState, slug)))
@@ -154,15 +152,20 @@ func normalizeMoveArgs(args []string) ([]string, error) {
 	return normalizeArgs(args, withValue)
 }
 
-func rewritePlanReadmeStatus(path, toState, fromState, reason string) error {
-	lang := effectiveLanguage(filepath.Dir(filepath.Dir(filepath.Dir(path))))
-	b, err := os.ReadFile(path)
+func rewritePlanReadmeStatus(rec *journal.Recorder, sm states.Machine, path, toState, fromState, reason string, lang i18n.Language) error {
+	b, snap, err := fsutil.ReadSnapshot(path)
 	if err != nil {
 		return err
 	}
-	text := string(b)
+	text := withPlanStatus(string(b), sm, toState, fromState, reason, lang)
+	return rec.WriteChecked(snap, []byte(text), 0o664)
+}
+
+// withPlanStatus sets the README status line to toState and records reason
+// in the move history.
+func withPlanStatus(text string, sm states.Machine, toState, fromState, reason string, lang i18n.Language) string {
 	lines := strings.Split(text, "\n")
-	newStatus := stateStatusLabel(toState, lang)
+	newStatus := sm.Label(toState, lang)
 	updated := false
 	for i, ln := range lines {
 		trimmed := strings.TrimSpace(ln)
@@ -180,23 +183,7 @@ func rewritePlanReadmeStatus(path, toState, fromState, reason string) error {
 		note := fmt.Sprintf("- %s %s `%s` %s `%s`: %s", time.Now().Format("2006-01-02 15:04"), tr(lang, "moved from", "movido de"), fromState, tr(lang, "to", "a"), toState, strings.TrimSpace(reason))
 		text = appendSectionBullet(text, tr(lang, "## Move History", "## Historial de cambios"), note)
 	}
-	return os.WriteFile(path, []byte(text), 0o664)
-}
-
-func stateStatusLabel(state string, lang i18n.Language) string {
-	en := map[string]string{
-		"current":      "In Progress (Current)",
-		"to-implement": "Pending (To Implement)",
-		"done":         "Completed (Done)",
-		"outdated":     "Outdated (Outdated)",
-	}[state]
-	es := map[string]string{
-		"current":      "En ejecución (Current)",
-		"to-implement": "Pendiente (To Implement)",
-		"done":         "Completado (Done)",
-		"outdated":     "Obsoleto (Outdated)",
-	}[state]
-	return tr(lang, en, es)
+	return text
 }
 
 func readPlanTitle(readmePath string) string {
@@ -213,17 +200,17 @@ func readPlanTitle(readmePath string) string {
 	return ""
 }
 
-func removePlanLinkFromSection(text, state, slug string) string {
+func removePlanLinkFromSection(text string, st states.State, slug string) string {
 	lines := strings.Split(text, "\n")
-	start, end, sep := findCanonicalSection(lines, state)
+	start, end, sep := findCanonicalSection(lines, st)
 	if start < 0 {
-		start, end, sep = findSectionByStateLink(lines, state)
+		start, end, sep = findSectionByStateLink(lines, st.Folder)
 	}
 	if start < 0 {
 		return text
 	}
 
-	needle := fmt.Sprintf("./%s/%s/", state, slug)
+	needle := fmt.Sprintf("./%s/%s/", st.Folder, slug)
 	sec := append([]string{}, lines[start+1:sep]...)
 	newSec := make([]string, 0, len(sec))
 	bulletCount := 0
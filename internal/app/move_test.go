@@ -63,3 +63,133 @@ func TestRunMoveTransitionsStateAndUpdatesIndex(t *testing.T) {
 		t.Fatalf("expected done link added, got %q", index)
 	}
 }
+
+func TestRunMoveHonorsConfiguredStates(t *testing.T) {
+	root := t.TempDir()
+	cfg := `states:
+  - id: to-implement
+  - id: current
+  - id: review
+    folder: in-review
+    emoji: "🔍"
+    labels:
+      en: In Review (Review)
+      es: En revisión (Review)
+    from: [current]
+  - id: done
+    from: [review]
+`
+	if err := os.MkdirAll(filepath.Join(root, ".pacto"), 0o775); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".pacto", "config.yaml"), []byte(cfg), 0o664); err != nil {
+		t.Fatal(err)
+	}
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+	if _, err := os.Stat(filepath.Join(plansRoot, "in-review")); err != nil {
+		t.Fatalf("expected configured state folder: %v", err)
+	}
+
+	captureOutput(t, func() {
+		if code := RunNew([]string{"to-implement", "flow", "--root", root}); code != 0 {
+			t.Fatalf("RunNew returned %d", code)
+		}
+	})
+	_, stderr := captureOutput(t, func() {
+		if code := RunNew([]string{"outdated", "other", "--root", root}); code != 2 {
+			t.Fatalf("RunNew with undeclared state returned %d, want 2", code)
+		}
+	})
+	if !strings.Contains(stderr, "allowed: to-implement|current|review|done") {
+		t.Fatalf("expected configured states in error, got %q", stderr)
+	}
+
+	_, stderr = captureOutput(t, func() {
+		if code := RunMove([]string{"to-implement", "flow", "done", "--root", root}); code != 2 {
+			t.Fatalf("RunMove returned %d, want 2", code)
+		}
+	})
+	if !strings.Contains(stderr, "not allowed") || !strings.Contains(stderr, "only from: review") {
+		t.Fatalf("expected transition error, got %q", stderr)
+	}
+
+	for _, step := range [][]string{{"to-implement", "flow", "current"}, {"current", "flow", "review"}} {
+		captureOutput(t, func() {
+			if code := RunMove(append(step, "--root", root)); code != 0 {
+				t.Fatalf("RunMove %v returned %d", step, code)
+			}
+		})
+	}
+	b, err := os.ReadFile(filepath.Join(plansRoot, "in-review", "flow", "README.md"))
+	if err != nil {
+		t.Fatalf("expected plan in configured folder: %v", err)
+	}
+	if !strings.Contains(string(b), "**Status:** In Review (Review)") {
+		t.Fatalf("expected configured label, got %q", string(b))
+	}
+	idx, err := os.ReadFile(filepath.Join(plansRoot, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := string(idx)
+	if !strings.Contains(index, "| 🔍 **Review** | 1 |") || !strings.Contains(index, "## 🔍 Review") || !strings.Contains(index, "./in-review/flow/") {
+		t.Fatalf("expected review row, section and link, got %q", index)
+	}
+
+	captureOutput(t, func() {
+		if code := RunMove([]string{"review", "flow", "done", "--root", root}); code != 0 {
+			t.Fatalf("RunMove review -> done returned %d", code)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(plansRoot, "done", "flow", "README.md")); err != nil {
+		t.Fatalf("expected plan in done: %v", err)
+	}
+}
+
+func TestStateAddedAfterInitIsCreatedOnDemand(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	cfg := `states:
+  - id: to-implement
+  - id: current
+  - id: review
+    from: [current]
+  - id: done
+  - id: outdated
+`
+	if err := os.WriteFile(filepath.Join(root, ".pacto", "config.yaml"), []byte(cfg), 0o664); err != nil {
+		t.Fatal(err)
+	}
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+	if err := os.Remove(filepath.Join(plansRoot, "outdated")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, stderr := captureOutput(t, func() {
+		if code := RunStatus([]string{"--root", root, "--format", "json"}); code != 0 {
+			t.Fatalf("RunStatus returned %d", code)
+		}
+		if code := RunNew([]string{"current", "flow", "--root", root}); code != 0 {
+			t.Fatalf("RunNew returned %d", code)
+		}
+		if code := RunMove([]string{"current", "flow", "review", "--root", root}); code != 0 {
+			t.Fatalf("RunMove returned %d", code)
+		}
+		if code := RunNew([]string{"outdated", "stale", "--root", root}); code != 0 {
+			t.Fatalf("RunNew returned %d", code)
+		}
+	})
+	if stderr != "" {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+	for _, p := range []string{"review/flow/README.md", "outdated/stale/README.md"} {
+		if _, err := os.Stat(filepath.Join(plansRoot, filepath.FromSlash(p))); err != nil {
+			t.Fatalf("expected %s: %v", p, err)
+		}
+	}
+}
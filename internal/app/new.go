@@ -11,7 +11,11 @@ import (
 	"strings"
 	"time"
 
+	"pacto/internal/discovery"
+	"pacto/internal/fsutil"
 	"pacto/internal/i18n"
+	"pacto/internal/journal"
+	"pacto/internal/states"
 	"pacto/internal/ui"
 )
 
@@ -37,6 +41,7 @@ type newRequest struct {
 	planFileName string
 	planPath     string
 	readmePath   string
+	description  string
 }
 
 func RunNew(args []string) int {
@@ -54,11 +59,19 @@ func RunNew(args []string) int {
 		return code
 	}
 	lang := effectiveLanguage(req.root)
-	if code = createPlanScaffold(req); code != 0 {
+	lock, code, ok := lockWorkspace(req.root, lang)
+	if !ok {
+		return code
+	}
+	defer lock.Release()
+	rec := newJournal(req.root, "new", args)
+	defer commitJournal(rec, "created "+req.state+"/"+req.slug)
+	recordTransition("", req.state, req.slug)
+	if code = createPlanScaffold(rec, req); code != 0 {
 		return code
 	}
 
-	if err := updateRootIndex(req.root, req.state, req.slug, req.title, req.date, lang); err != nil {
+	if err := updateRootIndex(rec, req.root, req.state, req.slug, req.title, req.date, lang); err != nil {
 		fmt.Fprintf(os.Stderr, "update root README: %v\n", err)
 		return 3
 	}
@@ -76,7 +89,7 @@ func parseAndValidateNewArgs(args []string) (newOptions, string, string, bool, i
 	fs.SetOutput(os.Stderr)
 	fs.Usage = func() {
 		fmt.Fprintln(os.Stderr, "Usage:")
-		fmt.Fprintln(os.Stderr, "  pacto new <current|to-implement|done|outdated> <slug> [--title ...] [--owner ...] [--root <path>] [--allow-minimal-root]")
+		fmt.Fprintln(os.Stderr, "  pacto new <state> <slug> [--title ...] [--owner ...] [--root <path>] [--allow-minimal-root]")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Options:")
 		fs.PrintDefaults()
@@ -121,10 +134,6 @@ func parseAndValidateNewArgs(args []string) (newOptions, string, string, bool, i
 
 	state := strings.ToLower(strings.TrimSpace(pos[0]))
 	slug := strings.TrimSpace(pos[1])
-	if !isValidState(state) {
-		fmt.Fprintf(os.Stderr, "invalid state %q (allowed: current|to-implement|done|outdated)\n", state)
-		return newOptions{}, "", "", rootProvided, 2, false
-	}
 	if !slugRe.MatchString(slug) {
 		fmt.Fprintf(os.Stderr, "invalid slug %q (use lowercase letters, numbers, dashes)\n", slug)
 		return newOptions{}, "", "", rootProvided, 2, false
@@ -146,13 +155,20 @@ func buildNewRequest(opts newOptions, state, slug string, rootProvided bool) (ne
 		absRoot = resolved
 	}
 
+	sm, code, ok := loadStateMachine(absRoot)
+	if !ok {
+		return newRequest{}, code, false
+	}
+	if code := requireStates(sm, state); code != 0 {
+		return newRequest{}, code, false
+	}
 	if opts.allowMinimal {
-		if err := ensureMinimalRoot(absRoot); err != nil {
+		if err := ensureMinimalRoot(absRoot, sm); err != nil {
 			fmt.Fprintf(os.Stderr, "prepare minimal root: %v\n", err)
 			return newRequest{}, 2, false
 		}
 	} else {
-		if err := validateRoot(absRoot); err != nil {
+		if err := validateRoot(absRoot, sm); err != nil {
 			fmt.Fprintf(os.Stderr, "invalid pacto root: %v\n", err)
 			return newRequest{}, 2, false
 		}
@@ -165,8 +181,8 @@ func buildNewRequest(opts newOptions, state, slug string, rootProvided bool) (ne
 
 	now := time.Now()
 	date := now.Format("2006-01-02")
-	planDir := filepath.Join(absRoot, state, slug)
-	if _, err := os.Stat(planDir); err == nil {
+	planDir := filepath.Join(sm.Dir(absRoot, state), slug)
+	if _, err := os.Stat(planDir); err == nil && !isRedirectDir(planDir) {
 		fmt.Fprintf(os.Stderr, "plan already exists: %s\n", planDir)
 		return newRequest{}, 2, false
 	}
@@ -187,9 +203,24 @@ func buildNewRequest(opts newOptions, state, slug string, rootProvided bool) (ne
 	return req, 0, true
 }
 
-func createPlanScaffold(req newRequest) int {
+func createPlanScaffold(rec *journal.Recorder, req newRequest) int {
 	lang := effectiveLanguage(req.root)
-	if err := os.MkdirAll(req.planDir, 0o775); err != nil {
+	if err := os.MkdirAll(filepath.Dir(req.planDir), 0o775); err != nil {
+		fmt.Fprintf(os.Stderr, "create plan dir: %v\n", err)
+		return 3
+	}
+	// A folder left by rename or merge holding only MOVED.md is reused.
+	if isRedirectDir(req.planDir) {
+		if err := rec.RemoveAll(req.planDir); err != nil {
+			fmt.Fprintf(os.Stderr, "remove redirect: %v\n", err)
+			return 3
+		}
+	}
+	if err := rec.Mkdir(req.planDir, 0o775); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			fmt.Fprintf(os.Stderr, "plan already exists: %s\n", req.planDir)
+			return 2
+		}
 		fmt.Fprintf(os.Stderr, "create plan dir: %v\n", err)
 		return 3
 	}
@@ -199,11 +230,11 @@ func createPlanScaffold(req newRequest) int {
 		fmt.Fprintf(os.Stderr, "build plan from template: %v\n", err)
 		return 3
 	}
-	if err := os.WriteFile(req.planPath, []byte(planText), 0o664); err != nil {
+	if err := rec.WriteChecked(fsutil.Snapshot{Path: req.planPath}, []byte(planText), 0o664); err != nil {
 		fmt.Fprintf(os.Stderr, "write plan file: %v\n", err)
 		return 3
 	}
-	if err := os.WriteFile(req.readmePath, []byte(buildPlanReadme(req.title, req.state, req.date, req.planFileName, lang)), 0o664); err != nil {
+	if err := rec.WriteChecked(fsutil.Snapshot{Path: req.readmePath}, []byte(buildPlanReadme(req.title, stateMachine(req.root).Label(req.state, lang), req.date, req.planFileName, req.description, lang)), 0o664); err != nil {
 		fmt.Fprintf(os.Stderr, "write readme: %v\n", err)
 		return 3
 	}
@@ -215,42 +246,31 @@ func normalizeNewArgs(args []string) ([]string, error) {
 	return normalizeArgs(args, withValue)
 }
 
-func isValidState(state string) bool {
-	switch state {
-	case "current", "to-implement", "done", "outdated":
-		return true
-	default:
-		return false
-	}
-}
-
-func validateRoot(root string) error {
+func validateRoot(root string, sm states.Machine) error {
 	for _, p := range []string{"README.md", "PLANTILLA_PACTO_PLAN.md", "PACTO.md"} {
 		if _, err := os.Stat(filepath.Join(root, p)); err != nil {
 			return fmt.Errorf("missing %s", p)
 		}
 	}
-	for _, st := range []string{"current", "to-implement", "done", "outdated"} {
-		if _, err := os.Stat(filepath.Join(root, st)); err != nil {
-			return fmt.Errorf("missing state folder %s", st)
-		}
+	if !hasStateDirs(root) {
+		return fmt.Errorf("missing state folders (%s)", strings.Join(sm.Folders(), ", "))
 	}
 	return nil
 }
 
-func ensureMinimalRoot(root string) error {
+func ensureMinimalRoot(root string, sm states.Machine) error {
 	if err := os.MkdirAll(root, 0o775); err != nil {
 		return err
 	}
-	for _, st := range []string{"current", "to-implement", "done", "outdated"} {
-		if err := os.MkdirAll(filepath.Join(root, st), 0o775); err != nil {
+	for _, folder := range sm.Folders() {
+		if err := os.MkdirAll(filepath.Join(root, folder), 0o775); err != nil {
 			return err
 		}
 	}
 	readmePath := filepath.Join(root, "README.md")
 	if _, err := os.Stat(readmePath); err != nil {
 		lang := effectiveLanguage(root)
-		if err := os.WriteFile(readmePath, []byte(defaultRootReadme(lang)), 0o664); err != nil {
+		if err := os.WriteFile(readmePath, []byte(defaultRootReadme(lang, sm)), 0o664); err != nil {
 			return err
 		}
 	}
@@ -292,26 +312,16 @@ func buildPlanFromTemplate(root, title, date, owner string, allowMinimal bool, l
 	return t, nil
 }
 
-func buildPlanReadme(title, state, date, planFileName string, lang i18n.Language) string {
-	statusEN := map[string]string{
-		"current":      "In Progress (Current)",
-		"to-implement": "Pending (To Implement)",
-		"done":         "Completed (Done)",
-		"outdated":     "Outdated (Outdated)",
-	}[state]
-	statusES := map[string]string{
-		"current":      "En ejecución (Current)",
-		"to-implement": "Pendiente (To Implement)",
-		"done":         "Completado (Done)",
-		"outdated":     "Obsoleto (Outdated)",
-	}[state]
-	status := tr(lang, statusEN, statusES)
+func buildPlanReadme(title, status, date, planFileName, description string, lang i18n.Language) string {
 	var b strings.Builder
 	b.WriteString("# " + title + "\n\n")
 	b.WriteString(tr(lang, "**Status:** ", "**Estado:** ") + status + "  \n")
 	b.WriteString(tr(lang, "**Date:** ", "**Fecha:** ") + date + "\n\n")
 	b.WriteString(tr(lang, "## Description\n\n", "## Descripción\n\n"))
-	b.WriteString(tr(lang, "Plan created with `pacto new`.\n\n", "Plan creado con `pacto new`.\n\n"))
+	if strings.TrimSpace(description) == "" {
+		description = tr(lang, "Plan created with `pacto new`.", "Plan creado con `pacto new`.")
+	}
+	b.WriteString(strings.TrimRight(description, "\n") + "\n\n")
 	b.WriteString(tr(lang, "## Documents\n\n", "## Documentos\n\n"))
 	b.WriteString("- [" + planFileName + "](./" + planFileName + ")\n")
 	return b.String()
@@ -337,34 +347,40 @@ func slugToTopic(slug string) string {
 	return up
 }
 
-func updateRootIndex(root, state, slug, title, date string, lang i18n.Language) error {
+func updateRootIndex(rec *journal.Recorder, root, state, slug, title, date string, lang i18n.Language) error {
 	readmePath := filepath.Join(root, "README.md")
-	b, err := os.ReadFile(readmePath)
+	b, snap, err := fsutil.ReadSnapshot(readmePath)
 	if err != nil {
 		return err
 	}
 	text := string(b)
 
-	counts, err := countPlans(root)
+	sm := stateMachine(root)
+	counts, err := countPlans(root, sm)
 	if err != nil {
 		return err
 	}
-	text = updateCountsTable(text, counts)
-	text, err = upsertLinkInSection(text, state, title, fmt.Sprintf("./%s/%s/", state, slug))
+	text = updateCountsTable(text, sm, counts)
+	st := indexState(sm, state)
+	text, err = upsertLinkInSection(text, st, title, fmt.Sprintf("./%s/%s/", st.Folder, slug))
 	if err != nil {
 		return err
 	}
 	text = updateLastUpdate(text, date, lang)
 
-	return os.WriteFile(readmePath, []byte(text), 0o664)
+	return rec.WriteChecked(snap, []byte(text), 0o664)
 }
 
-func countPlans(root string) (map[string]int, error) {
+func countPlans(root string, sm states.Machine) (map[string]int, error) {
 	out := map[string]int{}
-	for _, st := range []string{"current", "to-implement", "done", "outdated"} {
-		dir := filepath.Join(root, st)
+	for _, st := range sm.States {
+		dir := filepath.Join(root, st.Folder)
 		ents, err := os.ReadDir(dir)
 		if err != nil {
+			if os.IsNotExist(err) {
+				out[st.ID] = 0
+				continue
+			}
 			return nil, err
 		}
 		n := 0
@@ -376,18 +392,24 @@ func countPlans(root string) (map[string]int, error) {
 				n++
 			}
 		}
-		out[st] = n
+		out[st.ID] = n
+	}
+	if archived, err := discovery.FindArchived(root); err == nil {
+		out[discovery.ArchiveDir] = len(archived)
 	}
 	return out, nil
 }
 
-func updateCountsTable(text string, counts map[string]int) string {
-	repls := map[string]string{
-		"| 🟢 **Current** |":      fmt.Sprintf("| 🟢 **Current** | %d |", counts["current"]),
-		"| 🟡 **To Implement** |": fmt.Sprintf("| 🟡 **To Implement** | %d |", counts["to-implement"]),
-		"| ✅ **Done** |":         fmt.Sprintf("| ✅ **Done** | %d |", counts["done"]),
-		"| ⚠️ **Outdated** |":    fmt.Sprintf("| ⚠️ **Outdated** | %d |", counts["outdated"]),
+func updateCountsTable(text string, sm states.Machine, counts map[string]int) string {
+	for _, st := range sm.States {
+		text = ensureCountRow(text, sm, st)
 	}
+	repls := map[string]string{}
+	for _, st := range sm.States {
+		repls[st.CountRow()] = fmt.Sprintf("%s %d |", st.CountRow(), counts[st.ID])
+	}
+	archive := indexState(sm, states.Archive)
+	repls[archive.CountRow()] = fmt.Sprintf("%s %d |", archive.CountRow(), counts[states.Archive])
 	lines := strings.Split(text, "\n")
 	for i, ln := range lines {
 		for prefix, rep := range repls {
@@ -399,16 +421,42 @@ func updateCountsTable(text string, counts map[string]int) string {
 	return strings.Join(lines, "\n")
 }
 
-func upsertLinkInSection(text, state, title, relPath string) (string, error) {
+// ensureCountRow adds a zero row for st after the last state row of the counts
+// table. Tables without any known row are left alone.
+func ensureCountRow(text string, sm states.Machine, st states.State) string {
+	lines := strings.Split(text, "\n")
+	insertAt := -1
+	for i, ln := range lines {
+		t := strings.TrimSpace(ln)
+		if strings.HasPrefix(t, st.CountRow()) {
+			return text
+		}
+		for _, other := range sm.States {
+			if strings.HasPrefix(t, other.CountRow()) {
+				insertAt = i + 1
+			}
+		}
+	}
+	if insertAt < 0 {
+		return text
+	}
+	out := make([]string, 0, len(lines)+1)
+	out = append(out, lines[:insertAt]...)
+	out = append(out, st.CountRow()+" 0 |")
+	out = append(out, lines[insertAt:]...)
+	return strings.Join(out, "\n")
+}
+
+func upsertLinkInSection(text string, st states.State, title, relPath string) (string, error) {
 	entry := fmt.Sprintf("- [%s](%s)", title, relPath)
 	lines := strings.Split(text, "\n")
 
-	start, end, sep := findCanonicalSection(lines, state)
+	start, end, sep := findCanonicalSection(lines, st)
 	if start < 0 {
-		start, end, sep = findSectionByStateLink(lines, state)
+		start, end, sep = findSectionByStateLink(lines, st.Folder)
 	}
 	if start < 0 {
-		return addFallbackSection(lines, state, entry), nil
+		return addFallbackSection(lines, st, entry), nil
 	}
 
 	sec := append([]string{}, lines[start+1:sep]...)
@@ -454,16 +502,8 @@ func upsertLinkInSection(text, state, title, relPath string) (string, error) {
 	return strings.Join(out, "\n"), nil
 }
 
-func findCanonicalSection(lines []string, state string) (start, end, sep int) {
-	candidates := map[string][]string{
-		"current":      {"## 🟢 Current (En Ejecución)", "## 🟢 Current (In Progress)", "## 🟢 Current"},
-		"to-implement": {"## 🟡 To Implement (Pendientes)", "## 🟡 To Implement (Pending)", "## 🟡 To Implement"},
-		"done":         {"## ✅ Done (Completados)", "## ✅ Done (Completed)", "## ✅ Done"},
-		"outdated":     {"## ⚠️ Outdated (Obsoletos)", "## ⚠️ Outdated (Outdated)", "## ⚠️ Outdated"},
-	}[state]
-	if len(candidates) == 0 {
-		return -1, -1, -1
-	}
+func findCanonicalSection(lines []string, st states.State) (start, end, sep int) {
+	candidates := st.Headings(i18n.Spanish)
 	start = -1
 	for i, ln := range lines {
 		trimmed := strings.TrimSpace(ln)
@@ -497,8 +537,8 @@ func findCanonicalSection(lines []string, state string) (start, end, sep int) {
 	return start, end, sep
 }
 
-func findSectionByStateLink(lines []string, state string) (start, end, sep int) {
-	needle := "./" + state + "/"
+func findSectionByStateLink(lines []string, folder string) (start, end, sep int) {
+	needle := "./" + folder + "/"
 	for i := 0; i < len(lines); i++ {
 		if !strings.HasPrefix(strings.TrimSpace(lines[i]), "## ") {
 			continue
@@ -526,8 +566,8 @@ func findSectionByStateLink(lines []string, state string) (start, end, sep int)
 	return -1, -1, -1
 }
 
-func addFallbackSection(lines []string, state, entry string) string {
-	heading := "## Plans (" + state + ")"
+func addFallbackSection(lines []string, st states.State, entry string) string {
+	heading := st.Headings(i18n.English)[0]
 	insertAt := len(lines)
 	for i, ln := range lines {
 		if strings.TrimSpace(ln) == "## 📜 Pacto" {
@@ -536,6 +576,9 @@ func addFallbackSection(lines []string, state, entry string) string {
 		}
 	}
 	block := []string{"", heading, entry, "---"}
+	if insertAt < len(lines) {
+		block = []string{heading, entry, "", "---", ""}
+	}
 	out := make([]string, 0, len(lines)+len(block))
 	out = append(out, lines[:insertAt]...)
 	out = append(out, block...)
@@ -543,6 +586,23 @@ func addFallbackSection(lines []string, state, entry string) string {
 	return strings.Join(out, "\n")
 }
 
+// ensureStateIndex adds count rows and empty sections for configured states
+// that the index does not mention yet.
+func ensureStateIndex(text string, sm states.Machine, lang i18n.Language) string {
+	for _, st := range sm.States {
+		text = ensureCountRow(text, sm, st)
+		lines := strings.Split(text, "\n")
+		if start, _, _ := findCanonicalSection(lines, st); start >= 0 {
+			continue
+		}
+		if start, _, _ := findSectionByStateLink(lines, st.Folder); start >= 0 {
+			continue
+		}
+		text = addFallbackSection(lines, st, tr(lang, "_No plans._", "_No hay planes._"))
+	}
+	return text
+}
+
 func updateLastUpdate(text, date string, lang i18n.Language) string {
 	lines := strings.Split(text, "\n")
 	for i, ln := range lines {
@@ -567,45 +627,25 @@ func defaultPlanTemplate(title, date, owner string, lang i18n.Language) string {
 	return b.String()
 }
 
-func defaultRootReadme(lang i18n.Language) string {
-	if lang == i18n.Spanish {
-		return "# Planes de Pacto\n\n" +
-			"## Resumen\n\n" +
-			"| Estado | Cantidad |\n" +
-			"|-------|-------|\n" +
-			"| 🟢 **Current** | 0 |\n" +
-			"| 🟡 **To Implement** | 0 |\n" +
-			"| ✅ **Done** | 0 |\n" +
-			"| ⚠️ **Outdated** | 0 |\n\n" +
-			"---\n\n" +
-			"## 🟢 Current (En Ejecución)\n_No hay planes._\n\n---\n\n" +
-			"## 🟡 To Implement (Pendientes)\n_No hay planes._\n\n---\n\n" +
-			"## ✅ Done (Completados)\n_No hay planes._\n\n---\n\n" +
-			"## ⚠️ Outdated (Obsoletos)\n_No hay planes._\n\n---\n\n" +
-			"## 📜 Pacto\n\n" +
-			"- [PACTO.md](./PACTO.md)\n" +
-			"- [PLANTILLA_PACTO_PLAN.md](./PLANTILLA_PACTO_PLAN.md)\n\n" +
-			"---\n\n" +
-			"**Última Actualización:** 1970-01-01\n"
-	}
-	return "# Pacto Plans\n\n" +
-		"## Summary\n\n" +
-		"| State | Count |\n" +
-		"|-------|-------|\n" +
-		"| 🟢 **Current** | 0 |\n" +
-		"| 🟡 **To Implement** | 0 |\n" +
-		"| ✅ **Done** | 0 |\n" +
-		"| ⚠️ **Outdated** | 0 |\n\n" +
-		"---\n\n" +
-		"## 🟢 Current (In Progress)\n_No plans._\n\n---\n\n" +
-		"## 🟡 To Implement (Pending)\n_No plans._\n\n---\n\n" +
-		"## ✅ Done (Completed)\n_No plans._\n\n---\n\n" +
-		"## ⚠️ Outdated (Outdated)\n_No plans._\n\n---\n\n" +
-		"## 📜 Pacto\n\n" +
-		"- [PACTO.md](./PACTO.md)\n" +
-		"- [PLANTILLA_PACTO_PLAN.md](./PLANTILLA_PACTO_PLAN.md)\n\n" +
-		"---\n\n" +
-		"**Last Updated:** 1970-01-01\n"
+func defaultRootReadme(lang i18n.Language, sm states.Machine) string {
+	var b strings.Builder
+	b.WriteString(tr(lang, "# Pacto Plans\n\n", "# Planes de Pacto\n\n"))
+	b.WriteString(tr(lang, "## Summary\n\n", "## Resumen\n\n"))
+	b.WriteString(tr(lang, "| State | Count |\n", "| Estado | Cantidad |\n"))
+	b.WriteString("|-------|-------|\n")
+	for _, st := range sm.States {
+		b.WriteString(st.CountRow() + " 0 |\n")
+	}
+	b.WriteString("\n---\n\n")
+	for _, st := range sm.States {
+		b.WriteString(st.Headings(lang)[0] + "\n" + tr(lang, "_No plans._", "_No hay planes._") + "\n\n---\n\n")
+	}
+	b.WriteString("## 📜 Pacto\n\n")
+	b.WriteString("- [PACTO.md](./PACTO.md)\n")
+	b.WriteString("- [PLANTILLA_PACTO_PLAN.md](./PLANTILLA_PACTO_PLAN.md)\n\n")
+	b.WriteString("---\n\n")
+	b.WriteString(tr(lang, "**Last Updated:** ", "**Última Actualización:** ") + "1970-01-01\n")
+	return b.String()
 }
 
 func defaultMinimalTemplate(lang i18n.Language) string {
@@ -83,3 +83,39 @@ func TestRunNewPrintsRelativePathsFromCWD(t *testing.T) {
 		t.Fatalf("expected relative root README path in output, got %q", stdout)
 	}
 }
+
+func TestRunNewWithWorkspaceFlag(t *testing.T) {
+	root := t.TempDir()
+	api := filepath.Join(root, "services", "api")
+	for _, dir := range []string{root, api} {
+		if code := RunInit([]string{"--root", dir, "--no-interactive", "--no-install"}); code != 0 {
+			t.Fatalf("RunInit %s returned %d", dir, code)
+		}
+	}
+	oldWD, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(oldWD) }()
+	if err := os.Chdir(filepath.Join(root, "services")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = captureOutput(t, func() {
+		if code := Run([]string{"new", "to-implement", "login", "--workspace", "services/api"}); code != 0 {
+			t.Fatalf("Run new returned %d, want 0", code)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(api, ".pacto", "plans", "to-implement", "login", "README.md")); err != nil {
+		t.Fatalf("expected plan in services/api workspace: %v", err)
+	}
+
+	_, stderr := captureOutput(t, func() {
+		if code := Run([]string{"new", "to-implement", "other", "--workspace", "services/web"}); code != 2 {
+			t.Fatalf("Run new returned %d, want 2", code)
+		}
+	})
+	if !strings.Contains(stderr, `unknown workspace "services/web" (available: ., services/api)`) {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+}
@@ -9,14 +9,17 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
+	"pacto/internal/fsutil"
 	"pacto/internal/plugins"
 	"pacto/internal/plugins/builtin"
+	"pacto/internal/ui"
 )
 
 func RunPlugin(args []string) int {
 	if len(args) == 0 {
-		fmt.Fprintln(os.Stderr, "Usage: pacto plugin <list|list-available|install|validate|enable|disable> [options]")
+		fmt.Fprintln(os.Stderr, "Usage: pacto plugin <list|list-available|install|upgrade|verify|validate|migrate|schema|report|enable|disable> [options]")
 		return 2
 	}
 	sub := strings.ToLower(strings.TrimSpace(args[0]))
@@ -28,8 +31,18 @@ func RunPlugin(args []string) int {
 		return runPluginListAvailable(rest)
 	case "install":
 		return runPluginInstall(rest)
+	case "upgrade":
+		return runPluginUpgrade(rest)
+	case "verify":
+		return runPluginVerify(rest)
 	case "validate":
 		return runPluginValidate(rest)
+	case "migrate":
+		return runPluginMigrate(rest)
+	case "schema":
+		return runPluginSchema(rest)
+	case "report":
+		return runPluginReport(rest)
 	case "enable":
 		return runPluginEnable(rest)
 	case "disable":
@@ -81,7 +94,9 @@ func runPluginInstall(args []string) int {
 	root := fs.String("root", ".", "Project root path")
 	force := fs.Bool("force", false, "Overwrite existing plugin files")
 	noEnable := fs.Bool("no-enable", false, "Install files but do not auto-enable plugin")
-	normalizedArgs, normErr := normalizeArgs(args, map[string]bool{"--root": true, "-root": true})
+	yes := fs.Bool("yes", false, "Approve the plugin permissions without prompting")
+	checksum := fs.String("sha256", "", "Expected SHA-256 of a tarball source")
+	normalizedArgs, normErr := normalizeArgs(args, map[string]bool{"--root": true, "-root": true, "--sha256": true, "-sha256": true})
 	if normErr != nil {
 		fmt.Fprintf(os.Stderr, "parse args: %v\n", normErr)
 		return 2
@@ -94,7 +109,7 @@ func runPluginInstall(args []string) int {
 		return 2
 	}
 	if len(fs.Args()) != 1 {
-		fmt.Fprintln(os.Stderr, "usage: pacto plugin install <id> [--root <path>] [--force] [--no-enable]")
+		fmt.Fprintln(os.Stderr, "usage: pacto plugin install <id|path|tarball> [--root <path>] [--sha256 <hex>] [--force] [--no-enable] [--yes]")
 		return 2
 	}
 	projectRoot, err := filepath.Abs(*root)
@@ -102,37 +117,459 @@ func runPluginInstall(args []string) int {
 		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
 		return 2
 	}
-	id := strings.ToLower(strings.TrimSpace(fs.Args()[0]))
-	res, err := builtin.Install(projectRoot, id, builtin.InstallOptions{Force: *force})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "install plugin: %v\n", err)
-		if errors.Is(err, builtin.ErrUnknownPlugin) {
+	arg := strings.TrimSpace(fs.Args()[0])
+	var id string
+	if plugins.IsLocalSource(arg) {
+		res, entry, code, ok := installPluginFromSource(projectRoot, arg, *checksum, *force)
+		if !ok {
+			return code
+		}
+		id = res.PluginID
+		fmt.Printf("Installed plugin %s from %s\n", id, entry.Source)
+		printPluginSync(res)
+		fmt.Println(pathLine("updated", plugins.LockPath(projectRoot)))
+	} else {
+		if strings.TrimSpace(*checksum) != "" {
+			fmt.Fprintln(os.Stderr, "--sha256 applies to tarball sources only")
 			return 2
 		}
+		id = strings.ToLower(arg)
+		res, err := builtin.Install(projectRoot, id, builtin.InstallOptions{Force: *force})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "install plugin: %v\n", err)
+			if errors.Is(err, builtin.ErrUnknownPlugin) {
+				return 2
+			}
+			return 3
+		}
+
+		fmt.Printf("Installed plugin %s\n", res.PluginID)
+		for _, p := range res.Created {
+			fmt.Println(pathLine("created", p))
+		}
+		for _, p := range res.Updated {
+			fmt.Println(pathLine("updated", p))
+		}
+		for _, p := range res.Skipped {
+			fmt.Println(pathLine("skipped", p))
+		}
+		if _, err := pinPlugin(projectRoot, id, builtinSource(id), plugins.SourceBuiltin, ""); err != nil {
+			fmt.Fprintf(os.Stderr, "pin plugin: %v\n", err)
+			return 3
+		}
+		fmt.Println(pathLine("updated", plugins.LockPath(projectRoot)))
+	}
+
+	if !*noEnable {
+		return approveAndEnablePlugin(projectRoot, id, *yes)
+	}
+	return 0
+}
+
+// approveAndEnablePlugin shows the plugin's effective permissions and enables
+// it once the user approves them, recording the approved permissions.
+func approveAndEnablePlugin(projectRoot, id string, yes bool) int {
+	var plugin *plugins.Plugin
+	d := plugins.Discover(projectRoot)
+	for i := range d.Plugins {
+		if d.Plugins[i].Manifest.Metadata.ID == id {
+			plugin = &d.Plugins[i]
+			break
+		}
+	}
+	if plugin == nil {
+		fmt.Fprintf(os.Stderr, "plugin not found: %s\n", id)
+		return 2
+	}
+	perm := plugins.PluginPermissions(*plugin)
+	fmt.Printf("Plugin %s scripts will run with:\n", id)
+	for _, line := range perm.Summary() {
+		fmt.Printf("  - %s\n", line)
+	}
+	if !yes {
+		approved := false
+		if isTerminal(os.Stdin) {
+			fmt.Print("Approve and enable? [y/N]: ")
+			approved = promptYesNo(false)
+		}
+		if !approved {
+			fmt.Fprintf(os.Stderr, "plugin %s not enabled: approve its permissions at the prompt or with --yes\n", id)
+			return 2
+		}
+	}
+	if err := plugins.Enable(projectRoot, id); err != nil {
+		fmt.Fprintf(os.Stderr, "enable plugin: %v\n", err)
+		return 3
+	}
+	if err := plugins.Approve(projectRoot, id, perm.Digest()); err != nil {
+		fmt.Fprintf(os.Stderr, "approve plugin: %v\n", err)
 		return 3
 	}
+	fmt.Printf("Enabled plugin %s\n", id)
+	return 0
+}
+
+func builtinSource(id string) string {
+	return plugins.SourceBuiltin + ":" + id
+}
+
+func installPluginFromSource(projectRoot, src, checksum string, force bool) (plugins.SyncResult, plugins.LockEntry, int, bool) {
+	st, err := plugins.StageSource(src)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "install plugin: %v\n", err)
+		return plugins.SyncResult{}, plugins.LockEntry{}, 2, false
+	}
+	defer st.Close()
+	if code := checkArchiveSum(st, src, checksum); code != 0 {
+		return plugins.SyncResult{}, plugins.LockEntry{}, code, false
+	}
+	res, err := plugins.SyncInstall(projectRoot, st, force)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "install plugin: %v\n", err)
+		if errors.Is(err, plugins.ErrAlreadyInstalled) {
+			fmt.Fprintln(os.Stderr, "hint: use `pacto plugin upgrade <id> --from <source>` or --force")
+			return plugins.SyncResult{}, plugins.LockEntry{}, 2, false
+		}
+		return plugins.SyncResult{}, plugins.LockEntry{}, 3, false
+	}
+	entry, err := pinPlugin(projectRoot, res.PluginID, recordedSource(projectRoot, src), st.SourceType, st.ArchiveSHA256)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pin plugin: %v\n", err)
+		return plugins.SyncResult{}, plugins.LockEntry{}, 3, false
+	}
+	return res, entry, 0, true
+}
+
+func pinPlugin(projectRoot, id, source, sourceType, archiveSum string) (plugins.LockEntry, error) {
+	version := ""
+	if st, err := plugins.StageSource(filepath.Join(projectRoot, ".pacto", "plugins", id)); err == nil {
+		version = st.Manifest.Metadata.Version
+	}
+	return plugins.PinInstalled(projectRoot, id, version, source, sourceType, archiveSum)
+}
 
-	fmt.Printf("Installed plugin %s\n", res.PluginID)
+// checkArchiveSum compares a tarball source with the checksum passed on the
+// command line, if any.
+func checkArchiveSum(st plugins.Staged, src, checksum string) int {
+	want := strings.ToLower(strings.TrimSpace(checksum))
+	if want == "" {
+		return 0
+	}
+	if st.SourceType != plugins.SourceTarball {
+		fmt.Fprintln(os.Stderr, "--sha256 applies to tarball sources only")
+		return 2
+	}
+	if st.ArchiveSHA256 != want {
+		fmt.Fprintf(os.Stderr, "checksum mismatch for %s: got %s, expected %s\n", src, st.ArchiveSHA256, want)
+		return 2
+	}
+	return 0
+}
+
+// recordedSource keeps sources inside the project relative so the lockfile
+// stays valid across checkouts.
+func recordedSource(projectRoot, src string) string {
+	abs, err := filepath.Abs(src)
+	if err != nil {
+		return src
+	}
+	if rel, err := filepath.Rel(projectRoot, abs); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return abs
+}
+
+func resolveRecordedSource(projectRoot, source string) string {
+	if filepath.IsAbs(source) {
+		return source
+	}
+	return filepath.Join(projectRoot, filepath.FromSlash(source))
+}
+
+func printPluginSync(res plugins.SyncResult) {
 	for _, p := range res.Created {
 		fmt.Println(pathLine("created", p))
 	}
 	for _, p := range res.Updated {
 		fmt.Println(pathLine("updated", p))
 	}
-	for _, p := range res.Skipped {
-		fmt.Println(pathLine("skipped", p))
+	for _, p := range res.Removed {
+		fmt.Println(pathLine("removed", p))
 	}
+}
 
-	if !*noEnable {
-		if err := plugins.Enable(projectRoot, id); err != nil {
-			fmt.Fprintf(os.Stderr, "enable plugin: %v\n", err)
+func runPluginVerify(args []string) int {
+	fs := flag.NewFlagSet("plugin verify", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	root := fs.String("root", ".", "Project root path")
+	format := fs.String("format", "table", "Output format: table|json")
+	normalizedArgs, normErr := normalizeArgs(args, map[string]bool{"--root": true, "-root": true, "--format": true, "-format": true})
+	if normErr != nil {
+		fmt.Fprintf(os.Stderr, "parse args: %v\n", normErr)
+		return 2
+	}
+	if err := fs.Parse(normalizedArgs); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "parse flags: %v\n", err)
+		return 2
+	}
+	if len(fs.Args()) > 1 {
+		fmt.Fprintln(os.Stderr, "usage: pacto plugin verify [id] [--root <path>] [--format table|json]")
+		return 2
+	}
+	projectRoot, err := filepath.Abs(*root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
+		return 2
+	}
+	lf, err := plugins.ReadLock(projectRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read lockfile: %v\n", err)
+		return 3
+	}
+	only := ""
+	if len(fs.Args()) == 1 {
+		only = strings.ToLower(strings.TrimSpace(fs.Args()[0]))
+		if _, ok := lf.Get(only); !ok {
+			fmt.Fprintf(os.Stderr, "plugin not pinned: %s\n", only)
+			return 2
+		}
+	}
+
+	drifts := make([]plugins.Drift, 0, len(lf.Plugins))
+	for _, e := range lf.Plugins {
+		if only != "" && e.ID != only {
+			continue
+		}
+		d, err := plugins.VerifyEntry(projectRoot, e)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "verify %s: %v\n", e.ID, err)
+			return 3
+		}
+		drifts = append(drifts, d)
+	}
+	unpinned := make([]string, 0)
+	if only == "" {
+		for _, p := range plugins.Discover(projectRoot).Plugins {
+			if _, ok := lf.Get(p.Manifest.Metadata.ID); !ok {
+				unpinned = append(unpinned, p.Manifest.Metadata.ID)
+			}
+		}
+		sort.Strings(unpinned)
+	}
+
+	drifted := false
+	for _, d := range drifts {
+		if !d.Clean() {
+			drifted = true
+		}
+	}
+	if strings.ToLower(strings.TrimSpace(*format)) == "json" {
+		payload := map[string]any{"plugins": drifts, "unpinned": unpinned, "drift": drifted}
+		enc, _ := json.MarshalIndent(payload, "", "  ")
+		fmt.Println(string(enc))
+	} else {
+		fmt.Println("Plugin Integrity")
+		for _, d := range drifts {
+			switch {
+			case d.Missing:
+				fmt.Printf("- %s: missing\n", d.ID)
+			case d.Clean():
+				fmt.Printf("- %s: ok\n", d.ID)
+			default:
+				fmt.Printf("- %s: drift\n", d.ID)
+				if d.ArchiveChanged {
+					fmt.Println("  archive changed since it was pinned")
+				}
+				for _, f := range d.Modified {
+					fmt.Printf("  modified: %s\n", f)
+				}
+				for _, f := range d.Added {
+					fmt.Printf("  added: %s\n", f)
+				}
+				for _, f := range d.Removed {
+					fmt.Printf("  removed: %s\n", f)
+				}
+			}
+		}
+		for _, id := range unpinned {
+			fmt.Printf("- %s: unpinned\n", id)
+		}
+	}
+	if drifted {
+		return 3
+	}
+	return 0
+}
+
+func runPluginUpgrade(args []string) int {
+	fs := flag.NewFlagSet("plugin upgrade", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	root := fs.String("root", ".", "Project root path")
+	from := fs.String("from", "", "New plugin source (directory or tarball); defaults to the pinned source")
+	dryRun := fs.Bool("dry-run", false, "Show the manifest diff without installing")
+	force := fs.Bool("force", false, "Overwrite local changes to the installed plugin")
+	checksum := fs.String("sha256", "", "Expected SHA-256 of a tarball source")
+	normalizedArgs, normErr := normalizeArgs(args, map[string]bool{"--root": true, "-root": true, "--from": true, "-from": true, "--sha256": true, "-sha256": true})
+	if normErr != nil {
+		fmt.Fprintf(os.Stderr, "parse args: %v\n", normErr)
+		return 2
+	}
+	if err := fs.Parse(normalizedArgs); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "parse flags: %v\n", err)
+		return 2
+	}
+	if len(fs.Args()) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: pacto plugin upgrade <id> [--from <path|tarball>] [--sha256 <hex>] [--root <path>] [--dry-run] [--force]")
+		return 2
+	}
+	projectRoot, err := filepath.Abs(*root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
+		return 2
+	}
+	id := strings.ToLower(strings.TrimSpace(fs.Args()[0]))
+	lf, err := plugins.ReadLock(projectRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read lockfile: %v\n", err)
+		return 3
+	}
+	entry, pinned := lf.Get(id)
+	source, sourceType := strings.TrimSpace(*from), ""
+	if source == "" {
+		if !pinned {
+			fmt.Fprintf(os.Stderr, "plugin %s is not pinned; pass --from <path|tarball>\n", id)
+			return 2
+		}
+		source, sourceType = entry.Source, entry.SourceType
+	}
+
+	st, cleanup, code, ok := stagePluginUpgrade(projectRoot, id, source, sourceType)
+	if !ok {
+		return code
+	}
+	defer cleanup()
+	if st.Manifest.Metadata.ID != id {
+		fmt.Fprintf(os.Stderr, "source provides plugin %q, not %q\n", st.Manifest.Metadata.ID, id)
+		return 2
+	}
+	if code := checkArchiveSum(st, source, *checksum); code != 0 {
+		return code
+	}
+	if *from == "" && *checksum == "" && entry.ArchiveSHA256 != "" && st.ArchiveSHA256 != entry.ArchiveSHA256 {
+		fmt.Fprintf(os.Stderr, "tarball %s changed since it was pinned (sha256 %s, pinned %s); pass --sha256 or --from to accept it\n", source, st.ArchiveSHA256, entry.ArchiveSHA256)
+		return 3
+	}
+
+	if pinned {
+		d, err := plugins.VerifyEntry(projectRoot, entry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "verify %s: %v\n", id, err)
 			return 3
 		}
-		fmt.Printf("Enabled plugin %s\n", id)
+		if d.FilesChanged() && !*force {
+			fmt.Fprintf(os.Stderr, "plugin %s has local changes since it was pinned (run `pacto plugin verify %s`); use --force to overwrite them\n", id, id)
+			return 3
+		}
+	}
+	newSum, _, err := plugins.PinDir(st.Dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hash plugin source: %v\n", err)
+		return 3
+	}
+	if pinned && newSum == entry.SHA256 && *from == "" {
+		fmt.Printf("Plugin %s is up to date (%s)\n", id, entry.Version)
+		return 0
+	}
+
+	pluginDir := filepath.Join(projectRoot, ".pacto", "plugins", id)
+	oldManifest, _ := os.ReadFile(filepath.Join(pluginDir, "plugin.yaml"))
+	newManifest, err := os.ReadFile(filepath.Join(st.Dir, "plugin.yaml"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read manifest: %v\n", err)
+		return 3
+	}
+	fmt.Printf("Upgrade plugin %s: %s -> %s\n", id, orDash(entry.Version), orDash(st.Manifest.Metadata.Version))
+	diff := plugins.DiffLines(string(oldManifest), string(newManifest))
+	if len(diff) == 0 {
+		fmt.Println(ui.Dim("  plugin.yaml unchanged"))
+	} else {
+		fmt.Println("  plugin.yaml:")
+		for _, ln := range diff {
+			fmt.Printf("    %s\n", ln)
+		}
+	}
+	if *dryRun {
+		return 0
+	}
+
+	res, err := plugins.SyncInstall(projectRoot, st, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "upgrade plugin: %v\n", err)
+		return 3
+	}
+	printPluginSync(res)
+	recorded := source
+	if *from != "" {
+		recorded = recordedSource(projectRoot, source)
+		sourceType = st.SourceType
+	}
+	if _, err := pinPlugin(projectRoot, id, recorded, sourceType, st.ArchiveSHA256); err != nil {
+		fmt.Fprintf(os.Stderr, "pin plugin: %v\n", err)
+		return 3
+	}
+	fmt.Println(pathLine("updated", plugins.LockPath(projectRoot)))
+	if cfg, err := plugins.ReadActiveConfig(projectRoot); err == nil {
+		if digest, ok := cfg.Approved[id]; ok && digest != plugins.PluginPermissions(plugins.Plugin{Manifest: st.Manifest}).Digest() {
+			fmt.Fprintf(os.Stderr, "warning: plugin %s permissions changed; run `pacto plugin enable %s` to review and approve them\n", id, id)
+		}
 	}
 	return 0
 }
 
+// stagePluginUpgrade stages the upgrade source. Built-in sources are staged
+// from the plugins embedded in this binary.
+func stagePluginUpgrade(projectRoot, id, source, sourceType string) (plugins.Staged, func(), int, bool) {
+	if sourceType == plugins.SourceBuiltin {
+		tmp, err := os.MkdirTemp("", "pacto-builtin-")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "stage plugin: %v\n", err)
+			return plugins.Staged{}, nil, 3, false
+		}
+		cleanup := func() { _ = os.RemoveAll(tmp) }
+		if _, err := builtin.Install(tmp, strings.TrimPrefix(source, plugins.SourceBuiltin+":"), builtin.InstallOptions{}); err != nil {
+			cleanup()
+			fmt.Fprintf(os.Stderr, "stage plugin: %v\n", err)
+			return plugins.Staged{}, nil, 3, false
+		}
+		st, err := plugins.StageSource(filepath.Join(tmp, ".pacto", "plugins", id))
+		if err != nil {
+			cleanup()
+			fmt.Fprintf(os.Stderr, "stage plugin: %v\n", err)
+			return plugins.Staged{}, nil, 3, false
+		}
+		return st, cleanup, 0, true
+	}
+	st, err := plugins.StageSource(resolveRecordedSource(projectRoot, source))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "stage plugin: %v\n", err)
+		return plugins.Staged{}, nil, 2, false
+	}
+	return st, st.Close, 0, true
+}
+
+func orDash(v string) string {
+	if strings.TrimSpace(v) == "" {
+		return "-"
+	}
+	return v
+}
+
 func runPluginList(args []string) int {
 	fs := flag.NewFlagSet("plugin list", flag.ContinueOnError)
 	fs.SetOutput(os.Stderr)
@@ -165,20 +602,48 @@ func runPluginList(args []string) int {
 	}
 	d := plugins.Discover(projectRoot)
 	type row struct {
-		ID       string `json:"id"`
-		Version  string `json:"version"`
-		Priority int    `json:"priority"`
-		Enabled  bool   `json:"enabled"`
-		Path     string `json:"path"`
+		ID          string              `json:"id"`
+		Version     string              `json:"version"`
+		APIVersion  string              `json:"api_version"`
+		Priority    int                 `json:"priority"`
+		Enabled     bool                `json:"enabled"`
+		Requires    string              `json:"requires_pacto,omitempty"`
+		DependsOn   []string            `json:"depends_on,omitempty"`
+		Commands    []string            `json:"commands,omitempty"`
+		Permissions plugins.Permissions `json:"permissions"`
+		Approval    string              `json:"approval,omitempty"`
+		Path        string              `json:"path"`
 	}
 	rows := make([]row, 0, len(d.Plugins))
 	for _, p := range d.Plugins {
+		deps := make([]string, 0, len(p.Manifest.Spec.DependsOn))
+		for _, dep := range p.Manifest.Spec.DependsOn {
+			deps = append(deps, strings.TrimSpace(dep.ID+" "+dep.Version))
+		}
+		cmds := make([]string, 0, len(p.Manifest.Spec.Commands))
+		for _, c := range p.Manifest.Spec.Commands {
+			cmds = append(cmds, c.Name)
+		}
+		perm := plugins.PluginPermissions(p)
+		approval := ""
+		if digest, ok := activeCfg.Approved[p.Manifest.Metadata.ID]; ok {
+			approval = "approved"
+			if digest != perm.Digest() {
+				approval = "changed"
+			}
+		}
 		rows = append(rows, row{
-			ID:       p.Manifest.Metadata.ID,
-			Version:  p.Manifest.Metadata.Version,
-			Priority: p.Manifest.Metadata.Priority,
-			Enabled:  enabled[p.Manifest.Metadata.ID],
-			Path:     p.Dir,
+			Permissions: perm,
+			Approval:    approval,
+			ID:          p.Manifest.Metadata.ID,
+			Version:     p.Manifest.Metadata.Version,
+			APIVersion:  p.Manifest.APIVersion,
+			Priority:    p.Manifest.Metadata.Priority,
+			Enabled:     enabled[p.Manifest.Metadata.ID],
+			Requires:    p.Manifest.Spec.Requires.Pacto,
+			DependsOn:   deps,
+			Commands:    cmds,
+			Path:        p.Dir,
 		})
 	}
 	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
@@ -198,6 +663,22 @@ func runPluginList(args []string) int {
 			state = "enabled"
 		}
 		fmt.Printf("- %s (%s) priority=%d state=%s\n", r.ID, r.Version, r.Priority, state)
+		if r.Requires != "" {
+			fmt.Printf("  requires: pacto %s\n", r.Requires)
+		}
+		if len(r.DependsOn) > 0 {
+			fmt.Printf("  depends on: %s\n", strings.Join(r.DependsOn, ", "))
+		}
+		if len(r.Commands) > 0 {
+			fmt.Printf("  commands: %s\n", strings.Join(r.Commands, ", "))
+		}
+		fmt.Println("  permissions:")
+		for _, line := range r.Permissions.Summary() {
+			fmt.Printf("    - %s\n", line)
+		}
+		if r.Approval == "changed" {
+			fmt.Printf("    ! permissions changed since approval; run `pacto plugin enable %s` to review them\n", r.ID)
+		}
 		fmt.Printf("  path: %s\n", r.Path)
 	}
 	for _, err := range d.Errors {
@@ -241,29 +722,54 @@ func runPluginValidate(args []string) int {
 			return 2
 		}
 	}
-	if len(d.Errors) > 0 {
-		for _, e := range d.Errors {
+	errs := append([]error{}, d.Errors...)
+	_, resolveErrs := plugins.Resolve(d.Plugins)
+	_, activeErrs := plugins.LoadActive(projectRoot)
+	seen := map[string]bool{}
+	for _, e := range errs {
+		seen[e.Error()] = true
+	}
+	for _, e := range append(resolveErrs, activeErrs...) {
+		if !seen[e.Error()] {
+			seen[e.Error()] = true
+			errs = append(errs, e)
+		}
+	}
+	if len(errs) > 0 {
+		for _, e := range errs {
 			fmt.Fprintf(os.Stderr, "plugin validation error: %v\n", e)
 		}
 		return 3
 	}
 	fmt.Printf("Validated %d plugin(s)\n", len(d.Plugins))
+	for _, p := range d.Plugins {
+		if p.Manifest.APIVersion == plugins.ManifestAPIVersion {
+			fmt.Printf("- %s uses %s; run `pacto plugin migrate %s` to upgrade to %s\n", p.Manifest.Metadata.ID, p.Manifest.APIVersion, p.Manifest.Metadata.ID, plugins.ManifestAPIVersionV1Beta1)
+		}
+	}
 	return 0
 }
 
-func runPluginEnable(args []string) int {
-	fs := flag.NewFlagSet("plugin enable", flag.ContinueOnError)
+func runPluginMigrate(args []string) int {
+	fs := flag.NewFlagSet("plugin migrate", flag.ContinueOnError)
 	fs.SetOutput(os.Stderr)
 	root := fs.String("root", ".", "Project root path")
-	if err := fs.Parse(args); err != nil {
+	all := fs.Bool("all", false, "Migrate every installed plugin")
+	dryRun := fs.Bool("dry-run", false, "Print the manifest diff without writing")
+	normalizedArgs, normErr := normalizeArgs(args, map[string]bool{"--root": true, "-root": true})
+	if normErr != nil {
+		fmt.Fprintf(os.Stderr, "parse args: %v\n", normErr)
+		return 2
+	}
+	if err := fs.Parse(normalizedArgs); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			return 0
 		}
 		fmt.Fprintf(os.Stderr, "parse flags: %v\n", err)
 		return 2
 	}
-	if len(fs.Args()) != 1 {
-		fmt.Fprintln(os.Stderr, "usage: pacto plugin enable <id> [--root <path>]")
+	if (len(fs.Args()) == 1) == *all || len(fs.Args()) > 1 {
+		fmt.Fprintln(os.Stderr, "usage: pacto plugin migrate <id>|--all [--root <path>] [--dry-run]")
 		return 2
 	}
 	projectRoot, err := filepath.Abs(*root)
@@ -271,27 +777,194 @@ func runPluginEnable(args []string) int {
 		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
 		return 2
 	}
-	id := strings.ToLower(strings.TrimSpace(fs.Args()[0]))
-	d := plugins.Discover(projectRoot)
-	exists := false
-	for _, p := range d.Plugins {
-		if p.Manifest.Metadata.ID == id {
-			exists = true
-			break
+	pluginsRoot := filepath.Join(projectRoot, ".pacto", "plugins")
+	var ids []string
+	if *all {
+		ents, err := os.ReadDir(pluginsRoot)
+		if err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "read plugins dir: %v\n", err)
+			return 3
+		}
+		for _, ent := range ents {
+			if _, err := os.Stat(filepath.Join(pluginsRoot, ent.Name(), "plugin.yaml")); ent.IsDir() && err == nil {
+				ids = append(ids, ent.Name())
+			}
+		}
+	} else {
+		ids = []string{strings.ToLower(strings.TrimSpace(fs.Args()[0]))}
+		if _, err := os.Stat(filepath.Join(pluginsRoot, ids[0], "plugin.yaml")); err != nil {
+			fmt.Fprintf(os.Stderr, "plugin not installed: %s\n", ids[0])
+			return 2
 		}
 	}
-	if !exists {
-		fmt.Fprintf(os.Stderr, "plugin not found: %s\n", id)
+	failed := false
+	for _, id := range ids {
+		dir := filepath.Join(pluginsRoot, id)
+		mig, err := plugins.MigrateManifest(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate %s: %v\n", id, err)
+			failed = true
+			continue
+		}
+		if !mig.Changed() {
+			fmt.Printf("Plugin %s already uses %s\n", id, mig.To)
+			continue
+		}
+		fmt.Printf("Migrate plugin %s: %s -> %s\n", id, mig.From, mig.To)
+		for _, line := range plugins.DiffLines(string(mig.Before), string(mig.After)) {
+			fmt.Printf("  %s\n", line)
+		}
+		for _, note := range mig.Notes {
+			fmt.Printf("  note: %s\n", note)
+		}
+		if *dryRun {
+			continue
+		}
+		manifest := filepath.Join(dir, "plugin.yaml")
+		if err := fsutil.WriteFileAtomic(manifest, mig.After, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "write %s: %v\n", manifest, err)
+			failed = true
+			continue
+		}
+		fmt.Println(pathLine("updated", manifest))
+	}
+	if failed {
+		return 3
+	}
+	return 0
+}
+
+func runPluginSchema(args []string) int {
+	fs := flag.NewFlagSet("plugin schema", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	apiVersion := fs.String("api-version", plugins.ManifestAPIVersionV1Beta1, "Manifest apiVersion")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "parse flags: %v\n", err)
 		return 2
 	}
-	if err := plugins.Enable(projectRoot, id); err != nil {
-		fmt.Fprintf(os.Stderr, "enable plugin: %v\n", err)
+	if len(fs.Args()) > 0 {
+		fmt.Fprintln(os.Stderr, "plugin schema does not accept positional args")
+		return 2
+	}
+	b, err := plugins.ManifestSchema(*apiVersion)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	fmt.Print(string(b))
+	return 0
+}
+
+func runPluginReport(args []string) int {
+	fs := flag.NewFlagSet("plugin report", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	root := fs.String("root", ".", "Project root path")
+	since := fs.String("since", "30d", "Time window to summarize (e.g. 7d, 4w, 48h)")
+	slow := fs.Duration("slow", 5*time.Second, "Count hooks taking at least this long as slow")
+	format := fs.String("format", "table", "Output format: table|json")
+	normalizedArgs, normErr := normalizeArgs(args, map[string]bool{
+		"--root": true, "-root": true, "--since": true, "-since": true,
+		"--slow": true, "-slow": true, "--format": true, "-format": true,
+	})
+	if normErr != nil {
+		fmt.Fprintf(os.Stderr, "parse args: %v\n", normErr)
+		return 2
+	}
+	if err := fs.Parse(normalizedArgs); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "parse flags: %v\n", err)
+		return 2
+	}
+	if len(fs.Args()) > 1 {
+		fmt.Fprintln(os.Stderr, "usage: pacto plugin report [id] [--since <age>] [--slow <duration>] [--root <path>] [--format table|json]")
+		return 2
+	}
+	window, err := parseAge(*since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --since %q: %v\n", *since, err)
+		return 2
+	}
+	projectRoot, err := filepath.Abs(*root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
+		return 2
+	}
+	entries, err := plugins.ReadAudit(projectRoot, time.Now().Add(-window))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read audit log: %v\n", err)
 		return 3
 	}
-	fmt.Printf("Enabled plugin %s\n", id)
+	if len(fs.Args()) == 1 {
+		only := strings.ToLower(strings.TrimSpace(fs.Args()[0]))
+		kept := entries[:0]
+		for _, e := range entries {
+			if e.Plugin == only {
+				kept = append(kept, e)
+			}
+		}
+		entries = kept
+	}
+	report := plugins.SummarizeAudit(entries, *slow)
+
+	if strings.ToLower(strings.TrimSpace(*format)) == "json" {
+		payload := map[string]any{"since": *since, "slow_ms": slow.Milliseconds(), "entries": len(entries), "plugins": report}
+		enc, _ := json.MarshalIndent(payload, "", "  ")
+		fmt.Println(string(enc))
+		return 0
+	}
+	fmt.Printf("Guardrail Report (last %s)\n", *since)
+	if len(report) == 0 {
+		fmt.Println("- no guardrail results recorded")
+		return 0
+	}
+	line := func(s plugins.AuditStats) string {
+		return fmt.Sprintf("%s: %d runs, %d passed, %d blocked, %d bypassed, %d warned, %d failed, %d timed out, %d slow (max %dms, avg %dms)",
+			s.ID, s.Runs, s.Passed, s.Blocked, s.Bypassed, s.Warned, s.Failed, s.TimedOut, s.Slow, s.MaxDurationMS, s.AvgDurationMS)
+	}
+	for _, p := range report {
+		fmt.Printf("- %s\n", line(p.AuditStats))
+		for _, g := range p.Guardrails {
+			fmt.Printf("  %s\n", line(g))
+		}
+	}
 	return 0
 }
 
+func runPluginEnable(args []string) int {
+	fs := flag.NewFlagSet("plugin enable", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	root := fs.String("root", ".", "Project root path")
+	yes := fs.Bool("yes", false, "Approve the plugin permissions without prompting")
+	normalizedArgs, normErr := normalizeArgs(args, map[string]bool{"--root": true, "-root": true})
+	if normErr != nil {
+		fmt.Fprintf(os.Stderr, "parse args: %v\n", normErr)
+		return 2
+	}
+	if err := fs.Parse(normalizedArgs); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "parse flags: %v\n", err)
+		return 2
+	}
+	if len(fs.Args()) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: pacto plugin enable <id> [--root <path>] [--yes]")
+		return 2
+	}
+	projectRoot, err := filepath.Abs(*root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
+		return 2
+	}
+	id := strings.ToLower(strings.TrimSpace(fs.Args()[0]))
+	return approveAndEnablePlugin(projectRoot, id, *yes)
+}
+
 func runPluginDisable(args []string) int {
 	fs := flag.NewFlagSet("plugin disable", flag.ContinueOnError)
 	fs.SetOutput(os.Stderr)
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"pacto/internal/plugins"
 )
@@ -31,6 +32,23 @@ func TestRunBlocksGuardrailOnMutatingCommand(t *testing.T) {
 	}
 }
 
+func TestRunSkipsGuardrailsInsideHooks(t *testing.T) {
+	root := t.TempDir()
+	writeTestPlugin(t, root, "acme", "block-explore", []string{"explore"}, "#!/bin/sh\nexit 2\n")
+	if err := plugins.WriteActiveConfig(root, []string{"acme"}); err != nil {
+		t.Fatal(err)
+	}
+	oldWD, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWD) }()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(plugins.InHookEnv, "1")
+	if code := Run([]string{"explore", "idea-1"}); code != 0 {
+		t.Fatalf("Run returned %d inside a hook, want 0", code)
+	}
+}
+
 func TestRunAllowsSpecificGuardrailBypass(t *testing.T) {
 	root := t.TempDir()
 	writeTestPlugin(t, root, "acme", "block-explore", []string{"explore"}, "#!/bin/sh\nexit 2\n")
@@ -203,8 +221,21 @@ func TestRunPluginCommands(t *testing.T) {
 		t.Fatalf("expected plugin in list output, got %q", stdout)
 	}
 
-	if code := Run([]string{"plugin", "enable", "acme"}); code != 0 {
-		t.Fatalf("enable returned %d", code)
+	_, stderr := captureOutput(t, func() {
+		if code := Run([]string{"plugin", "enable", "acme"}); code != 2 {
+			t.Fatalf("enable without approval returned %d, want 2", code)
+		}
+	})
+	if !strings.Contains(stderr, "or with --yes") {
+		t.Fatalf("expected approval hint, got %q", stderr)
+	}
+	stdout, _ = captureOutput(t, func() {
+		if code := Run([]string{"plugin", "enable", "acme", "--yes"}); code != 0 {
+			t.Fatalf("enable returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, "unrestricted: full environment") {
+		t.Fatalf("expected permissions summary, got %q", stdout)
 	}
 	cfg, err := plugins.ReadActiveConfig(root)
 	if err != nil {
@@ -213,6 +244,9 @@ func TestRunPluginCommands(t *testing.T) {
 	if len(cfg.Enabled) != 1 || cfg.Enabled[0] != "acme" {
 		t.Fatalf("unexpected enabled list: %#v", cfg.Enabled)
 	}
+	if cfg.Approved["acme"] == "" {
+		t.Fatalf("expected approval recorded, got %#v", cfg.Approved)
+	}
 
 	if code := Run([]string{"plugin", "disable", "acme"}); code != 0 {
 		t.Fatalf("disable returned %d", code)
@@ -252,7 +286,7 @@ func TestRunPluginInstallAutoEnablesByDefault(t *testing.T) {
 		t.Fatal(err)
 	}
 	stdout, _ := captureOutput(t, func() {
-		code := Run([]string{"plugin", "install", "git-sync"})
+		code := Run([]string{"plugin", "install", "git-sync", "--yes"})
 		if code != 0 {
 			t.Fatalf("Run returned %d, want 0", code)
 		}
@@ -352,7 +386,372 @@ func TestRunPluginValidateFailsForInvalidManifest(t *testing.T) {
 	}
 }
 
+func TestRunPostHookReceivesMoveOutcome(t *testing.T) {
+	root := t.TempDir()
+	captureOutput(t, func() {
+		if code := RunInit([]string{"--root", root, "--no-interactive", "--no-install"}); code != 0 {
+			t.Fatalf("RunInit returned %d", code)
+		}
+	})
+	script := "#!/bin/sh\n" +
+		"{ echo \"$PACTO_HOOK_PHASE $PACTO_EXIT_CODE $PACTO_FROM_STATE $PACTO_TO_STATE $PACTO_SLUG\"; echo \"$PACTO_TOUCHED_FILES\"; } >> \"$PACTO_PROJECT_ROOT/hook.log\"\n" +
+		"exit 1\n"
+	writeTestPhasePlugin(t, root, "tracker", "sync", plugins.PhasePost, []string{"new", "move"}, script)
+	writeTestPhasePlugin(t, root, "alerts", "notify", plugins.PhaseOnError, []string{"move"}, "#!/bin/sh\necho \"$PACTO_HOOK_PHASE $PACTO_EXIT_CODE\" >> \"$PACTO_PROJECT_ROOT/error.log\"\n")
+	if err := plugins.WriteActiveConfig(root, []string{"tracker", "alerts"}); err != nil {
+		t.Fatal(err)
+	}
+	oldWD, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWD) }()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	captureOutput(t, func() {
+		if code := Run([]string{"new", "to-implement", "hooked"}); code != 0 {
+			t.Fatalf("Run new returned %d", code)
+		}
+	})
+	if err := os.Remove(filepath.Join(root, "hook.log")); err != nil {
+		t.Fatalf("expected post hook after new: %v", err)
+	}
+
+	_, stderr := captureOutput(t, func() {
+		if code := Run([]string{"move", "to-implement", "hooked", "current"}); code != 0 {
+			t.Fatalf("Run move returned %d, want 0 despite failing post hook", code)
+		}
+	})
+	if !strings.Contains(stderr, "post hook failed: tracker/sync") {
+		t.Fatalf("expected post hook warning, got %q", stderr)
+	}
+	b, err := os.ReadFile(filepath.Join(root, "hook.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := string(b)
+	if !strings.HasPrefix(log, "post 0 to-implement current hooked\n") || !strings.Contains(log, ".pacto/plans/current/hooked") {
+		t.Fatalf("unexpected hook outcome: %q", log)
+	}
+	if _, err := os.Stat(filepath.Join(root, "error.log")); !os.IsNotExist(err) {
+		t.Fatalf("onError hook must not run after success")
+	}
+
+	captureOutput(t, func() {
+		if code := Run([]string{"move", "to-implement", "missing", "current"}); code == 0 {
+			t.Fatalf("expected move of missing plan to fail")
+		}
+	})
+	b, err = os.ReadFile(filepath.Join(root, "error.log"))
+	if err != nil {
+		t.Fatalf("expected onError hook to run: %v", err)
+	}
+	if strings.TrimSpace(string(b)) == "" || !strings.HasPrefix(string(b), "onError ") || strings.HasPrefix(string(b), "onError 0") {
+		t.Fatalf("unexpected onError outcome: %q", string(b))
+	}
+}
+
+func TestRunStatusMergesJSONHookAnnotations(t *testing.T) {
+	root := t.TempDir()
+	captureOutput(t, func() {
+		if code := RunInit([]string{"--root", root, "--no-interactive", "--no-install"}); code != 0 {
+			t.Fatalf("RunInit returned %d", code)
+		}
+		if code := RunNew([]string{"current", "auth", "--root", root}); code != 0 {
+			t.Fatalf("RunNew returned %d", code)
+		}
+	})
+	script := "#!/bin/sh\n" +
+		"grep -q '\"report\"' || exit 9\n" +
+		`echo '{"decision":"allow","annotations":[{"plan":"current/auth","key":"jira","value":"PRJ-7"}],"next_actions":[{"text":"sync tracker"}]}'` + "\n"
+	writeTestPhasePlugin(t, root, "tracker", "annotate", plugins.PhasePre, []string{"status"}, script)
+	manifest := filepath.Join(root, ".pacto", "plugins", "tracker", "plugin.yaml")
+	b, err := os.ReadFile(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(manifest, []byte(strings.Replace(string(b), "timeoutMs: 1000\n", "timeoutMs: 1000\n        protocol: json\n", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := plugins.WriteActiveConfig(root, []string{"tracker"}); err != nil {
+		t.Fatal(err)
+	}
+	oldWD, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWD) }()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := captureOutput(t, func() {
+		if code := Run([]string{"status", "--format", "json"}); code != 0 {
+			t.Fatalf("Run status returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, `"jira": "PRJ-7"`) || !strings.Contains(stdout, `"sync tracker"`) {
+		t.Fatalf("expected hook annotations in report, got %q (stderr %q)", stdout, stderr)
+	}
+}
+
+func TestRunPluginInstallFromPathPinsAndVerifies(t *testing.T) {
+	root := t.TempDir()
+	srcRoot := t.TempDir()
+	writeTestPlugin(t, srcRoot, "acme", "clean", []string{"new"}, "#!/bin/sh\nexit 0\n")
+	src := filepath.Join(srcRoot, ".pacto", "plugins", "acme")
+
+	stdout, _ := captureOutput(t, func() {
+		if code := RunPlugin([]string{"install", src, "--root", root, "--yes"}); code != 0 {
+			t.Fatalf("install from path returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, "Installed plugin acme from "+src) {
+		t.Fatalf("unexpected install output: %q", stdout)
+	}
+	lf, err := plugins.ReadLock(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := lf.Get("acme")
+	if !ok || entry.SourceType != plugins.SourcePath || entry.Source != src || len(entry.SHA256) != 64 {
+		t.Fatalf("unexpected lock entry: %#v", entry)
+	}
+	cfg, err := plugins.ReadActiveConfig(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Enabled) != 1 || cfg.Enabled[0] != "acme" {
+		t.Fatalf("expected plugin enabled, got %#v", cfg.Enabled)
+	}
+
+	script := filepath.Join(root, ".pacto", "plugins", "acme", "scripts", "check.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	stdout, _ = captureOutput(t, func() {
+		if code := RunPlugin([]string{"verify", "--root", root}); code != 3 {
+			t.Fatalf("verify with drift returned %d, want 3", code)
+		}
+	})
+	if !strings.Contains(stdout, "acme: drift") || !strings.Contains(stdout, "modified: scripts/check.sh") {
+		t.Fatalf("expected drift report, got %q", stdout)
+	}
+
+	manifest := filepath.Join(src, "plugin.yaml")
+	b, err := os.ReadFile(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(manifest, []byte(strings.Replace(string(b), "version: 0.1.0", "version: 0.2.0", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, stderr := captureOutput(t, func() {
+		if code := RunPlugin([]string{"upgrade", "acme", "--root", root}); code != 3 {
+			t.Fatalf("upgrade over local changes returned %d, want 3", code)
+		}
+	})
+	if !strings.Contains(stderr, "local changes") {
+		t.Fatalf("expected local changes error, got %q", stderr)
+	}
+	stdout, _ = captureOutput(t, func() {
+		if code := RunPlugin([]string{"upgrade", "acme", "--force", "--root", root}); code != 0 {
+			t.Fatalf("forced upgrade returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, "0.1.0 -> 0.2.0") || !strings.Contains(stdout, "+   version: 0.2.0") {
+		t.Fatalf("expected manifest diff, got %q", stdout)
+	}
+	captureOutput(t, func() {
+		if code := RunPlugin([]string{"verify", "acme", "--root", root}); code != 0 {
+			t.Fatalf("verify after upgrade returned %d", code)
+		}
+	})
+	lf, _ = plugins.ReadLock(root)
+	if entry, _ := lf.Get("acme"); entry.Version != "0.2.0" {
+		t.Fatalf("expected lock version updated, got %#v", entry)
+	}
+}
+
+func TestRunPluginMigrateAndValidateDependencies(t *testing.T) {
+	root := t.TempDir()
+	writeTestPlugin(t, root, "acme", "clean", []string{"new"}, "#!/bin/sh\nexit 0\n")
+	writeTestPlugin(t, root, "tracker", "sync", []string{"new"}, "#!/bin/sh\nexit 0\n")
+
+	stdout, _ := captureOutput(t, func() {
+		if code := RunPlugin([]string{"migrate", "--all", "--dry-run", "--root", root}); code != 0 {
+			t.Fatalf("migrate --dry-run returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, "Migrate plugin acme: pacto/v1alpha1 -> pacto/v1beta1") || !strings.Contains(stdout, "+ apiVersion: pacto/v1beta1") {
+		t.Fatalf("unexpected dry-run output: %q", stdout)
+	}
+	trackerManifest := filepath.Join(root, ".pacto", "plugins", "tracker", "plugin.yaml")
+	if b, _ := os.ReadFile(trackerManifest); !strings.Contains(string(b), "pacto/v1alpha1") {
+		t.Fatalf("dry-run must not write the manifest")
+	}
+	captureOutput(t, func() {
+		if code := RunPlugin([]string{"migrate", "tracker", "--root", root}); code != 0 {
+			t.Fatalf("migrate returned %d", code)
+		}
+	})
+	b, err := os.ReadFile(trackerManifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := strings.Replace(string(b), "pacto/v1alpha1", "pacto/v1beta1", 1)
+	manifest += "  dependsOn:\n    - id: acme\n      version: \">=0.2.0\"\n"
+	if err := os.WriteFile(trackerManifest, []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := plugins.WriteActiveConfig(root, []string{"tracker"}); err != nil {
+		t.Fatal(err)
+	}
+	_, stderr := captureOutput(t, func() {
+		if code := RunPlugin([]string{"validate", "--root", root}); code != 3 {
+			t.Fatalf("validate returned %d, want 3", code)
+		}
+	})
+	if !strings.Contains(stderr, "tracker depends on acme >=0.2.0, found 0.1.0") {
+		t.Fatalf("expected dependency constraint error, got %q", stderr)
+	}
+
+	stdout, _ = captureOutput(t, func() {
+		if code := RunPlugin([]string{"schema", "--api-version", "v1beta1"}); code != 0 {
+			t.Fatalf("schema returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, `"dependsOn"`) {
+		t.Fatalf("expected v1beta1 schema, got %q", stdout)
+	}
+}
+
+func TestRunAuditsGuardrailResultsAndReports(t *testing.T) {
+	root := t.TempDir()
+	writeTestPlugin(t, root, "acme", "block-explore", []string{"explore"}, "#!/bin/sh\necho dirty >&2\nexit 2\n")
+	writeTestPlugin(t, root, "lint", "pass-explore", []string{"explore"}, "#!/bin/sh\nexit 0\n")
+	if err := plugins.WriteActiveConfig(root, []string{"acme", "lint"}); err != nil {
+		t.Fatal(err)
+	}
+	oldWD, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWD) }()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	captureOutput(t, func() {
+		if code := Run([]string{"explore", "idea-1"}); code != 3 {
+			t.Fatalf("blocked explore returned %d, want 3", code)
+		}
+		if code := Run([]string{"--allow-guardrail", "acme/block-explore", "explore", "idea-1"}); code != 0 {
+			t.Fatalf("bypassed explore returned %d, want 0", code)
+		}
+	})
+
+	entries, err := plugins.ReadAudit(root, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 || entries[0].Outcome != plugins.AuditBlocked || entries[1].Outcome != plugins.AuditPassed ||
+		entries[2].Outcome != plugins.AuditBypassed || entries[3].Outcome != plugins.AuditPassed {
+		t.Fatalf("unexpected audit entries: %#v", entries)
+	}
+	if entries[2].Command != "explore" || entries[2].ExitCode != 2 || entries[2].Stderr != "dirty" {
+		t.Fatalf("audit entry missing details: %#v", entries[2])
+	}
+
+	stdout, _ := captureOutput(t, func() {
+		if code := RunPlugin([]string{"report", "--since", "7d"}); code != 0 {
+			t.Fatalf("plugin report returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, "acme/block-explore: 2 runs, 0 passed, 1 blocked, 1 bypassed") ||
+		!strings.Contains(stdout, "lint/pass-explore: 2 runs, 2 passed, 0 blocked") {
+		t.Fatalf("unexpected report: %q", stdout)
+	}
+	stdout, _ = captureOutput(t, func() {
+		if code := RunPlugin([]string{"report", "other", "--format", "json"}); code != 0 {
+			t.Fatalf("plugin report returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, `"entries": 0`) {
+		t.Fatalf("expected filtered report to be empty, got %q", stdout)
+	}
+}
+
+func TestRunDispatchesPluginCommand(t *testing.T) {
+	root := t.TempDir()
+	pluginDir := filepath.Join(root, ".pacto", "plugins", "acme")
+	if err := os.MkdirAll(filepath.Join(pluginDir, "scripts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\ncat\necho\necho \"cmd=$PACTO_PLUGIN_COMMAND args=$PACTO_ARGS\"\necho oops >&2\nexit 5\n"
+	if err := os.WriteFile(filepath.Join(pluginDir, "scripts", "notes.sh"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `apiVersion: pacto/v1beta1
+kind: Plugin
+metadata:
+  id: acme
+  version: 0.1.0
+spec:
+  commands:
+    - name: release-notes
+      summary: Draft release notes from done plans.
+      usage: pacto release-notes [--since <tag>]
+      examples: ["pacto release-notes --since v1.2.0"]
+      run:
+        script: scripts/notes.sh
+`
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.yaml"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := plugins.WriteActiveConfig(root, []string{"acme"}); err != nil {
+		t.Fatal(err)
+	}
+	oldWD, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWD) }()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr := captureOutput(t, func() {
+		if code := Run([]string{"release-notes", "--since", "v1.2.0"}); code != 5 {
+			t.Fatalf("Run returned %d, want the script's exit code 5", code)
+		}
+	})
+	if !strings.Contains(stdout, `"command":"release-notes"`) || !strings.Contains(stdout, `"flags":{"since":"v1.2.0"}`) {
+		t.Fatalf("expected JSON request on stdin, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "cmd=release-notes args=--since v1.2.0") || !strings.Contains(stderr, "oops") {
+		t.Fatalf("expected script output passed through, got %q / %q", stdout, stderr)
+	}
+
+	stdout, _ = captureOutput(t, func() {
+		if code := Run([]string{"help"}); code != 0 {
+			t.Fatalf("help returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, "Plugin commands:\n  release-notes Draft release notes from done plans.") {
+		t.Fatalf("expected plugin commands in root help, got %q", stdout)
+	}
+	stdout, _ = captureOutput(t, func() {
+		if code := Run([]string{"release-notes", "--help"}); code != 0 {
+			t.Fatalf("plugin command help returned %d", code)
+		}
+	})
+	if !strings.Contains(stdout, "pacto release-notes [--since <tag>]") || !strings.Contains(stdout, "(plugin acme)") {
+		t.Fatalf("unexpected plugin command help: %q", stdout)
+	}
+}
+
+func TestBuiltinCommandsAreReservedForPlugins(t *testing.T) {
+	for _, c := range commandCatalog() {
+		if !plugins.IsReservedCommand(c.Name) {
+			t.Fatalf("built-in command %q is not reserved for plugins", c.Name)
+		}
+	}
+}
+
 func writeTestPlugin(t *testing.T, root, pluginID, guardrailID string, commands []string, script string) {
+	t.Helper()
+	writeTestPhasePlugin(t, root, pluginID, guardrailID, plugins.PhasePre, commands, script)
+}
+
+func writeTestPhasePlugin(t *testing.T, root, pluginID, guardrailID, phase string, commands []string, script string) {
 	t.Helper()
 	pluginDir := filepath.Join(root, ".pacto", "plugins", pluginID)
 	if err := os.MkdirAll(filepath.Join(pluginDir, "scripts"), 0o755); err != nil {
@@ -376,6 +775,7 @@ func writeTestPlugin(t *testing.T, root, pluginID, guardrailID string, commands
 		"spec:\n" +
 		"  cliGuardrails:\n" +
 		"    - id: " + guardrailID + "\n" +
+		"      phase: " + phase + "\n" +
 		"      commands: [" + strings.Join(commands, ",") + "]\n" +
 		"      run:\n" +
 		"        script: scripts/check.sh\n" +
@@ -0,0 +1,856 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"pacto/internal/discovery"
+	"pacto/internal/fsutil"
+	"pacto/internal/i18n"
+	"pacto/internal/journal"
+	"pacto/internal/states"
+	"pacto/internal/ui"
+)
+
+const redirectFileName = "MOVED.md"
+
+var (
+	rePhaseNumber   = regexp.MustCompile(`(?i)^(\s*##\s*phase\s+)([1-9][0-9]*)`)
+	reProgressRow   = regexp.MustCompile(`(?i)^(\s*\|\s*(?:phase|fase)\s+)([1-9][0-9]*)(\s*\|)`)
+	reStepRefInText = regexp.MustCompile(`\b([1-9][0-9]*)\.([1-9][0-9]*)\b`)
+)
+
+type restructureOptions struct {
+	root   string
+	title  string
+	phases string
+	dryRun bool
+}
+
+func parseRestructureArgs(name string, args []string, withPhases bool) (restructureOptions, []string, int, bool) {
+	opts := restructureOptions{}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.StringVar(&opts.root, "root", "", "Project root path (auto-discovers when omitted)")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "Show intended changes without writing files")
+	withValue := map[string]bool{"--root": true, "-root": true}
+	if name != "merge" {
+		fs.StringVar(&opts.title, "title", "", "Title for the resulting plan")
+		withValue["--title"] = true
+		withValue["-title"] = true
+	}
+	if withPhases {
+		fs.StringVar(&opts.phases, "phases", "", "Phases to move into the new plan (e.g. 2,3 or 2-4)")
+		withValue["--phases"] = true
+		withValue["-phases"] = true
+	}
+	normalizedArgs, normErr := normalizeArgs(args, withValue)
+	if normErr != nil {
+		fmt.Fprintf(os.Stderr, "parse args: %v\n", normErr)
+		return restructureOptions{}, nil, 2, false
+	}
+	if err := fs.Parse(normalizedArgs); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return restructureOptions{}, nil, 0, false
+		}
+		fmt.Fprintf(os.Stderr, "parse flags: %v\n", err)
+		return restructureOptions{}, nil, 2, false
+	}
+	return opts, fs.Args(), 0, true
+}
+
+func validatePlanArgs(sm states.Machine, pairs ...string) int {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if code := requireStates(sm, pairs[i]); code != 0 {
+			return code
+		}
+		if !slugRe.MatchString(pairs[i+1]) {
+			fmt.Fprintf(os.Stderr, "invalid slug %q (use lowercase letters, numbers, dashes)\n", pairs[i+1])
+			return 2
+		}
+	}
+	return 0
+}
+
+func RunRename(args []string) int {
+	opts, pos, code, ok := parseRestructureArgs("rename", args, false)
+	if !ok {
+		return code
+	}
+	if len(pos) != 3 {
+		fmt.Fprintln(os.Stderr, "rename requires <state> <old-slug> <new-slug>")
+		return 2
+	}
+	state := strings.ToLower(strings.TrimSpace(pos[0]))
+	oldSlug := strings.TrimSpace(pos[1])
+	newSlug := strings.TrimSpace(pos[2])
+	if oldSlug == newSlug {
+		fmt.Fprintln(os.Stderr, "old and new slugs are the same")
+		return 2
+	}
+
+	plansRoot, err := resolvePlansRootForAction(opts.root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
+		return 2
+	}
+	sm, code, ok := loadStateMachine(plansRoot)
+	if !ok {
+		return code
+	}
+	if code := validatePlanArgs(sm, state, oldSlug, state, newSlug); code != 0 {
+		return code
+	}
+	lang := effectiveLanguage(filepath.Dir(plansRoot))
+	lock, code, ok := lockWorkspace(plansRoot, lang)
+	if !ok {
+		return code
+	}
+	defer lock.Release()
+
+	ref, err := resolvePlanRef(plansRoot, state, oldSlug)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve plan: %v\n", err)
+		return 2
+	}
+	dstDir := filepath.Join(sm.Dir(plansRoot, state), newSlug)
+	if _, err := os.Stat(dstDir); err == nil {
+		fmt.Fprintf(os.Stderr, "destination already exists: %s\n", dstDir)
+		return 2
+	}
+	docRenames := planDocRenames(ref.PlanDocs, oldSlug, newSlug)
+
+	if opts.dryRun {
+		fmt.Println(ui.ActionHeader(tr(lang, "Dry Run", "Simulación"), fmt.Sprintf("%s/%s -> %s/%s", state, oldSlug, state, newSlug)))
+		fmt.Println(pathLine("renamed", dstDir))
+		for _, to := range docRenames {
+			fmt.Println(pathLine("renamed", filepath.Join(dstDir, to)))
+		}
+		fmt.Println(pathLine("created", filepath.Join(ref.Dir, redirectFileName)))
+		return 0
+	}
+
+	rec := newJournal(plansRoot, "rename", args)
+	defer commitJournal(rec, fmt.Sprintf("%s/%s -> %s/%s", state, oldSlug, state, newSlug))
+
+	if err := rec.Rename(ref.Dir, dstDir); err != nil {
+		fmt.Fprintf(os.Stderr, "rename plan directory: %v\n", err)
+		return 3
+	}
+	for from, to := range docRenames {
+		if err := rec.Rename(filepath.Join(dstDir, from), filepath.Join(dstDir, to)); err != nil {
+			fmt.Fprintf(os.Stderr, "rename plan doc: %v\n", err)
+			return 3
+		}
+	}
+	readme := filepath.Join(dstDir, "README.md")
+	if err := rewriteFile(rec, readme, func(text string) string {
+		for from, to := range docRenames {
+			text = strings.ReplaceAll(text, from, to)
+		}
+		if t := strings.TrimSpace(opts.title); t != "" {
+			text = replaceTitleLine(text, t)
+		}
+		return text
+	}); err != nil {
+		return reportWriteError("update README", err, lang)
+	}
+	if err := writeRedirect(rec, ref.Dir, plansRoot, state, newSlug, tr(lang, "renamed", "renombrado"), lang); err != nil {
+		return reportWriteError("write redirect note", err, lang)
+	}
+	move := indexMove{fromState: state, fromRef: oldSlug, toState: state, toRef: newSlug, title: readPlanTitle(readme), slug: newSlug}
+	if err := rewriteIndexForMoves(rec, plansRoot, []indexMove{move}, lang); err != nil {
+		return reportWriteError("update root README", err, lang)
+	}
+	touched, err := rewritePlanReferences(rec, plansRoot, planRefRewriter(sm.Folder(state)+"/"+oldSlug, sm.Folder(state)+"/"+newSlug))
+	if err != nil {
+		return reportWriteError("update references", err, lang)
+	}
+
+	fmt.Println(ui.ActionHeader(tr(lang, "Renamed Plan", "Plan renombrado"), fmt.Sprintf("%s/%s -> %s/%s", state, oldSlug, state, newSlug)))
+	fmt.Println(pathLine("updated", readme))
+	fmt.Println(pathLine("created", filepath.Join(ref.Dir, redirectFileName)))
+	fmt.Println(pathLine("updated", filepath.Join(plansRoot, "README.md")))
+	for _, p := range touched {
+		fmt.Println(pathLine("updated", p))
+	}
+	return 0
+}
+
+func RunSplit(args []string) int {
+	opts, pos, code, ok := parseRestructureArgs("split", args, true)
+	if !ok {
+		return code
+	}
+	if len(pos) != 3 && len(pos) != 4 {
+		fmt.Fprintln(os.Stderr, "split requires <state> <slug> <new-slug> [new-state] --phases <list>")
+		return 2
+	}
+	state := strings.ToLower(strings.TrimSpace(pos[0]))
+	slug := strings.TrimSpace(pos[1])
+	newSlug := strings.TrimSpace(pos[2])
+	newState := state
+	if len(pos) == 4 {
+		newState = strings.ToLower(strings.TrimSpace(pos[3]))
+	}
+	selected, err := parsePhaseList(opts.phases)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --phases %q: %v\n", opts.phases, err)
+		return 2
+	}
+
+	plansRoot, err := resolvePlansRootForAction(opts.root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
+		return 2
+	}
+	sm, code, ok := loadStateMachine(plansRoot)
+	if !ok {
+		return code
+	}
+	if code := validatePlanArgs(sm, state, slug, newState, newSlug); code != 0 {
+		return code
+	}
+	lang := effectiveLanguage(filepath.Dir(plansRoot))
+	lock, code, ok := lockWorkspace(plansRoot, lang)
+	if !ok {
+		return code
+	}
+	defer lock.Release()
+
+	ref, err := resolvePlanRef(plansRoot, state, slug)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve plan: %v\n", err)
+		return 2
+	}
+	newDir := filepath.Join(sm.Dir(plansRoot, newState), newSlug)
+	if _, err := os.Stat(newDir); err == nil {
+		fmt.Fprintf(os.Stderr, "plan already exists: %s\n", newDir)
+		return 2
+	}
+	srcDoc := ref.PlanDocs[0]
+	b, srcSnap, err := fsutil.ReadSnapshot(srcDoc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read plan doc: %v\n", err)
+		return 3
+	}
+	secs := splitDocSections(string(b))
+	present := map[int]bool{}
+	for _, sec := range secs {
+		if sec.phase > 0 {
+			present[sec.phase] = true
+		}
+	}
+	for _, n := range selected {
+		if !present[n] {
+			fmt.Fprintf(os.Stderr, "phase %d not found in %s\n", n, filepath.Base(srcDoc))
+			return 2
+		}
+	}
+	if len(selected) == len(present) {
+		fmt.Fprintln(os.Stderr, "split must leave at least one phase in the source plan (use rename instead)")
+		return 2
+	}
+
+	title := strings.TrimSpace(opts.title)
+	if title == "" {
+		title = slugToTitle(newSlug)
+	}
+	date := time.Now().Format("2006-01-02")
+	isSelected := map[int]bool{}
+	for _, n := range selected {
+		isSelected[n] = true
+	}
+	phaseList := joinInts(selected)
+	srcLink := fmt.Sprintf("../../%s/%s/README.md", sm.Folder(state), slug)
+	newLink := fmt.Sprintf("../../%s/%s/README.md", sm.Folder(newState), newSlug)
+
+	kept := make([]docSection, 0, len(secs))
+	moved := make([]docSection, 0, len(selected))
+	noteAdded := false
+	for _, sec := range secs {
+		if sec.phase > 0 && isSelected[sec.phase] {
+			moved = append(moved, sec)
+			if !noteAdded {
+				note := tr(lang,
+					fmt.Sprintf("> Phases %s moved to [%s/%s](%s) on %s.", phaseList, newState, newSlug, newLink, date),
+					fmt.Sprintf("> Fases %s movidas a [%s/%s](%s) el %s.", phaseList, newState, newSlug, newLink, date))
+				kept = append(kept, docSection{lines: []string{note, ""}})
+				noteAdded = true
+			}
+			continue
+		}
+		kept = append(kept, sec)
+	}
+	keptPhases := renumberPhases(kept, 1)
+	movedPhases := renumberPhases(moved, 1)
+	progress := renumberPhaseRefs(kept, keptPhases, movedPhases, newState+"/"+newSlug)
+
+	var planText strings.Builder
+	planText.WriteString("# Plan: " + title + "\n\n")
+	planText.WriteString(tr(lang,
+		fmt.Sprintf("> Split from [%s/%s](%s) (phases %s) on %s.\n\n", state, slug, srcLink, phaseList, date),
+		fmt.Sprintf("> Separado de [%s/%s](%s) (fases %s) el %s.\n\n", state, slug, srcLink, phaseList, date)))
+	if len(progress) > 0 {
+		planText.WriteString(strings.Join(progress, "\n") + "\n\n")
+	}
+	planText.WriteString(strings.TrimRight(joinDocSections(moved), "\n") + "\n")
+
+	planFileName := fmt.Sprintf("PLAN_%s_%s.md", slugToTopic(newSlug), date)
+	targets := map[int]phaseTarget{}
+	srcRef := sm.Folder(state) + "/" + slug
+	for old, n := range keptPhases {
+		targets[old] = phaseTarget{ref: srcRef, doc: filepath.Base(srcDoc), phase: n}
+	}
+	for old, n := range movedPhases {
+		targets[old] = phaseTarget{ref: sm.Folder(newState) + "/" + newSlug, doc: planFileName, phase: n}
+	}
+	if opts.dryRun {
+		fmt.Println(ui.ActionHeader(tr(lang, "Dry Run", "Simulación"), fmt.Sprintf("%s/%s -> %s/%s", state, slug, newState, newSlug)))
+		fmt.Println(pathLine("updated", srcDoc))
+		fmt.Println(pathLine("created", filepath.Join(newDir, planFileName)))
+		fmt.Println(ui.Bullet(tr(lang, "phases moved: ", "fases movidas: ") + phaseList))
+		return 0
+	}
+
+	rec := newJournal(plansRoot, "split", args)
+	defer commitJournal(rec, fmt.Sprintf("%s/%s phases %s -> %s/%s", state, slug, phaseList, newState, newSlug))
+
+	if err := rec.WriteChecked(srcSnap, []byte(joinDocSections(kept)), 0o664); err != nil {
+		return reportWriteError("write plan doc", err, lang)
+	}
+	if err := rec.MkdirAll(newDir, 0o775); err != nil {
+		fmt.Fprintf(os.Stderr, "create plan dir: %v\n", err)
+		return 3
+	}
+	newPlanPath := filepath.Join(newDir, planFileName)
+	if err := rec.WriteChecked(fsutil.Snapshot{Path: newPlanPath}, []byte(planText.String()), 0o664); err != nil {
+		return reportWriteError("write plan file", err, lang)
+	}
+	description := tr(lang,
+		fmt.Sprintf("Split from [%s/%s](%s) with `pacto split`.", state, slug, srcLink),
+		fmt.Sprintf("Separado de [%s/%s](%s) con `pacto split`.", state, slug, srcLink))
+	newReadme := filepath.Join(newDir, "README.md")
+	if err := rec.WriteChecked(fsutil.Snapshot{Path: newReadme}, []byte(buildPlanReadme(title, sm.Label(newState, lang), date, planFileName, description, lang)), 0o664); err != nil {
+		return reportWriteError("write readme", err, lang)
+	}
+	if err := updateRootIndex(rec, plansRoot, newState, newSlug, title, date, lang); err != nil {
+		return reportWriteError("update root README", err, lang)
+	}
+	touched, err := rewritePlanReferences(rec, plansRoot, phaseLinkRewriter(srcRef, filepath.Base(srcDoc), targets), srcDoc, newPlanPath)
+	if err != nil {
+		return reportWriteError("update references", err, lang)
+	}
+
+	fmt.Println(ui.ActionHeader(tr(lang, "Split Plan", "Plan separado"), fmt.Sprintf("%s/%s -> %s/%s", state, slug, newState, newSlug)))
+	fmt.Println(pathLine("updated", srcDoc))
+	fmt.Println(pathLine("created", newReadme))
+	fmt.Println(pathLine("created", newPlanPath))
+	fmt.Println(pathLine("updated", filepath.Join(plansRoot, "README.md")))
+	for _, p := range touched {
+		fmt.Println(pathLine("updated", p))
+	}
+	return 0
+}
+
+func RunMerge(args []string) int {
+	opts, pos, code, ok := parseRestructureArgs("merge", args, false)
+	if !ok {
+		return code
+	}
+	if len(pos) != 4 {
+		fmt.Fprintln(os.Stderr, "merge requires <state> <slug> <into-state> <into-slug>")
+		return 2
+	}
+	state := strings.ToLower(strings.TrimSpace(pos[0]))
+	slug := strings.TrimSpace(pos[1])
+	intoState := strings.ToLower(strings.TrimSpace(pos[2]))
+	intoSlug := strings.TrimSpace(pos[3])
+	if state == intoState && slug == intoSlug {
+		fmt.Fprintln(os.Stderr, "cannot merge a plan into itself")
+		return 2
+	}
+
+	plansRoot, err := resolvePlansRootForAction(opts.root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
+		return 2
+	}
+	sm, code, ok := loadStateMachine(plansRoot)
+	if !ok {
+		return code
+	}
+	if code := validatePlanArgs(sm, state, slug, intoState, intoSlug); code != 0 {
+		return code
+	}
+	lang := effectiveLanguage(filepath.Dir(plansRoot))
+	lock, code, ok := lockWorkspace(plansRoot, lang)
+	if !ok {
+		return code
+	}
+	defer lock.Release()
+
+	src, err := resolvePlanRef(plansRoot, state, slug)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve plan: %v\n", err)
+		return 2
+	}
+	dst, err := resolvePlanRef(plansRoot, intoState, intoSlug)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve plan: %v\n", err)
+		return 2
+	}
+	srcBytes, err := os.ReadFile(src.PlanDocs[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read plan doc: %v\n", err)
+		return 3
+	}
+	dstBytes, dstSnap, err := fsutil.ReadSnapshot(dst.PlanDocs[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read plan doc: %v\n", err)
+		return 3
+	}
+
+	srcSecs := splitDocSections(string(srcBytes))
+	dstSecs := splitDocSections(string(dstBytes))
+	lastPhase := 0
+	for _, sec := range dstSecs {
+		if sec.phase > lastPhase {
+			lastPhase = sec.phase
+		}
+	}
+	incoming := make([]docSection, 0)
+	carried := map[string][]string{}
+	for _, sec := range srcSecs {
+		if sec.phase > 0 {
+			incoming = append(incoming, sec)
+			continue
+		}
+		if h := sec.heading; h == "## Execution Notes" || h == "## Blockers" || h == "## Evidence" {
+			for _, ln := range sec.lines[1:] {
+				if strings.HasPrefix(strings.TrimSpace(ln), "- ") {
+					carried[h] = append(carried[h], strings.TrimSpace(ln))
+				}
+			}
+		}
+	}
+	incomingPhases := renumberPhases(incoming, lastPhase+1)
+	for h, bullets := range carried {
+		for i, b := range bullets {
+			carried[h][i] = renumberStepRefs(b, incomingPhases, nil, "")
+		}
+	}
+
+	// References to the merged plan are rewritten before the merge note is
+	// added, so the note keeps pointing at where the phases came from.
+	oldRef, intoRef := sm.Folder(state)+"/"+slug, sm.Folder(intoState)+"/"+intoSlug
+	targets := map[int]phaseTarget{}
+	for old, n := range incomingPhases {
+		targets[old] = phaseTarget{ref: intoRef, doc: filepath.Base(dst.PlanDocs[0]), phase: n}
+	}
+	phaseFix := phaseLinkRewriter(oldRef, filepath.Base(src.PlanDocs[0]), targets)
+	refFix := planRefRewriter(oldRef, intoRef)
+	fixRefs := func(text string) string { return refFix(phaseFix(text)) }
+	for _, secs := range [][]docSection{dstSecs, incoming} {
+		for i := range secs {
+			for j, ln := range secs[i].lines {
+				secs[i].lines[j] = fixRefs(ln)
+			}
+		}
+	}
+
+	date := time.Now().Format("2006-01-02")
+	srcLink := fmt.Sprintf("../../%s/%s/README.md", sm.Folder(state), slug)
+	note := tr(lang,
+		fmt.Sprintf("> Merged from [%s/%s](%s) on %s.", state, slug, srcLink, date),
+		fmt.Sprintf("> Fusionado desde [%s/%s](%s) el %s.", state, slug, srcLink, date))
+	insertAt := len(dstSecs)
+	for i, sec := range dstSecs {
+		if sec.phase > 0 {
+			insertAt = i + 1
+		}
+	}
+	block := append([]docSection{{lines: []string{note, ""}}}, incoming...)
+	merged := make([]docSection, 0, len(dstSecs)+len(block))
+	merged = append(merged, dstSecs[:insertAt]...)
+	merged = append(merged, block...)
+	merged = append(merged, dstSecs[insertAt:]...)
+	text := joinDocSections(merged)
+	for _, h := range []string{"## Execution Notes", "## Blockers", "## Evidence"} {
+		for _, bullet := range carried[h] {
+			text = appendSectionBullet(text, h, bullet)
+		}
+	}
+
+	if opts.dryRun {
+		fmt.Println(ui.ActionHeader(tr(lang, "Dry Run", "Simulación"), fmt.Sprintf("%s/%s -> %s/%s", state, slug, intoState, intoSlug)))
+		fmt.Println(pathLine("updated", dst.PlanDocs[0]))
+		fmt.Println(pathLine("removed", src.Dir))
+		fmt.Println(ui.Bullet(fmt.Sprintf(tr(lang, "phases appended: %d", "fases añadidas: %d"), len(incoming))))
+		return 0
+	}
+
+	rec := newJournal(plansRoot, "merge", args)
+	defer commitJournal(rec, fmt.Sprintf("%s/%s -> %s/%s", state, slug, intoState, intoSlug))
+
+	if err := rec.WriteChecked(dstSnap, []byte(text), 0o664); err != nil {
+		return reportWriteError("write plan doc", err, lang)
+	}
+	if err := rec.RemoveAll(src.Dir); err != nil {
+		fmt.Fprintf(os.Stderr, "remove merged plan: %v\n", err)
+		return 3
+	}
+	if err := rec.Mkdir(src.Dir, 0o775); err != nil {
+		fmt.Fprintf(os.Stderr, "create redirect dir: %v\n", err)
+		return 3
+	}
+	if err := writeRedirect(rec, src.Dir, plansRoot, intoState, intoSlug, tr(lang, "merged into", "fusionado en"), lang); err != nil {
+		return reportWriteError("write redirect note", err, lang)
+	}
+	if err := rewriteIndexAfterRemoval(rec, plansRoot, state, slug, lang); err != nil {
+		return reportWriteError("update root README", err, lang)
+	}
+	touched, err := rewritePlanReferences(rec, plansRoot, fixRefs, dst.PlanDocs[0])
+	if err != nil {
+		return reportWriteError("update references", err, lang)
+	}
+
+	fmt.Println(ui.ActionHeader(tr(lang, "Merged Plan", "Plan fusionado"), fmt.Sprintf("%s/%s -> %s/%s", state, slug, intoState, intoSlug)))
+	fmt.Println(pathLine("updated", dst.PlanDocs[0]))
+	fmt.Println(pathLine("created", filepath.Join(src.Dir, redirectFileName)))
+	fmt.Println(pathLine("updated", filepath.Join(plansRoot, "README.md")))
+	for _, p := range touched {
+		fmt.Println(pathLine("updated", p))
+	}
+	return 0
+}
+
+// docSection is a "## " block of a plan doc. The first section holds the
+// preamble and has no heading; phase sections carry their phase number.
+type docSection struct {
+	heading string
+	phase   int
+	lines   []string
+}
+
+func splitDocSections(content string) []docSection {
+	lines := strings.Split(content, "\n")
+	secs := []docSection{{}}
+	for _, ln := range lines {
+		t := strings.TrimSpace(ln)
+		if strings.HasPrefix(t, "## ") {
+			sec := docSection{heading: t, lines: []string{ln}}
+			if m := rePhaseHeading.FindStringSubmatch(t); len(m) == 2 {
+				sec.phase = parsePosInt(m[1])
+			}
+			secs = append(secs, sec)
+			continue
+		}
+		last := &secs[len(secs)-1]
+		last.lines = append(last.lines, ln)
+	}
+	return secs
+}
+
+func joinDocSections(secs []docSection) string {
+	lines := make([]string, 0, 64)
+	for _, sec := range secs {
+		lines = append(lines, sec.lines...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renumberPhases rewrites phase headings and task refs so phases are numbered
+// contiguously starting at first, in document order. It returns the old to
+// new phase numbers.
+func renumberPhases(secs []docSection, first int) map[int]int {
+	mapping := map[int]int{}
+	next := first
+	for i := range secs {
+		if secs[i].phase == 0 {
+			continue
+		}
+		old := secs[i].phase
+		mapping[old] = next
+		secs[i].phase = next
+		lines := make([]string, len(secs[i].lines))
+		copy(lines, secs[i].lines)
+		lines[0] = rePhaseNumber.ReplaceAllString(lines[0], "${1}"+strconv.Itoa(next))
+		for j := 1; j < len(lines); j++ {
+			m := reExecCheckbox.FindStringSubmatch(lines[j])
+			if len(m) != 3 {
+				continue
+			}
+			phase, task, ok := extractStepRef(m[2])
+			if !ok || phase != old {
+				continue
+			}
+			oldRef := fmt.Sprintf("%d.%d", phase, task)
+			idx := strings.Index(lines[j], oldRef)
+			if idx < 0 {
+				continue
+			}
+			lines[j] = lines[j][:idx] + fmt.Sprintf("%d.%d", next, task) + lines[j][idx+len(oldRef):]
+		}
+		secs[i].lines = lines
+		next++
+	}
+	return mapping
+}
+
+// renumberPhaseRefs applies kept to progress-table rows and to step refs in
+// bullets outside phase sections. Rows of moved phases are removed and
+// returned, renumbered and under their table header, for the plan that now
+// holds them; step refs to moved phases are prefixed with movedTo.
+func renumberPhaseRefs(secs []docSection, kept, moved map[int]int, movedTo string) []string {
+	var carried []string
+	for i := range secs {
+		if secs[i].phase != 0 {
+			continue
+		}
+		out := make([]string, 0, len(secs[i].lines))
+		var head []string
+		for _, ln := range secs[i].lines {
+			if m := reProgressRow.FindStringSubmatch(ln); len(m) == 4 {
+				old := parsePosInt(m[2])
+				if n, ok := kept[old]; ok {
+					out = append(out, reProgressRow.ReplaceAllString(ln, "${1}"+strconv.Itoa(n)+"${3}"))
+					continue
+				}
+				if n, ok := moved[old]; ok {
+					if len(carried) == 0 {
+						carried = append(carried, secs[i].lines[0], "")
+						carried = append(carried, head...)
+					}
+					carried = append(carried, reProgressRow.ReplaceAllString(ln, "${1}"+strconv.Itoa(n)+"${3}"))
+					continue
+				}
+			}
+			t := strings.TrimSpace(ln)
+			if strings.HasPrefix(t, "|") {
+				head = append(head, ln)
+			} else {
+				head = nil
+			}
+			if strings.HasPrefix(t, "- ") || strings.HasPrefix(t, "* ") {
+				ln = renumberStepRefs(ln, kept, moved, movedTo)
+			}
+			out = append(out, ln)
+		}
+		secs[i].lines = out
+	}
+	return carried
+}
+
+// renumberStepRefs rewrites "<phase>.<task>" refs in line. Refs to phases in
+// moved are renumbered and prefixed with movedTo; other phases are left as is.
+func renumberStepRefs(line string, kept, moved map[int]int, movedTo string) string {
+	matches := reStepRefInText.FindAllStringSubmatchIndex(line, -1)
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if (m[0] > 0 && line[m[0]-1] == '.') || (m[1] < len(line) && line[m[1]] == '.') {
+			continue
+		}
+		phase, task := parsePosInt(line[m[2]:m[3]]), line[m[4]:m[5]]
+		repl := ""
+		if n, ok := kept[phase]; ok {
+			repl = fmt.Sprintf("%d.%s", n, task)
+		} else if n, ok := moved[phase]; ok {
+			repl = fmt.Sprintf("%s %d.%s", movedTo, n, task)
+		} else {
+			continue
+		}
+		b.WriteString(line[last:m[0]])
+		b.WriteString(repl)
+		last = m[1]
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+func parsePhaseList(raw string) ([]int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("--phases is required")
+	}
+	seen := map[int]bool{}
+	out := make([]int, 0, 4)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		lo, hi := part, part
+		if a, b, ok := strings.Cut(part, "-"); ok {
+			lo, hi = a, b
+		}
+		from, to := parsePosInt(lo), parsePosInt(hi)
+		if from == 0 || to == 0 || to < from {
+			return nil, fmt.Errorf("use phase numbers such as 2,3 or 2-4")
+		}
+		for n := from; n <= to; n++ {
+			if !seen[n] {
+				seen[n] = true
+				out = append(out, n)
+			}
+		}
+	}
+	sort.Ints(out)
+	return out, nil
+}
+
+func joinInts(ns []int) string {
+	parts := make([]string, 0, len(ns))
+	for _, n := range ns {
+		parts = append(parts, strconv.Itoa(n))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// planDocRenames maps PLAN_<OLD_TOPIC>* file names to their new-slug names.
+func planDocRenames(docs []string, oldSlug, newSlug string) map[string]string {
+	out := map[string]string{}
+	oldPrefix := "PLAN_" + slugToTopic(oldSlug)
+	for _, d := range docs {
+		base := filepath.Base(d)
+		if !strings.HasPrefix(base, oldPrefix) {
+			continue
+		}
+		out[base] = "PLAN_" + slugToTopic(newSlug) + strings.TrimPrefix(base, oldPrefix)
+	}
+	return out
+}
+
+func replaceTitleLine(text, title string) string {
+	lines := strings.Split(text, "\n")
+	for i, ln := range lines {
+		if strings.HasPrefix(strings.TrimSpace(ln), "# ") {
+			lines[i] = "# " + title
+			return strings.Join(lines, "\n")
+		}
+	}
+	return "# " + title + "\n\n" + text
+}
+
+func rewriteFile(rec *journal.Recorder, path string, fn func(string) string) error {
+	b, snap, err := fsutil.ReadSnapshot(path)
+	if err != nil {
+		return err
+	}
+	updated := fn(string(b))
+	if updated == string(b) {
+		return nil
+	}
+	return rec.WriteChecked(snap, []byte(updated), 0o664)
+}
+
+// isRedirectDir reports whether dir holds nothing but the MOVED.md note
+// written by writeRedirect.
+func isRedirectDir(dir string) bool {
+	ents, err := os.ReadDir(dir)
+	return err == nil && len(ents) == 1 && !ents[0].IsDir() && ents[0].Name() == redirectFileName
+}
+
+func writeRedirect(rec *journal.Recorder, oldDir, plansRoot, state, slug, verb string, lang i18n.Language) error {
+	if _, err := os.Stat(oldDir); err != nil {
+		if err := rec.Mkdir(oldDir, 0o775); err != nil {
+			return err
+		}
+	}
+	target := filepath.Join(stateMachine(plansRoot).Dir(plansRoot, state), slug, "README.md")
+	rel, err := filepath.Rel(oldDir, target)
+	if err != nil {
+		return err
+	}
+	body := tr(lang, "# Moved\n\n", "# Movido\n\n") +
+		fmt.Sprintf(tr(lang, "This plan was %s [%s/%s](%s) on %s.\n", "Este plan fue %s [%s/%s](%s) el %s.\n"), verb, state, slug, filepath.ToSlash(rel), time.Now().Format("2006-01-02"))
+	return rec.WriteChecked(fsutil.Snapshot{Path: filepath.Join(oldDir, redirectFileName)}, []byte(body), 0o664)
+}
+
+func rewriteIndexAfterRemoval(rec *journal.Recorder, plansRoot, state, slug string, lang i18n.Language) error {
+	return rewriteFile(rec, filepath.Join(plansRoot, "README.md"), func(text string) string {
+		sm := stateMachine(plansRoot)
+		counts, err := countPlans(plansRoot, sm)
+		if err == nil {
+			text = updateCountsTable(text, sm, counts)
+		}
+		text = removePlanLinkFromSection(text, indexState(sm, state), slug)
+		return updateLastUpdate(text, time.Now().Format("2006-01-02"), lang)
+	})
+}
+
+// rewritePlanReferences applies fix to every markdown file under the state
+// and archive folders except skip, returning the files it changed.
+func rewritePlanReferences(rec *journal.Recorder, plansRoot string, fix func(string) string, skip ...string) ([]string, error) {
+	folders := append(stateMachine(plansRoot).Folders(), discovery.ArchiveDir)
+	touched := make([]string, 0)
+	for _, folder := range folders {
+		dir := filepath.Join(plansRoot, folder)
+		err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() || !strings.EqualFold(filepath.Ext(p), ".md") || d.Name() == redirectFileName || containsString(skip, p) {
+				return nil
+			}
+			b, snap, err := fsutil.ReadSnapshot(p)
+			if err != nil {
+				return err
+			}
+			updated := fix(string(b))
+			if updated == string(b) {
+				return nil
+			}
+			if err := rec.WriteChecked(snap, []byte(updated), 0o664); err != nil {
+				return err
+			}
+			touched = append(touched, p)
+			return nil
+		})
+		if err != nil {
+			return touched, err
+		}
+	}
+	return touched, nil
+}
+
+// planRefRewriter replaces links to oldRef ("folder/slug") with newRef.
+func planRefRewriter(oldRef, newRef string) func(string) string {
+	re := regexp.MustCompile(`(^|[^A-Za-z0-9_-])` + regexp.QuoteMeta(oldRef) + `([/)#\s]|$)`)
+	return func(text string) string {
+		return re.ReplaceAllString(text, "${1}"+newRef+"${2}")
+	}
+}
+
+// phaseTarget is where a phase of a split or merged plan doc now lives.
+type phaseTarget struct {
+	ref   string
+	doc   string
+	phase int
+}
+
+// phaseLinkRewriter retargets "#phase-N" anchor links into doc of the plan at
+// ref ("folder/slug") according to targets, keyed by the old phase number.
+func phaseLinkRewriter(ref, doc string, targets map[int]phaseTarget) func(string) string {
+	re := regexp.MustCompile(`(^|[^A-Za-z0-9_-])` + regexp.QuoteMeta(ref+"/"+doc) + `#phase-([1-9][0-9]*)([A-Za-z0-9_-]*)`)
+	return func(text string) string {
+		return re.ReplaceAllStringFunc(text, func(match string) string {
+			m := re.FindStringSubmatch(match)
+			t, ok := targets[parsePosInt(m[2])]
+			if !ok {
+				return match
+			}
+			return fmt.Sprintf("%s%s/%s#phase-%d%s", m[1], t.ref, t.doc, t.phase, m[3])
+		})
+	}
+}
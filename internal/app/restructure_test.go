@@ -0,0 +1,252 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestPlan(t *testing.T, plansRoot, state, slug, readme, plan string) string {
+	t.Helper()
+	dir := filepath.Join(plansRoot, state, slug)
+	if err := os.MkdirAll(dir, 0o775); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(readme), 0o664); err != nil {
+		t.Fatal(err)
+	}
+	planPath := filepath.Join(dir, "PLAN_"+slugToTopic(slug)+"_2026-01-01.md")
+	if err := os.WriteFile(planPath, []byte(plan), 0o664); err != nil {
+		t.Fatal(err)
+	}
+	return planPath
+}
+
+func TestRunRenameRewritesLinksAndLeavesRedirect(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	captureOutput(t, func() {
+		if code := RunNew([]string{"to-implement", "auth-v2", "--root", root}); code != 0 {
+			t.Fatalf("RunNew returned %d", code)
+		}
+	})
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+	otherPlan := writeTestPlan(t, plansRoot, "current", "consumer", "# Consumer\n",
+		"# Plan: Consumer\n\nDepends on [auth](../../to-implement/auth-v2/README.md).\n")
+
+	_, stderr := captureOutput(t, func() {
+		if code := RunRename([]string{"to-implement", "auth-v2", "auth-token-refresh", "--root", root, "--title", "Auth Token Refresh"}); code != 0 {
+			t.Fatalf("RunRename returned %d", code)
+		}
+	})
+	if stderr != "" {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+
+	newDir := filepath.Join(plansRoot, "to-implement", "auth-token-refresh")
+	readme, err := os.ReadFile(filepath.Join(newDir, "README.md"))
+	if err != nil {
+		t.Fatalf("expected renamed plan: %v", err)
+	}
+	if !strings.Contains(string(readme), "# Auth Token Refresh") || !strings.Contains(string(readme), "PLAN_AUTH_TOKEN_REFRESH_") {
+		t.Fatalf("expected README title and doc link updated, got %q", string(readme))
+	}
+	docs, _ := filepath.Glob(filepath.Join(newDir, "PLAN_AUTH_TOKEN_REFRESH_*.md"))
+	if len(docs) != 1 {
+		t.Fatalf("expected renamed plan doc, got %v", docs)
+	}
+	redirect, err := os.ReadFile(filepath.Join(plansRoot, "to-implement", "auth-v2", "MOVED.md"))
+	if err != nil || !strings.Contains(string(redirect), "../auth-token-refresh/README.md") {
+		t.Fatalf("expected redirect note, got %q (%v)", string(redirect), err)
+	}
+	idx, _ := os.ReadFile(filepath.Join(plansRoot, "README.md"))
+	if strings.Contains(string(idx), "./to-implement/auth-v2/") || !strings.Contains(string(idx), "[Auth Token Refresh](./to-implement/auth-token-refresh/)") {
+		t.Fatalf("expected index link rewritten, got %q", string(idx))
+	}
+	if !strings.Contains(string(idx), "| 🟡 **To Implement** | 1 |") {
+		t.Fatalf("redirect folder should not be counted, got %q", string(idx))
+	}
+	other, _ := os.ReadFile(otherPlan)
+	if !strings.Contains(string(other), "../../to-implement/auth-token-refresh/README.md") {
+		t.Fatalf("expected cross-plan reference rewritten, got %q", string(other))
+	}
+
+	// The old slug is free again: new replaces the redirect-only folder.
+	oldDir := filepath.Join(plansRoot, "to-implement", "auth-v2")
+	captureOutput(t, func() {
+		if code := RunNew([]string{"to-implement", "auth-v2", "--root", root}); code != 0 {
+			t.Fatalf("RunNew over a redirect returned %d", code)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(oldDir, "README.md")); err != nil {
+		t.Fatalf("expected a new plan in the old folder: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(oldDir, "MOVED.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected the redirect to be replaced, got %v", err)
+	}
+	captureOutput(t, func() {
+		if code := RunUndo([]string{"--root", root}); code != 0 {
+			t.Fatalf("RunUndo returned %d", code)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(oldDir, "MOVED.md")); err != nil {
+		t.Fatalf("expected undo to restore the redirect: %v", err)
+	}
+}
+
+func TestRunSplitAndMergeRenumberPhases(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+	srcPlan := writeTestPlan(t, plansRoot, "current", "big-plan", "# Big Plan\n",
+		"# Plan: Big Plan\n\n## Phase 1: Setup\n\n- [x] 1.1 setup\n\n## Phase 2: API\n\n- [ ] 2.1 api\n- [ ] 2.2 tests\n\n## Phase 3: UI\n\n- [ ] 3.1 ui\n\n## Execution Notes\n\n- 2026-01-01 10:00 started\n")
+
+	_, stderr := captureOutput(t, func() {
+		if code := RunSplit([]string{"current", "big-plan", "big-plan-api", "--phases", "2", "--root", root}); code != 0 {
+			t.Fatalf("RunSplit returned %d", code)
+		}
+	})
+	if stderr != "" {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+	src, _ := os.ReadFile(srcPlan)
+	srcText := string(src)
+	if strings.Contains(srcText, "2.1 api") || !strings.Contains(srcText, "## Phase 2: UI") || !strings.Contains(srcText, "- [ ] 2.1 ui") {
+		t.Fatalf("expected source renumbered without moved phase, got %q", srcText)
+	}
+	if !strings.Contains(srcText, "Phases 2 moved to [current/big-plan-api]") {
+		t.Fatalf("expected redirect note in source, got %q", srcText)
+	}
+	ref, err := resolvePlanRef(plansRoot, "current", "big-plan-api")
+	if err != nil {
+		t.Fatalf("expected new plan: %v", err)
+	}
+	moved, _ := os.ReadFile(ref.PlanDocs[0])
+	if !strings.Contains(string(moved), "## Phase 1: API") || !strings.Contains(string(moved), "- [ ] 1.2 tests") {
+		t.Fatalf("expected moved phase renumbered, got %q", string(moved))
+	}
+
+	_, stderr = captureOutput(t, func() {
+		if code := RunMerge([]string{"current", "big-plan-api", "current", "big-plan", "--root", root}); code != 0 {
+			t.Fatalf("RunMerge returned %d", code)
+		}
+	})
+	if stderr != "" {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+	src, _ = os.ReadFile(srcPlan)
+	srcText = string(src)
+	if !strings.Contains(srcText, "## Phase 3: API") || !strings.Contains(srcText, "- [ ] 3.2 tests") {
+		t.Fatalf("expected merged phases appended as phase 3, got %q", srcText)
+	}
+	if !strings.Contains(srcText, "Merged from [current/big-plan-api]") {
+		t.Fatalf("expected merge note, got %q", srcText)
+	}
+	if _, err := os.Stat(filepath.Join(plansRoot, "current", "big-plan-api", "README.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected merged plan removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(plansRoot, "current", "big-plan-api", "MOVED.md")); err != nil {
+		t.Fatalf("expected redirect note: %v", err)
+	}
+	idx, _ := os.ReadFile(filepath.Join(plansRoot, "README.md"))
+	if strings.Contains(string(idx), "./current/big-plan-api/") {
+		t.Fatalf("expected merged plan link removed, got %q", string(idx))
+	}
+}
+
+func TestRunSplitRewritesProgressNotesAndPhaseLinks(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+	srcPlan := writeTestPlan(t, plansRoot, "current", "big-plan", "# Big Plan\n",
+		"# Plan: Big Plan\n\n## Progress\n\n| Phase | Description | State | Progress |\n|---|---|---|---|\n| Phase 1 | Setup | Done | 100% |\n| Phase 2 | API | Pending | 0% |\n| Phase 3 | UI | Pending | 0% |\n\n"+
+			"## Phase 1: Setup\n\n- [x] 1.1 setup\n\n## Phase 2: API\n\n- [ ] 2.1 api\n\n## Phase 3: UI\n\n- [ ] 3.1 ui\n\n## Execution Notes\n\n- 2026-01-01 10:00 started 2.1 after 1.1, then 3.1\n")
+	otherPlan := writeTestPlan(t, plansRoot, "to-implement", "consumer", "# Consumer\n",
+		"# Plan: Consumer\n\nNeeds [api](../../current/big-plan/"+filepath.Base(srcPlan)+"#phase-2-api) and [ui](../../current/big-plan/"+filepath.Base(srcPlan)+"#phase-3-ui).\n")
+
+	_, stderr := captureOutput(t, func() {
+		if code := RunSplit([]string{"current", "big-plan", "big-plan-api", "--phases", "2", "--root", root}); code != 0 {
+			t.Fatalf("RunSplit returned %d", code)
+		}
+	})
+	if stderr != "" {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+	src, _ := os.ReadFile(srcPlan)
+	srcText := string(src)
+	if strings.Contains(srcText, "| Phase 2 | API") || !strings.Contains(srcText, "| Phase 2 | UI") || !strings.Contains(srcText, "| Phase 1 | Setup") {
+		t.Fatalf("expected progress table renumbered, got %q", srcText)
+	}
+	if !strings.Contains(srcText, "started current/big-plan-api 1.1 after 1.1, then 2.1") {
+		t.Fatalf("expected step refs in notes renumbered, got %q", srcText)
+	}
+	ref, err := resolvePlanRef(plansRoot, "current", "big-plan-api")
+	if err != nil {
+		t.Fatalf("expected new plan: %v", err)
+	}
+	moved, _ := os.ReadFile(ref.PlanDocs[0])
+	if !strings.Contains(string(moved), "| Phase | Description | State | Progress |") || !strings.Contains(string(moved), "| Phase 1 | API | Pending | 0% |") {
+		t.Fatalf("expected moved progress row carried over, got %q", string(moved))
+	}
+	other, _ := os.ReadFile(otherPlan)
+	want := []string{
+		"[api](../../current/big-plan-api/" + filepath.Base(ref.PlanDocs[0]) + "#phase-1-api)",
+		"[ui](../../current/big-plan/" + filepath.Base(srcPlan) + "#phase-2-ui)",
+	}
+	for _, w := range want {
+		if !strings.Contains(string(other), w) {
+			t.Fatalf("expected %q in other plan, got %q", w, string(other))
+		}
+	}
+}
+
+func TestRunMergeKeepsNoteSourceAndUndoes(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+	writeTestPlan(t, plansRoot, "to-implement", "auth-v2", "# Auth v2\n", "# Plan: Auth v2\n\n## Phase 1: Tokens\n\n- [ ] 1.1 tokens\n")
+	dstPlan := writeTestPlan(t, plansRoot, "current", "big", "# Big\n",
+		"# Plan: Big\n\nSee [auth](../../to-implement/auth-v2/README.md).\n\n## Phase 1: Setup\n\n- [x] 1.1 setup\n")
+	before, _ := os.ReadFile(dstPlan)
+
+	_, stderr := captureOutput(t, func() {
+		if code := RunMerge([]string{"to-implement", "auth-v2", "current", "big", "--root", root}); code != 0 {
+			t.Fatalf("RunMerge returned %d", code)
+		}
+	})
+	if stderr != "" {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+	b, _ := os.ReadFile(dstPlan)
+	text := string(b)
+	if !strings.Contains(text, "Merged from [to-implement/auth-v2](../../to-implement/auth-v2/README.md)") {
+		t.Fatalf("expected merge note to keep its source link, got %q", text)
+	}
+	if !strings.Contains(text, "See [auth](../../current/big/README.md)") {
+		t.Fatalf("expected existing reference rewritten, got %q", text)
+	}
+
+	_, stderr = captureOutput(t, func() {
+		if code := RunUndo([]string{"--root", root}); code != 0 {
+			t.Fatalf("RunUndo returned %d", code)
+		}
+	})
+	if stderr != "" {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+	if b, _ := os.ReadFile(dstPlan); string(b) != string(before) {
+		t.Fatalf("expected destination restored, got %q", string(b))
+	}
+	if _, err := os.Stat(filepath.Join(plansRoot, "to-implement", "auth-v2", "README.md")); err != nil {
+		t.Fatalf("expected merged plan restored: %v", err)
+	}
+}
@@ -3,6 +3,9 @@ package app
 import (
 	"os"
 	"path/filepath"
+
+	"pacto/internal/states"
+	"pacto/internal/yamlutil"
 )
 
 func resolvePlanRoot(path string) (string, bool) {
@@ -31,12 +34,36 @@ func resolvePlanRootFrom(path string) (string, string, bool) {
 	return "", "", false
 }
 
+// hasStateDirs recognises a plans root by the built-in state folders, or by
+// a workspace config declaring states plus at least one of their folders, so
+// adding a state to config.yaml does not hide the root until it is created.
 func hasStateDirs(path string) bool {
-	for _, st := range []string{"current", "to-implement", "done", "outdated"} {
-		info, err := os.Stat(filepath.Join(path, st))
-		if err != nil || !info.IsDir() {
-			return false
+	all := true
+	for _, folder := range states.Default().Folders() {
+		if !isDir(filepath.Join(path, folder)) {
+			all = false
+			break
 		}
 	}
-	return true
+	if all {
+		return true
+	}
+	cfg, err := yamlutil.ReadFileMap(states.ConfigPath(path))
+	if err != nil {
+		return false
+	}
+	if _, declared := cfg["states"]; !declared {
+		return false
+	}
+	for _, folder := range stateMachine(path).Folders() {
+		if isDir(filepath.Join(path, folder)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
 }
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"pacto/internal/states"
+)
+
+// loadStateMachine reads the states declared for plansRoot. Config errors are
+// usage errors: nothing can be resolved until the config is fixed.
+func loadStateMachine(plansRoot string) (states.Machine, int, bool) {
+	sm, err := states.LoadForPlansRoot(plansRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid state config in %s: %v\n", states.ConfigPath(plansRoot), err)
+		return sm, 2, false
+	}
+	return sm, 0, true
+}
+
+// stateMachine is loadStateMachine for helpers running after a command has
+// already validated the config.
+func stateMachine(plansRoot string) states.Machine {
+	sm, _ := states.LoadForPlansRoot(plansRoot)
+	return sm
+}
+
+func requireStates(sm states.Machine, ids ...string) int {
+	for _, id := range ids {
+		if !sm.Has(id) {
+			fmt.Fprintf(os.Stderr, "invalid state %q (allowed: %s)\n", id, sm.AllowedList())
+			return 2
+		}
+	}
+	return 0
+}
+
+func requireTransition(sm states.Machine, from, to string) int {
+	if sm.CanTransition(from, to) {
+		return 0
+	}
+	st, _ := sm.Get(to)
+	fmt.Fprintf(os.Stderr, "transition %q -> %q is not allowed (%s only from: %s)\n", from, to, to, strings.Join(st.From, "|"))
+	return 2
+}
+
+func indexState(sm states.Machine, id string) states.State {
+	if st, ok := sm.Get(id); ok {
+		return st
+	}
+	return states.State{ID: id, Folder: id, Title: slugToTitle(id)}
+}
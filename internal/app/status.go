@@ -16,7 +16,9 @@ import (
 	"pacto/internal/i18n"
 	"pacto/internal/model"
 	"pacto/internal/parser"
+	"pacto/internal/plugins"
 	"pacto/internal/report"
+	"pacto/internal/states"
 	statusui "pacto/internal/tui/status"
 	"pacto/internal/verify"
 )
@@ -35,6 +37,7 @@ type statusFlagValues struct {
 	maxNext        int
 	maxBlockers    int
 	verbose        bool
+	recursive      bool
 }
 
 func RunStatus(args []string) int {
@@ -55,10 +58,22 @@ func RunStatus(args []string) int {
 		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
 	}
 
-	rep, code, ok := buildStatusReport(cfg, append(cfgWarnings, runtimeWarnings...))
+	var rep model.StatusReport
+	if values.recursive {
+		var wsWarnings []string
+		rep, wsWarnings, code, ok = buildRecursiveStatusReport(cfg, append(cfgWarnings, runtimeWarnings...))
+		for _, w := range wsWarnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+		}
+	} else {
+		rep, code, ok = buildStatusReport(cfg, append(cfgWarnings, runtimeWarnings...))
+	}
 	if !ok {
 		return code
 	}
+	if code, ok := runStatusReportHooks(&rep, args, values.verbose); !ok {
+		return code
+	}
 
 	if isTerminal(os.Stdout) {
 		lang := effectiveLanguage(cfg.RepoRoot)
@@ -108,11 +123,12 @@ func parseStatusFlags(args []string) (statusFlagValues, map[string]bool, int, bo
 	fs.StringVar(&values.format, "format", "table", "Output format: table|json")
 	fs.StringVar(&values.configPath, "config", "", "Optional path to .pacto-engine.yaml")
 	fs.StringVar(&values.failOn, "fail-on", "none", "Fail policy: none|unverified|partial|blocked")
-	fs.StringVar(&values.state, "state", "all", "State filter: current|to-implement|done|outdated|all")
+	fs.StringVar(&values.state, "state", "all", "State filter: configured state id or all")
 	fs.BoolVar(&values.includeArchive, "include-archive", false, "Include archive plans")
 	fs.IntVar(&values.maxNext, "max-next-actions", 3, "Max next actions per plan")
 	fs.IntVar(&values.maxBlockers, "max-blockers", 3, "Max blockers per plan")
 	fs.BoolVar(&values.verbose, "verbose", false, "Print config and debug warnings")
+	fs.BoolVar(&values.recursive, "recursive", false, "Merge the reports of nested .pacto workspaces")
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -165,7 +181,11 @@ func buildStatusConfig(values statusFlagValues, provided map[string]bool) (confi
 	cfg.PlansRoot = plansRoot
 	cfg.RepoRoot = repoRoot
 
-	if err := validateConfig(cfg); err != nil {
+	sm, code, ok := loadStateMachine(cfg.PlansRoot)
+	if !ok {
+		return config.Config{}, nil, nil, code, false
+	}
+	if err := validateConfig(cfg, sm); err != nil {
 		fmt.Fprintf(os.Stderr, "invalid config: %v\n", err)
 		return config.Config{}, nil, nil, 2, false
 	}
@@ -224,7 +244,8 @@ func resolveStatusRoots(cfg config.Config, provided map[string]bool, cwdAbs stri
 }
 
 func buildStatusReport(cfg config.Config, cfgWarnings []string) (model.StatusReport, int, bool) {
-	plans, err := discovery.FindPlans(cfg.PlansRoot, discovery.Options{StateFilter: cfg.State, IncludeArchive: cfg.IncludeArchive})
+	sm := stateMachine(cfg.PlansRoot)
+	plans, err := discovery.FindPlans(cfg.PlansRoot, discovery.Options{StateFilter: cfg.State, IncludeArchive: cfg.IncludeArchive, States: sm})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "discover plans: %v\n", err)
 		return model.StatusReport{}, 3, false
@@ -233,9 +254,13 @@ func buildStatusReport(cfg config.Config, cfgWarnings []string) (model.StatusRep
 	parsed := make([]parser.ParsedPlan, 0, len(plans))
 	claimsByPlan := map[string][]model.ClaimResult{}
 	warningsByPlan := map[string][]string{}
-	verifier := verify.New(cfg.RepoRoot, cfg.PlansRoot)
+	verifier := verify.NewWithStates(cfg.RepoRoot, cfg.PlansRoot, sm)
+	verifier.Scopes = verify.ScopesForPlansRoot(cfg.PlansRoot)
 	claimOpts := claims.Options{Paths: cfg.ClaimsPaths, Symbols: cfg.ClaimsSymbols, Endpoints: cfg.ClaimsEndpoints, TestRefs: cfg.ClaimsTestRefs}
 
+	checksByPlan := map[string][]model.ClaimResult{}
+	checkRoot, checkPlugins := statusCheckPlugins(cfg.PlansRoot)
+
 	for _, plan := range plans {
 		pp, pErr := parser.ParsePlan(plan, cfg.Mode)
 		if pErr != nil {
@@ -252,6 +277,11 @@ func buildStatusReport(cfg config.Config, cfgWarnings []string) (model.StatusRep
 		if len(cfgWarnings) > 0 {
 			warningsByPlan[key] = append(warningsByPlan[key], cfgWarnings...)
 		}
+		if len(checkPlugins) > 0 {
+			checked, warns := plugins.RunStatusChecks(checkPlugins, checkRoot, cfg.RepoRoot, pp)
+			checksByPlan[key] = checked
+			warningsByPlan[key] = append(warningsByPlan[key], warns...)
+		}
 	}
 
 	rep := analyze.Build(analyze.Input{
@@ -261,11 +291,33 @@ func buildStatusReport(cfg config.Config, cfgWarnings []string) (model.StatusRep
 		Mode:      cfg.Mode,
 		Plans:     parsed,
 		Claims:    claimsByPlan,
+		Checks:    checksByPlan,
 		Warnings:  warningsByPlan,
+		States:    sm,
 	}, analyze.Options{MaxNextActions: cfg.MaxNextActions, MaxBlockers: cfg.MaxBlockers})
 	return rep, 0, true
 }
 
+// statusCheckPlugins returns the active plugins of the workspace owning
+// plansRoot that declare status checks.
+func statusCheckPlugins(plansRoot string) (string, []plugins.Plugin) {
+	projectRoot, ok := findProjectRootForPlugins(plansRoot)
+	if !ok {
+		return "", nil
+	}
+	active, errs := plugins.LoadActive(projectRoot)
+	if len(errs) > 0 {
+		return "", nil
+	}
+	out := make([]plugins.Plugin, 0, len(active))
+	for _, p := range active {
+		if len(p.Manifest.Spec.StatusChecks) > 0 {
+			out = append(out, p)
+		}
+	}
+	return projectRoot, out
+}
+
 func hasLangArg(args []string) bool {
 	for _, a := range args {
 		if a == "--lang" || a == "-lang" || strings.HasPrefix(a, "--lang=") {
@@ -325,7 +377,7 @@ func normalizeConfig(cfg config.Config) config.Config {
 	return cfg
 }
 
-func validateConfig(cfg config.Config) error {
+func validateConfig(cfg config.Config, sm states.Machine) error {
 	if cfg.Mode != "compat" && cfg.Mode != "strict" {
 		return fmt.Errorf("mode must be compat|strict")
 	}
@@ -337,16 +389,8 @@ func validateConfig(cfg config.Config) error {
 	default:
 		return fmt.Errorf("fail-on must be none|unverified|partial|blocked")
 	}
-	validStates := []string{"all", "current", "to-implement", "done", "outdated"}
-	ok := false
-	for _, s := range validStates {
-		if strings.EqualFold(cfg.State, s) {
-			ok = true
-			break
-		}
-	}
-	if !ok {
-		return fmt.Errorf("state must be all|current|to-implement|done|outdated")
+	if cfg.State != "all" && !sm.Has(cfg.State) {
+		return fmt.Errorf("state must be all|%s", sm.AllowedList())
 	}
 	if cfg.MaxNextActions < 1 || cfg.MaxBlockers < 1 {
 		return fmt.Errorf("max limits must be >=1")
@@ -127,3 +127,71 @@ func TestRunStatusAutoDetectsRootsFromNestedDir(t *testing.T) {
 		t.Fatalf("expected verified claim in output, got %q", stdout)
 	}
 }
+
+func TestRunStatusRecursiveMergesWorkspaces(t *testing.T) {
+	root := t.TempDir()
+	api := filepath.Join(root, "services", "api")
+	for _, dir := range []string{root, api} {
+		if code := RunInit([]string{"--root", dir, "--no-interactive", "--no-install"}); code != 0 {
+			t.Fatalf("RunInit %s returned %d", dir, code)
+		}
+	}
+	writeTestPlan(t, filepath.Join(root, ".pacto", "plans"), "current", "platform", "# platform\n", "Status: In Progress\n")
+	writeTestPlan(t, filepath.Join(api, ".pacto", "plans"), "current", "auth", "# auth\n", "Status: In Progress\n- `internal/auth.go`\n")
+	if err := os.MkdirAll(filepath.Join(api, "internal"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(api, "internal", "auth.go"), []byte("package internal\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _ := captureOutput(t, func() {
+		if code := RunStatus([]string{"--root", root, "--recursive", "--format", "json"}); code != 0 {
+			t.Fatalf("RunStatus returned %d, want 0", code)
+		}
+	})
+	for _, want := range []string{`"workspaces": [`, `"workspace": "services/api"`, `"workspace": "."`, `"total_plans": 2`, `"result": "verified"`} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("expected %s in output, got %q", want, stdout)
+		}
+	}
+
+	stdout, _ = captureOutput(t, func() {
+		if code := RunStatus([]string{"--root", root, "--format", "json"}); code != 0 {
+			t.Fatalf("RunStatus returned %d, want 0", code)
+		}
+	})
+	if strings.Contains(stdout, `"auth"`) {
+		t.Fatalf("expected nested workspace plans only with --recursive, got %q", stdout)
+	}
+}
+
+func TestRunStatusRecursiveUsesWorkspaceRepoRoot(t *testing.T) {
+	root := t.TempDir()
+	api := filepath.Join(root, "services", "api")
+	for _, dir := range []string{root, api} {
+		if code := RunInit([]string{"--root", dir, "--no-interactive", "--no-install"}); code != 0 {
+			t.Fatalf("RunInit %s returned %d", dir, code)
+		}
+	}
+	writeTestPlan(t, filepath.Join(api, ".pacto", "plans"), "current", "auth", "# auth\n", "Status: In Progress\n- `internal/auth.go`\n")
+	src := filepath.Join(root, "src", "api")
+	if err := os.MkdirAll(filepath.Join(src, "internal"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "internal", "auth.go"), []byte("package internal\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(api, ".pacto-engine.yaml"), []byte("repo_root: ../../src/api\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _ := captureOutput(t, func() {
+		if code := RunStatus([]string{"--root", root, "--recursive", "--format", "json"}); code != 0 {
+			t.Fatalf("RunStatus returned %d, want 0", code)
+		}
+	})
+	if !strings.Contains(stdout, `"workspace": "services/api"`) || !strings.Contains(stdout, `"result": "verified"`) {
+		t.Fatalf("expected the claim verified against the workspace repo_root, got %q", stdout)
+	}
+}
@@ -0,0 +1,199 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pacto/internal/journal"
+	"pacto/internal/ui"
+)
+
+func RunUndo(args []string) int {
+	fs := flag.NewFlagSet("undo", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	root := fs.String("root", "", "Project root path (auto-discovers when omitted)")
+	steps := fs.Int("steps", 1, "Number of journal entries to undo (newest first)")
+	force := fs.Bool("force", false, "Undo even if files changed after the recorded command")
+	dryRun := fs.Bool("dry-run", false, "Show entries that would be undone without writing files")
+	normalizedArgs, normErr := normalizeArgs(args, map[string]bool{"--root": true, "-root": true, "--steps": true, "-steps": true})
+	if normErr != nil {
+		fmt.Fprintf(os.Stderr, "parse args: %v\n", normErr)
+		return 2
+	}
+	if err := fs.Parse(normalizedArgs); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "parse flags: %v\n", err)
+		return 2
+	}
+	if len(fs.Args()) > 0 {
+		fmt.Fprintln(os.Stderr, "undo does not accept positional args")
+		return 2
+	}
+	if *steps < 1 {
+		fmt.Fprintln(os.Stderr, "--steps must be >= 1")
+		return 2
+	}
+
+	plansRoot, err := resolvePlansRootForAction(*root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
+		return 2
+	}
+	lang := effectiveLanguage(filepath.Dir(plansRoot))
+	lock, code, ok := lockWorkspace(plansRoot, lang)
+	if !ok {
+		return code
+	}
+	defer lock.Release()
+
+	stateDir := workspaceStateDir(plansRoot)
+	entries, err := journal.List(stateDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read journal: %v\n", err)
+		return 3
+	}
+	pending := make([]journal.Entry, 0, *steps)
+	for _, e := range entries {
+		if e.Undone() {
+			continue
+		}
+		pending = append(pending, e)
+		if len(pending) == *steps {
+			break
+		}
+	}
+	if len(pending) == 0 {
+		fmt.Println(ui.Dim(tr(lang, "Nothing to undo.", "No hay nada para deshacer.")))
+		return 0
+	}
+	if len(pending) < *steps {
+		fmt.Fprintf(os.Stderr, tr(lang, "warning: only %d journal entries available\n", "aviso: solo hay %d entradas en el journal\n"), len(pending))
+	}
+
+	if *dryRun {
+		fmt.Println(ui.ActionHeader(tr(lang, "Dry Run", "Simulación"), tr(lang, "undo", "deshacer")))
+		for _, e := range pending {
+			fmt.Println(ui.Bullet(fmt.Sprintf("%s %s: %s", e.ID, e.Command, e.Summary)))
+			for _, p := range journal.Check(stateDir, e) {
+				fmt.Println("  " + ui.Warn(p))
+			}
+		}
+		return 0
+	}
+
+	for _, e := range pending {
+		if err := journal.Undo(stateDir, e, *force); err != nil {
+			fmt.Fprintf(os.Stderr, "undo %s (%s): %v\n", e.ID, e.Command, err)
+			if errors.Is(err, journal.ErrDrift) {
+				fmt.Fprintln(os.Stderr, tr(lang, "next action: review the changes, then re-run with --force to discard them", "siguiente acción: revisa los cambios y vuelve a ejecutar con --force para descartarlos"))
+			}
+			return 3
+		}
+		fmt.Println(ui.ActionHeader(tr(lang, "Undone", "Deshecho"), fmt.Sprintf("%s %s", e.Command, e.Summary)))
+		fmt.Println(ui.Dim("  " + e.ID))
+	}
+	return 0
+}
+
+func RunLog(args []string) int {
+	fs := flag.NewFlagSet("log", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	root := fs.String("root", "", "Project root path (auto-discovers when omitted)")
+	limit := fs.Int("limit", 20, "Maximum number of entries to show (0 = all)")
+	format := fs.String("format", "table", "Output format: table|json")
+	normalizedArgs, normErr := normalizeArgs(args, map[string]bool{"--root": true, "-root": true, "--limit": true, "-limit": true, "--format": true, "-format": true})
+	if normErr != nil {
+		fmt.Fprintf(os.Stderr, "parse args: %v\n", normErr)
+		return 2
+	}
+	if err := fs.Parse(normalizedArgs); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "parse flags: %v\n", err)
+		return 2
+	}
+	if len(fs.Args()) > 0 {
+		fmt.Fprintln(os.Stderr, "log does not accept positional args")
+		return 2
+	}
+	plansRoot, err := resolvePlansRootForAction(*root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve root: %v\n", err)
+		return 2
+	}
+	lang := effectiveLanguage(filepath.Dir(plansRoot))
+	entries, err := journal.List(workspaceStateDir(plansRoot))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read journal: %v\n", err)
+		return 3
+	}
+	if *limit > 0 && len(entries) > *limit {
+		entries = entries[:*limit]
+	}
+
+	switch strings.ToLower(strings.TrimSpace(*format)) {
+	case "json":
+		type logEntry struct {
+			ID       string   `json:"id"`
+			Time     string   `json:"time"`
+			Command  string   `json:"command"`
+			Args     []string `json:"args,omitempty"`
+			Summary  string   `json:"summary,omitempty"`
+			Files    []string `json:"files"`
+			Undone   bool     `json:"undone"`
+			UndoneAt string   `json:"undone_at,omitempty"`
+		}
+		out := make([]logEntry, 0, len(entries))
+		for _, e := range entries {
+			le := logEntry{ID: e.ID, Time: e.Time.Format("2006-01-02T15:04:05Z07:00"), Command: e.Command, Args: e.Args, Summary: e.Summary, Files: entryFiles(e), Undone: e.Undone()}
+			if e.UndoneAt != nil {
+				le.UndoneAt = e.UndoneAt.Format("2006-01-02T15:04:05Z07:00")
+			}
+			out = append(out, le)
+		}
+		enc, _ := json.MarshalIndent(map[string]any{"entries": out}, "", "  ")
+		fmt.Println(string(enc))
+		return 0
+	case "table", "":
+		fmt.Println(ui.Title(tr(lang, "Journal", "Journal")))
+		if len(entries) == 0 {
+			fmt.Println(ui.Dim(tr(lang, "No journal entries.", "No hay entradas en el journal.")))
+			return 0
+		}
+		for _, e := range entries {
+			line := fmt.Sprintf("%s  %-7s %s", e.Time.Local().Format("2006-01-02 15:04:05"), e.Command, e.Summary)
+			if e.Undone() {
+				line += " " + ui.Dim(tr(lang, "(undone)", "(deshecho)"))
+			}
+			fmt.Println(line)
+			fmt.Println(ui.Dim(fmt.Sprintf("  %s  %d %s", e.ID, len(entryFiles(e)), tr(lang, "file(s)", "archivo(s)"))))
+		}
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unsupported format %q (allowed: table|json)\n", *format)
+		return 2
+	}
+}
+
+func entryFiles(e journal.Entry) []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(e.Ops))
+	for _, op := range e.Ops {
+		for _, p := range []string{op.Path, op.To} {
+			if p == "" || strings.HasSuffix(p, "/") || seen[p] {
+				continue
+			}
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,189 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"pacto/internal/journal"
+)
+
+func TestRunUndoRevertsMoveAndNew(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+	indexPath := filepath.Join(plansRoot, "README.md")
+	before, err := os.ReadFile(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	captureOutput(t, func() {
+		if code := RunNew([]string{"to-implement", "undo-sample", "--root", root}); code != 0 {
+			t.Fatalf("RunNew returned %d", code)
+		}
+		if code := RunMove([]string{"to-implement", "undo-sample", "done", "--root", root}); code != 0 {
+			t.Fatalf("RunMove returned %d", code)
+		}
+	})
+
+	stdout, stderr := captureOutput(t, func() {
+		if code := RunLog([]string{"--root", root}); code != 0 {
+			t.Fatalf("RunLog returned %d", code)
+		}
+	})
+	if stderr != "" || !strings.Contains(stdout, "to-implement/undo-sample -> done/undo-sample") || !strings.Contains(stdout, "created to-implement/undo-sample") {
+		t.Fatalf("expected both entries in log, got stdout=%q stderr=%q", stdout, stderr)
+	}
+
+	_, stderr = captureOutput(t, func() {
+		if code := RunUndo([]string{"--root", root}); code != 0 {
+			t.Fatalf("RunUndo returned %d", code)
+		}
+	})
+	if stderr != "" {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+	readme := filepath.Join(plansRoot, "to-implement", "undo-sample", "README.md")
+	b, err := os.ReadFile(readme)
+	if err != nil {
+		t.Fatalf("expected plan back in to-implement: %v", err)
+	}
+	if !strings.Contains(string(b), "Pending (To Implement)") {
+		t.Fatalf("expected README status restored, got %q", string(b))
+	}
+	if _, err := os.Stat(filepath.Join(plansRoot, "done", "undo-sample")); !os.IsNotExist(err) {
+		t.Fatalf("expected done/undo-sample removed, got %v", err)
+	}
+	idx, _ := os.ReadFile(indexPath)
+	if !strings.Contains(string(idx), "./to-implement/undo-sample/") || strings.Contains(string(idx), "./done/undo-sample/") {
+		t.Fatalf("expected index link restored, got %q", string(idx))
+	}
+
+	captureOutput(t, func() {
+		if code := RunUndo([]string{"--root", root}); code != 0 {
+			t.Fatalf("second RunUndo returned %d", code)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(plansRoot, "to-implement", "undo-sample")); !os.IsNotExist(err) {
+		t.Fatalf("expected plan dir removed by undoing new, got %v", err)
+	}
+	after, _ := os.ReadFile(indexPath)
+	if string(after) != string(before) {
+		t.Fatalf("expected root README restored to pre-new content\nbefore=%q\nafter=%q", string(before), string(after))
+	}
+}
+
+func TestRunUndoRefusesAfterExternalEdit(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	captureOutput(t, func() {
+		if code := RunNew([]string{"to-implement", "edited", "--root", root}); code != 0 {
+			t.Fatalf("RunNew returned %d", code)
+		}
+	})
+	readme := filepath.Join(root, ".pacto", "plans", "to-implement", "edited", "README.md")
+	if err := os.WriteFile(readme, []byte("# Edited by hand\n"), 0o664); err != nil {
+		t.Fatal(err)
+	}
+	_, stderr := captureOutput(t, func() {
+		if code := RunUndo([]string{"--root", root}); code != 3 {
+			t.Fatalf("RunUndo returned %d, want 3", code)
+		}
+	})
+	if !strings.Contains(stderr, "modified after new") {
+		t.Fatalf("expected drift report, got %q", stderr)
+	}
+	if _, err := os.Stat(readme); err != nil {
+		t.Fatalf("expected hand-edited README to survive: %v", err)
+	}
+}
+
+func TestRunUndoRevertsArchiveAndUnarchive(t *testing.T) {
+	root := t.TempDir()
+	if code := RunInit([]string{"--root", root}); code != 0 {
+		t.Fatalf("RunInit returned %d", code)
+	}
+	captureOutput(t, func() {
+		if code := RunNew([]string{"done", "old-plan", "--root", root}); code != 0 {
+			t.Fatalf("RunNew returned %d", code)
+		}
+	})
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+	doneReadme := filepath.Join(plansRoot, "done", "old-plan", "README.md")
+	archivedReadme := filepath.Join(plansRoot, "archive", strconv.Itoa(time.Now().Year()), "old-plan", "README.md")
+	original, err := os.ReadFile(doneReadme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readmeWrites := func() int {
+		entries, err := journal.List(filepath.Join(root, ".pacto"))
+		if err != nil || len(entries) == 0 {
+			t.Fatalf("expected journal entries, got %d (%v)", len(entries), err)
+		}
+		n := 0
+		for _, op := range entries[0].Ops {
+			if op.Kind == journal.OpWrite && strings.HasSuffix(op.Path, "/old-plan/README.md") {
+				n++
+			}
+		}
+		return n
+	}
+	undo := func() {
+		t.Helper()
+		_, stderr := captureOutput(t, func() {
+			if code := RunUndo([]string{"--root", root}); code != 0 {
+				t.Fatalf("RunUndo returned %d", code)
+			}
+		})
+		if stderr != "" {
+			t.Fatalf("unexpected stderr: %q", stderr)
+		}
+	}
+
+	captureOutput(t, func() {
+		if code := RunArchive([]string{"done", "old-plan", "--root", root}); code != 0 {
+			t.Fatalf("RunArchive returned %d", code)
+		}
+	})
+	if n := readmeWrites(); n != 1 {
+		t.Fatalf("expected archive to write the plan README once, got %d", n)
+	}
+	undo()
+	if b, _ := os.ReadFile(doneReadme); string(b) != string(original) {
+		t.Fatalf("expected README restored after undoing archive, got %q", string(b))
+	}
+
+	captureOutput(t, func() {
+		if code := RunArchive([]string{"done", "old-plan", "--root", root}); code != 0 {
+			t.Fatalf("RunArchive returned %d", code)
+		}
+	})
+	archived, err := os.ReadFile(archivedReadme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	captureOutput(t, func() {
+		if code := RunUnarchive([]string{"old-plan", "--root", root}); code != 0 {
+			t.Fatalf("RunUnarchive returned %d", code)
+		}
+	})
+	if n := readmeWrites(); n != 1 {
+		t.Fatalf("expected unarchive to write the plan README once, got %d", n)
+	}
+	undo()
+	if b, _ := os.ReadFile(archivedReadme); string(b) != string(archived) {
+		t.Fatalf("expected archived README restored after undoing unarchive, got %q", string(b))
+	}
+	if _, err := os.Stat(doneReadme); !os.IsNotExist(err) {
+		t.Fatalf("expected done/old-plan removed, got %v", err)
+	}
+}
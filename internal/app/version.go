@@ -1,6 +1,10 @@
 package app
 
-import "fmt"
+import (
+	"fmt"
+
+	"pacto/internal/plugins"
+)
 
 // Version is injected at build time via -ldflags.
 var Version = "dev"
@@ -8,3 +12,7 @@ var Version = "dev"
 func VersionLine() string {
 	return fmt.Sprintf("pacto version %s\n", Version)
 }
+
+func init() {
+	plugins.PactoVersion = Version
+}
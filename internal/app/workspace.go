@@ -0,0 +1,61 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"pacto/internal/fsutil"
+	"pacto/internal/i18n"
+	"pacto/internal/journal"
+)
+
+var workspaceLockTimeout = 10 * time.Second
+
+// workspaceStateDir returns the .pacto directory that owns plansRoot. Minimal
+// roots outside a .pacto workspace keep their state under <plansRoot>/.pacto.
+func workspaceStateDir(plansRoot string) string {
+	parent := filepath.Dir(plansRoot)
+	if filepath.Base(parent) == ".pacto" {
+		return parent
+	}
+	return filepath.Join(plansRoot, ".pacto")
+}
+
+func lockWorkspace(plansRoot string, lang i18n.Language) (*fsutil.Lock, int, bool) {
+	lock, err := fsutil.AcquireLock(workspaceStateDir(plansRoot), workspaceLockTimeout)
+	if err != nil {
+		if errors.Is(err, fsutil.ErrLockTimeout) {
+			fmt.Fprintln(os.Stderr, tr(lang, "another pacto command is modifying this workspace; retry when it finishes", "otro comando de pacto está modificando este workspace; reintenta cuando termine"))
+			return nil, 3, false
+		}
+		fmt.Fprintf(os.Stderr, "acquire workspace lock: %v\n", err)
+		return nil, 3, false
+	}
+	return lock, 0, true
+}
+
+func reportWriteError(what string, err error, lang i18n.Language) int {
+	if errors.Is(err, fsutil.ErrConflict) {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", what, err)
+		fmt.Fprintln(os.Stderr, tr(lang, "next action: review the external edit, then re-run the command", "siguiente acción: revisa la edición externa y vuelve a ejecutar el comando"))
+		return 3
+	}
+	fmt.Fprintf(os.Stderr, "%s: %v\n", what, err)
+	return 3
+}
+
+func newJournal(plansRoot, command string, args []string) *journal.Recorder {
+	return journal.NewRecorder(workspaceStateDir(plansRoot), command, args)
+}
+
+// commitJournal records whatever the command managed to change, including
+// partial work from a failed run, so it stays reversible with `pacto undo`.
+func commitJournal(rec *journal.Recorder, summary string) {
+	recordTouched(rec.Touched())
+	if _, err := rec.Commit(summary); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: journal: %v\n", err)
+	}
+}
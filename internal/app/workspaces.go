@@ -0,0 +1,209 @@
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"pacto/internal/analyze"
+	"pacto/internal/config"
+	"pacto/internal/model"
+)
+
+// maxWorkspaceDepth bounds how deep nested workspaces are searched for.
+const maxWorkspaceDepth = 4
+
+// workspaceCommands accept --workspace <path>, which is rewritten to --root
+// before the command parses its flags.
+var workspaceCommands = map[string]bool{
+	"status": true, "new": true, "explore": true, "exec": true, "move": true,
+	"rename": true, "split": true, "merge": true, "archive": true,
+	"unarchive": true, "undo": true, "log": true,
+}
+
+// findWorkspaces lists the Pacto workspaces at or below projectRoot as
+// slash-separated paths relative to it, "." being projectRoot itself.
+func findWorkspaces(projectRoot string) []string {
+	out := []string{}
+	_ = filepath.WalkDir(projectRoot, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(projectRoot, p)
+		rel = filepath.ToSlash(rel)
+		if rel != "." {
+			name := d.Name()
+			if strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" || strings.Count(rel, "/")+1 > maxWorkspaceDepth {
+				return filepath.SkipDir
+			}
+		}
+		if plansRoot, ok := resolvePlanRoot(p); ok {
+			out = append(out, rel)
+			if plansRoot == p && rel != "." {
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	sort.Strings(out)
+	return out
+}
+
+// workspaceDir is the project directory owning plansRoot.
+func workspaceDir(plansRoot string) string {
+	if parent := filepath.Dir(plansRoot); filepath.Base(parent) == ".pacto" {
+		return filepath.Dir(parent)
+	}
+	return plansRoot
+}
+
+// topProjectRoot returns the outermost workspace containing base.
+func topProjectRoot(base string) (string, bool) {
+	top := ""
+	for cur := cleanAbs(base); ; cur = filepath.Dir(cur) {
+		if _, ok := resolvePlanRoot(cur); ok {
+			top = cur
+		}
+		if filepath.Dir(cur) == cur {
+			break
+		}
+	}
+	return top, top != ""
+}
+
+// rewriteWorkspaceArg turns --workspace <path> into --root <dir>, with path
+// relative to the outermost workspace containing the current directory.
+func rewriteWorkspaceArg(cmd string, args []string) ([]string, error) {
+	name, found := "", false
+	out := make([]string, 0, len(args))
+	hasRoot := false
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--workspace" || a == "-workspace":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("flag --workspace expects a path")
+			}
+			name, found = args[i+1], true
+			i++
+			continue
+		case strings.HasPrefix(a, "--workspace="):
+			name, found = strings.TrimPrefix(a, "--workspace="), true
+			continue
+		case a == "--root" || a == "-root" || strings.HasPrefix(a, "--root="):
+			hasRoot = true
+		}
+		out = append(out, a)
+	}
+	if !found {
+		return args, nil
+	}
+	if !workspaceCommands[cmd] {
+		return nil, fmt.Errorf("flag --workspace is not supported by pacto %s", cmd)
+	}
+	if hasRoot {
+		return nil, fmt.Errorf("flags --workspace and --root are mutually exclusive")
+	}
+	cwd, err := filepath.Abs(".")
+	if err != nil {
+		return nil, err
+	}
+	top, ok := topProjectRoot(cwd)
+	if !ok {
+		return nil, fmt.Errorf("no pacto workspace found from %s", cwd)
+	}
+	dir := strings.TrimSpace(name)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(top, filepath.FromSlash(dir))
+	}
+	if _, ok := resolvePlanRoot(dir); !ok {
+		return nil, fmt.Errorf("unknown workspace %q (available: %s)", name, strings.Join(findWorkspaces(top), ", "))
+	}
+	return append(out, "--root", dir), nil
+}
+
+// buildRecursiveStatusReport merges the reports of every workspace below
+// the one owning cfg.PlansRoot. Nested workspaces verify claims with their
+// own configuration (see workspaceConfig); plans are tagged with their
+// workspace path.
+func buildRecursiveStatusReport(cfg config.Config, cfgWarnings []string) (model.StatusReport, []string, int, bool) {
+	top := workspaceDir(cfg.PlansRoot)
+	var merged model.StatusReport
+	warnings := []string{}
+	seenStates := map[string]bool{}
+	for _, ws := range findWorkspaces(top) {
+		wcfg := cfg
+		if ws != "." {
+			var wsWarnings []string
+			var err error
+			wcfg, wsWarnings, err = workspaceConfig(cfg, filepath.Join(top, filepath.FromSlash(ws)))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "workspace %s: %v\n", ws, err)
+				return model.StatusReport{}, nil, 2, false
+			}
+			for _, w := range wsWarnings {
+				warnings = append(warnings, fmt.Sprintf("workspace %s: %s", ws, w))
+			}
+		}
+		sm, code, ok := loadStateMachine(wcfg.PlansRoot)
+		if !ok {
+			return model.StatusReport{}, nil, code, false
+		}
+		if wcfg.State != "all" && !sm.Has(wcfg.State) {
+			warnings = append(warnings, fmt.Sprintf("workspace %s has no state %q; skipped", ws, wcfg.State))
+			continue
+		}
+		rep, code, ok := buildStatusReport(wcfg, cfgWarnings)
+		if !ok {
+			return model.StatusReport{}, nil, code, false
+		}
+		for j := range rep.Plans {
+			rep.Plans[j].Workspace = ws
+		}
+		if merged.Root == "" {
+			merged = rep
+			merged.Plans = nil
+			merged.States = nil
+		}
+		merged.Workspaces = append(merged.Workspaces, ws)
+		merged.Plans = append(merged.Plans, rep.Plans...)
+		for _, s := range rep.States {
+			if !seenStates[s] {
+				seenStates[s] = true
+				merged.States = append(merged.States, s)
+			}
+		}
+	}
+	if merged.Plans == nil {
+		merged.Plans = []model.PlanStatus{}
+	}
+	merged.Summary = analyze.Summarize(merged.Plans)
+	return merged, warnings, 0, true
+}
+
+// workspaceConfig resolves the configuration of the nested workspace in dir.
+// Its own layers decide repo_root (dir by default) and which claims are
+// verified; the run-wide settings of cfg, such as state and fail_on, stay.
+func workspaceConfig(cfg config.Config, dir string) (config.Config, []string, error) {
+	own, warnings, err := config.Load("", dir)
+	if err != nil {
+		return cfg, nil, err
+	}
+	wcfg := cfg
+	wcfg.PlansRoot, _ = resolvePlanRoot(dir)
+	wcfg.RepoRoot = dir
+	if r := strings.TrimSpace(own.RepoRoot); r != "" {
+		wcfg.RepoRoot = cleanAbs(r)
+	}
+	if info, err := os.Stat(wcfg.RepoRoot); err != nil || !info.IsDir() {
+		return cfg, nil, fmt.Errorf("repo root does not exist or is not a directory: %s", wcfg.RepoRoot)
+	}
+	wcfg.ClaimsPaths = own.ClaimsPaths
+	wcfg.ClaimsSymbols = own.ClaimsSymbols
+	wcfg.ClaimsEndpoints = own.ClaimsEndpoints
+	wcfg.ClaimsTestRefs = own.ClaimsTestRefs
+	return wcfg, warnings, nil
+}
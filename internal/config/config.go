@@ -2,12 +2,9 @@ package config
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
-
-	"pacto/internal/yamlutil"
 )
 
 type Config struct {
@@ -25,6 +22,7 @@ type Config struct {
 	ClaimsSymbols   bool
 	ClaimsEndpoints bool
 	ClaimsTestRefs  bool
+	UILanguage      string
 }
 
 func Defaults(_ string) Config {
@@ -46,112 +44,101 @@ func Defaults(_ string) Config {
 	}
 }
 
+// Load resolves the layered configuration for root (see Resolve) and
+// returns the effective values with warnings about unknown or invalid keys.
 func Load(configPath, root string) (Config, []string, error) {
-	cfg := Defaults(root)
-	warnings := make([]string, 0)
+	res, err := Resolve(configPath, root)
+	return res.Config, res.Warnings, err
+}
 
-	path := configPath
-	if strings.TrimSpace(path) == "" {
-		path = filepath.Join(root, ".pacto-engine.yaml")
-	}
-	if !filepath.IsAbs(path) {
-		path = filepath.Join(root, path)
-	}
-	path = filepath.Clean(path)
-	st, err := os.Stat(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return cfg, warnings, nil
+// set applies one flattened key to cfg. Relative roots resolve against
+// baseDir. It returns a warning for deprecated keys and an error for values
+// of the wrong type.
+func (cfg *Config) set(key string, v any, baseDir string) (string, error) {
+	switch key {
+	case "root", "pacto_root":
+		cfg.Root = resolveRootValue(asString(v), baseDir)
+	case "plans_root":
+		cfg.PlansRoot = resolveRootValue(asString(v), baseDir)
+		return "config key 'plans_root' is deprecated for status; use 'root' and 'repo_root'", nil
+	case "repo_root":
+		cfg.RepoRoot = resolveRootValue(asString(v), baseDir)
+	case "mode":
+		cfg.Mode = asString(v)
+	case "format":
+		cfg.Format = asString(v)
+	case "fail_on":
+		cfg.FailOn = asString(v)
+	case "state":
+		cfg.State = asString(v)
+	case "ui.language":
+		cfg.UILanguage = asString(v)
+	case "include_archive", "verification.claims.paths", "verification.claims.symbols", "verification.claims.endpoints", "verification.claims.test_refs":
+		b, err := parseBoolAny(v)
+		if err != nil {
+			return "", err
 		}
-		return cfg, warnings, err
-	}
-	if st.IsDir() {
-		return cfg, warnings, fmt.Errorf("config path is a directory: %s", path)
-	}
-
-	raw, err := yamlutil.ReadFileMap(path)
-	if err != nil {
-		return cfg, warnings, err
-	}
-	vals := flatten(raw)
-	known := map[string]bool{
-		"root":                           true,
-		"pacto_root":                     true,
-		"plans_root":                     true,
-		"repo_root":                      true,
-		"mode":                           true,
-		"format":                         true,
-		"fail_on":                        true,
-		"state":                          true,
-		"include_archive":                true,
-		"limits.max_next_actions":        true,
-		"limits.max_blockers":            true,
-		"verification.claims.paths":      true,
-		"verification.claims.symbols":    true,
-		"verification.claims.endpoints":  true,
-		"verification.claims.test_refs":  true,
-	}
-
-	for k, v := range vals {
-		switch k {
-		case "root":
-			cfg.Root = resolveRootValue(asString(v), filepath.Dir(path))
-		case "pacto_root":
-			cfg.Root = resolveRootValue(asString(v), filepath.Dir(path))
-		case "plans_root":
-			cfg.PlansRoot = resolveRootValue(asString(v), filepath.Dir(path))
-			warnings = append(warnings, "config key 'plans_root' is deprecated for status; use 'root' and 'repo_root'")
-		case "repo_root":
-			cfg.RepoRoot = resolveRootValue(asString(v), filepath.Dir(path))
-		case "mode":
-			cfg.Mode = asString(v)
-		case "format":
-			cfg.Format = asString(v)
-		case "fail_on":
-			cfg.FailOn = asString(v)
-		case "state":
-			cfg.State = asString(v)
+		switch key {
 		case "include_archive":
-			b, e := parseBoolAny(v)
-			if e != nil {
-				warnings = append(warnings, fmt.Sprintf("invalid include_archive: %q", asString(v)))
-				continue
-			}
 			cfg.IncludeArchive = b
-		case "limits.max_next_actions":
-			n, e := parseIntAny(v)
-			if e == nil {
-				cfg.MaxNextActions = n
-			}
-		case "limits.max_blockers":
-			n, e := parseIntAny(v)
-			if e == nil {
-				cfg.MaxBlockers = n
-			}
 		case "verification.claims.paths":
-			if b, e := parseBoolAny(v); e == nil {
-				cfg.ClaimsPaths = b
-			}
+			cfg.ClaimsPaths = b
 		case "verification.claims.symbols":
-			if b, e := parseBoolAny(v); e == nil {
-				cfg.ClaimsSymbols = b
-			}
+			cfg.ClaimsSymbols = b
 		case "verification.claims.endpoints":
-			if b, e := parseBoolAny(v); e == nil {
-				cfg.ClaimsEndpoints = b
-			}
-		case "verification.claims.test_refs":
-			if b, e := parseBoolAny(v); e == nil {
-				cfg.ClaimsTestRefs = b
-			}
+			cfg.ClaimsEndpoints = b
 		default:
-			if !known[k] {
-				warnings = append(warnings, fmt.Sprintf("unknown config key: %s", k))
-			}
+			cfg.ClaimsTestRefs = b
+		}
+	case "limits.max_next_actions", "limits.max_blockers":
+		n, err := parseIntAny(v)
+		if err != nil {
+			return "", fmt.Errorf("invalid int %q", asString(v))
+		}
+		if key == "limits.max_next_actions" {
+			cfg.MaxNextActions = n
+		} else {
+			cfg.MaxBlockers = n
 		}
+	default:
+		return "", fmt.Errorf("unknown config key: %s", key)
 	}
+	return "", nil
+}
 
-	return cfg, warnings, nil
+// Get returns the value of key formatted as it is printed by pacto config.
+func (cfg Config) Get(key string) (string, bool) {
+	switch key {
+	case "root":
+		return cfg.Root, true
+	case "repo_root":
+		return cfg.RepoRoot, true
+	case "mode":
+		return cfg.Mode, true
+	case "format":
+		return cfg.Format, true
+	case "fail_on":
+		return cfg.FailOn, true
+	case "state":
+		return cfg.State, true
+	case "ui.language":
+		return cfg.UILanguage, true
+	case "include_archive":
+		return strconv.FormatBool(cfg.IncludeArchive), true
+	case "limits.max_next_actions":
+		return strconv.Itoa(cfg.MaxNextActions), true
+	case "limits.max_blockers":
+		return strconv.Itoa(cfg.MaxBlockers), true
+	case "verification.claims.paths":
+		return strconv.FormatBool(cfg.ClaimsPaths), true
+	case "verification.claims.symbols":
+		return strconv.FormatBool(cfg.ClaimsSymbols), true
+	case "verification.claims.endpoints":
+		return strconv.FormatBool(cfg.ClaimsEndpoints), true
+	case "verification.claims.test_refs":
+		return strconv.FormatBool(cfg.ClaimsTestRefs), true
+	}
+	return "", false
 }
 
 func resolveRootValue(v, baseDir string) string {
@@ -25,6 +25,27 @@ func TestLoadResolvesRootRelativeToConfigDir(t *testing.T) {
 	}
 }
 
+func TestResolveLayerRootsRelativeToProject(t *testing.T) {
+	root := t.TempDir()
+	userDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", userDir)
+	for _, dir := range []string{filepath.Join(userDir, "pacto"), filepath.Join(root, ".pacto")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(userDir, "pacto", "config.yaml"), "root: .\n")
+	writeFile(t, filepath.Join(root, ".pacto", "config.yaml"), "repo_root: src\n")
+
+	cfg, _, err := Load("", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Root != root || cfg.RepoRoot != filepath.Join(root, "src") {
+		t.Fatalf("expected roots relative to the project, got root=%q repo_root=%q", cfg.Root, cfg.RepoRoot)
+	}
+}
+
 func TestLoadResolvesSplitRootsRelativeToConfigDir(t *testing.T) {
 	root := t.TempDir()
 	cfgDir := filepath.Join(root, "cfg")
@@ -116,6 +137,93 @@ func TestLoadReadsNestedYAMLListsAndBooleans(t *testing.T) {
 	}
 }
 
+func TestResolveLayersAndOrigins(t *testing.T) {
+	root := t.TempDir()
+	userDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", userDir)
+	for _, dir := range []string{filepath.Join(userDir, "pacto"), filepath.Join(root, ".pacto")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(userDir, "pacto", "config.yaml"), "mode: strict\nfail_on: partial\nlimits:\n  max_blockers: 9\nui:\n  language: es\n")
+	writeFile(t, filepath.Join(root, ".pacto", "config.yaml"), "version: 1\nfail_on: blocked\nplugins:\n  enabled: []\n")
+	writeFile(t, filepath.Join(root, ".pacto-engine.yaml"), "limits:\n  max_blockers: 5\n")
+	t.Setenv("PACTO_LIMITS_MAX_BLOCKERS", "2")
+
+	nested := filepath.Join(root, "services", "api")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	res, err := Resolve("", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", res.Warnings)
+	}
+	want := map[string]Value{
+		"mode":                {Key: "mode", Value: "strict", Origin: OriginUser, Source: filepath.Join(userDir, "pacto", "config.yaml")},
+		"fail_on":             {Key: "fail_on", Value: "blocked", Origin: OriginProject, Source: filepath.Join(root, ".pacto", "config.yaml")},
+		"limits.max_blockers": {Key: "limits.max_blockers", Value: "2", Origin: OriginEnv, Source: "PACTO_LIMITS_MAX_BLOCKERS"},
+		"ui.language":         {Key: "ui.language", Value: "es", Origin: OriginUser, Source: filepath.Join(userDir, "pacto", "config.yaml")},
+		"format":              {Key: "format", Value: "table", Origin: OriginDefault},
+	}
+	for k, w := range want {
+		if got := res.Values[k]; got != w {
+			t.Fatalf("%s = %+v, want %+v", k, got, w)
+		}
+	}
+	if res.Config.MaxBlockers != 2 || res.Config.FailOn != "blocked" || res.Config.Mode != "strict" {
+		t.Fatalf("unexpected config: %#v", res.Config)
+	}
+
+	// The project layer is found from nested directories too.
+	res, err = Resolve("", nested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Values["fail_on"].Origin != OriginProject {
+		t.Fatalf("expected project fail_on from nested dir, got %+v", res.Values["fail_on"])
+	}
+
+	t.Setenv("PACTO_INCLUDE_ARCHIVE", "maybe")
+	res, _ = Resolve("", root)
+	if !containsWarning(res.Warnings, "invalid include_archive") || res.Values["include_archive"].Origin != OriginDefault {
+		t.Fatalf("expected invalid env value to be ignored with a warning, got %v %+v", res.Warnings, res.Values["include_archive"])
+	}
+}
+
+func TestSetWritesTypedValuesAndKeepsOtherKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".pacto", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, "version: 1\nplugins:\n  enabled:\n    - acme\n")
+
+	if err := Set(path, "limits.max_next_actions", "4"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(path, "verification.claims.symbols", "off"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"- acme", "max_next_actions: 4", "symbols: false"} {
+		if !strings.Contains(string(b), want) {
+			t.Fatalf("expected %q in %s", want, b)
+		}
+	}
+
+	for key, value := range map[string]string{"mode": "loose", "limits.max_blockers": "0", "include_archive": "maybe", "plans_root": "x"} {
+		if err := Set(path, key, value); err == nil {
+			t.Fatalf("expected Set(%s, %s) to fail", key, value)
+		}
+	}
+}
+
 func containsWarning(warnings []string, sub string) bool {
 	for _, w := range warnings {
 		if strings.Contains(strings.ToLower(w), strings.ToLower(sub)) {
@@ -0,0 +1,276 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"pacto/internal/yamlutil"
+)
+
+// Origins of resolved values, lowest precedence first. Command flags are
+// applied by each command on top of all of them.
+const (
+	OriginDefault = "default"
+	OriginUser    = "user"
+	OriginProject = "project"
+	OriginEngine  = "engine"
+	OriginEnv     = "env"
+)
+
+// Keys lists the keys shown and accepted by pacto config, in display order.
+var Keys = []string{
+	"root",
+	"repo_root",
+	"mode",
+	"format",
+	"fail_on",
+	"state",
+	"include_archive",
+	"limits.max_next_actions",
+	"limits.max_blockers",
+	"verification.claims.paths",
+	"verification.claims.symbols",
+	"verification.claims.endpoints",
+	"verification.claims.test_refs",
+	"ui.language",
+}
+
+// fileOnlyKeys are deprecated spellings still read from files; their values
+// are reported under the canonical key.
+var fileOnlyKeys = map[string]string{
+	"pacto_root": "root",
+	"plans_root": "plans_root",
+}
+
+// allowedValues restricts keys that pacto config set validates up front.
+var allowedValues = map[string][]string{
+	"mode":        {"compat", "strict"},
+	"format":      {"table", "json"},
+	"fail_on":     {"none", "unverified", "partial", "blocked"},
+	"ui.language": {"en", "es"},
+}
+
+// Value is a resolved key with the layer it came from. Source is the file
+// path or environment variable name; it is empty for defaults.
+type Value struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Origin string `json:"origin"`
+	Source string `json:"source,omitempty"`
+}
+
+type Resolved struct {
+	Config   Config
+	Values   map[string]Value
+	Warnings []string
+}
+
+// Resolve layers built-in defaults, the user config, the project
+// .pacto/config.yaml, .pacto-engine.yaml (or configPath) and PACTO_* env
+// vars, each overriding the previous one.
+func Resolve(configPath, root string) (Resolved, error) {
+	res := Resolved{Config: Defaults(root), Values: map[string]Value{}}
+	for _, k := range Keys {
+		v, _ := res.Config.Get(k)
+		res.Values[k] = Value{Key: k, Value: v, Origin: OriginDefault}
+	}
+	if p := UserPath(); p != "" {
+		if err := res.applyFile(p, root, OriginUser, false); err != nil {
+			return res, err
+		}
+	}
+	if p := ProjectPath(root); p != "" {
+		if err := res.applyFile(p, filepath.Dir(filepath.Dir(p)), OriginProject, false); err != nil {
+			return res, err
+		}
+	}
+	engine := EnginePath(configPath, root)
+	if err := res.applyFile(engine, filepath.Dir(engine), OriginEngine, true); err != nil {
+		return res, err
+	}
+	res.applyEnv(root)
+	return res, nil
+}
+
+// applyFile reads one layer, resolving relative roots against baseDir: the
+// directory holding .pacto for the project file, the resolved root for the
+// user file (shared by every project) and the file's own directory for the
+// engine file. Only the engine file is strict about unknown keys; the others
+// also hold workspace, plugin and onboarding settings. The UI language is a
+// workspace setting, so the engine file cannot set it.
+func (r *Resolved) applyFile(path, baseDir, origin string, strict bool) error {
+	st, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if st.IsDir() {
+		return fmt.Errorf("config path is a directory: %s", path)
+	}
+	raw, err := yamlutil.ReadFileMap(path)
+	if err != nil {
+		return err
+	}
+	vals := flatten(raw)
+	keys := make([]string, 0, len(vals))
+	for k := range vals {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		canonical, ok := fileOnlyKeys[k]
+		if !ok {
+			if _, known := r.Config.Get(k); !known || (strict && k == "ui.language") {
+				if strict {
+					r.Warnings = append(r.Warnings, fmt.Sprintf("unknown config key: %s", k))
+				}
+				continue
+			}
+			canonical = k
+		}
+		r.apply(canonical, k, vals[k], baseDir, origin, path)
+	}
+	return nil
+}
+
+func (r *Resolved) applyEnv(root string) {
+	for _, k := range Keys {
+		name := EnvName(k)
+		v, ok := os.LookupEnv(name)
+		if !ok || strings.TrimSpace(v) == "" {
+			continue
+		}
+		r.apply(k, k, strings.TrimSpace(v), root, OriginEnv, name)
+	}
+}
+
+func (r *Resolved) apply(canonical, key string, v any, baseDir, origin, source string) {
+	warning, err := r.Config.set(key, v, baseDir)
+	if err != nil {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("invalid %s: %q", key, asString(v)))
+		return
+	}
+	if warning != "" {
+		r.Warnings = append(r.Warnings, warning)
+	}
+	if _, listed := r.Values[canonical]; listed {
+		val, _ := r.Config.Get(canonical)
+		r.Values[canonical] = Value{Key: canonical, Value: val, Origin: origin, Source: source}
+	}
+}
+
+// EnvName is the environment variable overriding key, e.g. PACTO_FAIL_ON
+// for fail_on and PACTO_LIMITS_MAX_BLOCKERS for limits.max_blockers.
+func EnvName(key string) string {
+	return "PACTO_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// UserPath is $XDG_CONFIG_HOME/pacto/config.yaml, falling back to
+// ~/.config/pacto/config.yaml.
+func UserPath() string {
+	if dir := strings.TrimSpace(os.Getenv("XDG_CONFIG_HOME")); dir != "" {
+		return filepath.Join(dir, "pacto", "config.yaml")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".config", "pacto", "config.yaml")
+}
+
+// ProjectPath is the .pacto/config.yaml of root or its nearest parent that
+// has one, or "" when there is none.
+func ProjectPath(root string) string {
+	cur, err := filepath.Abs(root)
+	if err != nil {
+		return ""
+	}
+	for {
+		p := filepath.Join(cur, ".pacto", "config.yaml")
+		if st, err := os.Stat(p); err == nil && !st.IsDir() {
+			return p
+		}
+		parent := filepath.Dir(cur)
+		if parent == cur {
+			return ""
+		}
+		cur = parent
+	}
+}
+
+// EnginePath is configPath resolved against root, defaulting to
+// <root>/.pacto-engine.yaml.
+func EnginePath(configPath, root string) string {
+	path := configPath
+	if strings.TrimSpace(path) == "" {
+		path = filepath.Join(root, ".pacto-engine.yaml")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	return filepath.Clean(path)
+}
+
+// Normalize validates value for key and converts it to the type written
+// to YAML.
+func Normalize(key, value string) (any, error) {
+	if !contains(Keys, key) {
+		return nil, fmt.Errorf("unknown config key: %s (allowed: %s)", key, strings.Join(Keys, ", "))
+	}
+	value = strings.TrimSpace(value)
+	if allowed, ok := allowedValues[key]; ok && !contains(allowed, value) {
+		return nil, fmt.Errorf("invalid value %q for %s (allowed: %s)", value, key, strings.Join(allowed, "|"))
+	}
+	scratch := Defaults("")
+	if _, err := scratch.set(key, value, ""); err != nil {
+		return nil, fmt.Errorf("invalid value %q for %s", value, key)
+	}
+	switch {
+	case key == "include_archive" || strings.HasPrefix(key, "verification.claims."):
+		b, _ := parseBoolAny(value)
+		return b, nil
+	case strings.HasPrefix(key, "limits."):
+		n, _ := parseIntAny(value)
+		if n < 1 {
+			return nil, fmt.Errorf("%s must be >=1", key)
+		}
+		return n, nil
+	}
+	return value, nil
+}
+
+// Set writes key to the YAML file at path, keeping every other key.
+func Set(path, key, value string) error {
+	typed, err := Normalize(key, value)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o775); err != nil {
+		return err
+	}
+	m, err := yamlutil.ReadMapOrDefault(path)
+	if err != nil {
+		return err
+	}
+	parts := strings.Split(key, ".")
+	parent := m
+	for _, p := range parts[:len(parts)-1] {
+		parent = yamlutil.EnsureMap(parent, p)
+	}
+	parent[parts[len(parts)-1]] = typed
+	return yamlutil.WriteMap(path, m)
+}
+
+func contains(items []string, v string) bool {
+	for _, it := range items {
+		if it == v {
+			return true
+		}
+	}
+	return false
+}
@@ -4,31 +4,36 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 
 	"pacto/internal/model"
+	"pacto/internal/states"
 )
 
-var defaultStates = []string{"current", "to-implement", "done", "outdated"}
+const ArchiveDir = "archive"
+
+var reArchiveYear = regexp.MustCompile(`^[0-9]{4}$`)
 
 type Options struct {
 	StateFilter    string
 	IncludeArchive bool
+	// States defaults to the built-in machine when empty.
+	States states.Machine
 }
 
 func FindPlans(root string, opts Options) ([]model.PlanRef, error) {
-	states := selectedStates(opts)
 	res := make([]model.PlanRef, 0)
-	for _, st := range states {
-		plans, err := collectState(root, st)
+	for _, st := range selectedStates(opts) {
+		plans, err := collectDir(filepath.Join(root, st.Folder), st.ID)
 		if err != nil {
 			return nil, err
 		}
 		res = append(res, plans...)
 	}
 	if opts.IncludeArchive {
-		plans, err := collectState(root, "archive")
+		plans, err := FindArchived(root)
 		if err == nil {
 			res = append(res, plans...)
 		}
@@ -43,21 +48,48 @@ func FindPlans(root string, opts Options) ([]model.PlanRef, error) {
 	return res, nil
 }
 
-func selectedStates(opts Options) []string {
+func selectedStates(opts Options) []states.State {
+	sm := opts.States
+	if len(sm.States) == 0 {
+		sm = states.Default()
+	}
 	state := strings.ToLower(strings.TrimSpace(opts.StateFilter))
-	if state == "" || state == "all" {
-		return append([]string{}, defaultStates...)
+	if st, ok := sm.Get(state); ok && sm.Has(state) {
+		return []states.State{st}
 	}
-	for _, st := range defaultStates {
-		if st == state {
-			return []string{state}
+	return append([]states.State{}, sm.States...)
+}
+
+// FindArchived returns plans under archive/<YYYY>/<slug> plus legacy flat
+// archive/<slug> entries, all reported with state "archive".
+func FindArchived(root string) ([]model.PlanRef, error) {
+	archiveDir := filepath.Join(root, ArchiveDir)
+	res, err := collectDir(archiveDir, ArchiveDir)
+	if err != nil {
+		return nil, err
+	}
+	ents, err := os.ReadDir(archiveDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return res, nil
 		}
+		return nil, err
 	}
-	return append([]string{}, defaultStates...)
+	for _, e := range ents {
+		if !e.IsDir() || !reArchiveYear.MatchString(e.Name()) {
+			continue
+		}
+		plans, err := collectDir(filepath.Join(archiveDir, e.Name()), ArchiveDir)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, plans...)
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Dir < res[j].Dir })
+	return res, nil
 }
 
-func collectState(root, st string) ([]model.PlanRef, error) {
-	stateDir := filepath.Join(root, st)
+func collectDir(stateDir, st string) ([]model.PlanRef, error) {
 	ents, err := os.ReadDir(stateDir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -35,3 +35,33 @@ func TestFindPlansByStateFilter(t *testing.T) {
 		t.Fatalf("unexpected plan ref: %#v", plans[0])
 	}
 }
+
+func TestFindPlansIncludesDatedArchive(t *testing.T) {
+	root := t.TempDir()
+	for _, d := range []string{"current", "archive/2025/old-plan", "archive/legacy-plan"} {
+		if err := os.MkdirAll(filepath.Join(root, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, d := range []string{"archive/2025/old-plan", "archive/legacy-plan"} {
+		if err := os.WriteFile(filepath.Join(root, d, "README.md"), []byte("# x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	plans, err := FindPlans(root, Options{IncludeArchive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plans) != 2 {
+		t.Fatalf("expected 2 archived plans, got %#v", plans)
+	}
+	for _, p := range plans {
+		if p.State != "archive" {
+			t.Fatalf("expected archive state, got %#v", p)
+		}
+	}
+	plans, _ = FindPlans(root, Options{})
+	if len(plans) != 0 {
+		t.Fatalf("expected archive excluded by default, got %#v", plans)
+	}
+}
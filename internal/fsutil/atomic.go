@@ -0,0 +1,100 @@
+package fsutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrConflict reports that a file changed on disk between read and write.
+var ErrConflict = errors.New("file changed on disk since it was read")
+
+// Snapshot records the content digest of a file at read time so a later write
+// can detect edits made by other processes in between.
+type Snapshot struct {
+	Path   string
+	Exists bool
+	Sum    [sha256.Size]byte
+}
+
+func ReadSnapshot(path string) ([]byte, Snapshot, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, Snapshot{Path: path}, err
+		}
+		return nil, Snapshot{}, err
+	}
+	return b, Snapshot{Path: path, Exists: true, Sum: sha256.Sum256(b)}, nil
+}
+
+func (s Snapshot) Check() error {
+	b, err := os.ReadFile(s.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			if !s.Exists {
+				return nil
+			}
+			return fmt.Errorf("%w: %s was removed", ErrConflict, s.Path)
+		}
+		return err
+	}
+	if !s.Exists {
+		return fmt.Errorf("%w: %s was created", ErrConflict, s.Path)
+	}
+	sum := sha256.Sum256(b)
+	if !bytes.Equal(sum[:], s.Sum[:]) {
+		return fmt.Errorf("%w: %s", ErrConflict, s.Path)
+	}
+	return nil
+}
+
+// WriteFileAtomic writes data to a temp file in the target directory and
+// renames it into place, so readers never observe a partially written file.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	cleanup := func() { _ = os.Remove(tmpName) }
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return err
+	}
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		cleanup()
+		return err
+	}
+	return nil
+}
+
+// WriteFileChecked verifies snap still matches the file on disk and then writes
+// data atomically. It returns ErrConflict when the file was edited externally.
+func WriteFileChecked(snap Snapshot, data []byte, perm os.FileMode) error {
+	if err := snap.Check(); err != nil {
+		return err
+	}
+	return WriteFileAtomic(snap.Path, data, perm)
+}
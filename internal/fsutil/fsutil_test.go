@@ -0,0 +1,65 @@
+package fsutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteFileCheckedDetectsExternalEdit(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "plan.md")
+	if err := os.WriteFile(p, []byte("one\n"), 0o664); err != nil {
+		t.Fatal(err)
+	}
+	_, snap, err := ReadSnapshot(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte("edited elsewhere\n"), 0o664); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileChecked(snap, []byte("two\n"), 0o664); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+	b, _ := os.ReadFile(p)
+	if string(b) != "edited elsewhere\n" {
+		t.Fatalf("external edit was clobbered: %q", string(b))
+	}
+
+	_, snap, err = ReadSnapshot(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileChecked(snap, []byte("three\n"), 0o664); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	b, _ = os.ReadFile(p)
+	if string(b) != "three\n" {
+		t.Fatalf("unexpected content: %q", string(b))
+	}
+	ents, _ := os.ReadDir(filepath.Dir(p))
+	if len(ents) != 1 {
+		t.Fatalf("expected temp files cleaned up, got %d entries", len(ents))
+	}
+}
+
+func TestAcquireLockIsExclusive(t *testing.T) {
+	dir := t.TempDir()
+	first, err := AcquireLock(dir, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AcquireLock(dir, 100*time.Millisecond); !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("expected lock timeout while held, got %v", err)
+	}
+	if err := first.Release(); err != nil {
+		t.Fatal(err)
+	}
+	second, err := AcquireLock(dir, time.Second)
+	if err != nil {
+		t.Fatalf("expected lock after release, got %v", err)
+	}
+	_ = second.Release()
+}
@@ -1,10 +1,14 @@
 package integrations
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 type localAdapter struct {
@@ -61,10 +65,194 @@ func adapters() map[string]Adapter {
 		"cursor":   localAdapter{toolID: "cursor", skillsDir: ".cursor", commandsDir: ".cursor/commands"},
 		"claude":   localAdapter{toolID: "claude", skillsDir: ".claude", commandsDir: ".claude/commands"},
 		"opencode": localAdapter{toolID: "opencode", skillsDir: ".opencode", commandsDir: ".opencode/commands"},
+		"windsurf": templateAdapter{
+			toolID: "windsurf",
+			skill: &artifactTemplate{
+				Path:        ".windsurf/rules/pacto-{{workflow}}.md",
+				FrontMatter: []frontMatterField{{"trigger", "model_decision"}, {"description", "{{summary}}"}},
+			},
+			command: &artifactTemplate{
+				Path:        ".windsurf/workflows/{{command}}.md",
+				FrontMatter: []frontMatterField{{"description", "{{summary}}"}},
+			},
+		},
+		"cline": templateAdapter{
+			toolID:  "cline",
+			skill:   &artifactTemplate{Path: ".clinerules/pacto-{{workflow}}.md"},
+			command: &artifactTemplate{Path: ".clinerules/workflows/{{command}}.md"},
+		},
+		"roo": templateAdapter{
+			toolID: "roo",
+			skill:  &artifactTemplate{Path: ".roo/rules/pacto-{{workflow}}.md"},
+			command: &artifactTemplate{
+				Path:        ".roo/commands/{{command}}.md",
+				FrontMatter: []frontMatterField{{"description", "{{summary}}"}},
+			},
+		},
+		"copilot": templateAdapter{
+			toolID: "copilot",
+			skill: &artifactTemplate{
+				Path:        ".github/instructions/pacto-{{workflow}}.instructions.md",
+				FrontMatter: []frontMatterField{{"applyTo", "**"}, {"description", "{{summary}}"}},
+			},
+			command: &artifactTemplate{
+				Path:        ".github/prompts/{{command}}.prompt.md",
+				FrontMatter: []frontMatterField{{"mode", "agent"}, {"description", "{{summary}}"}},
+			},
+		},
+		// Aider has no custom commands; its conventions files are loaded
+		// with `read:` in .aider.conf.yml.
+		"aider": templateAdapter{
+			toolID: "aider",
+			skill:  &artifactTemplate{Path: ".aider/pacto/pacto-{{workflow}}.md"},
+		},
+		"gemini": templateAdapter{
+			toolID: "gemini",
+			skill: &artifactTemplate{
+				Path:        ".gemini/skills/pacto-{{workflow}}/SKILL.md",
+				FrontMatter: []frontMatterField{{"name", "pacto-{{workflow}}"}, {"description", "{{summary}}"}},
+			},
+			command: &artifactTemplate{Path: ".gemini/commands/{{command}}.toml", Format: FormatTOML},
+		},
 	}
 }
 
+// GetAdapter returns a built-in adapter.
 func GetAdapter(toolID string) (Adapter, bool) {
 	a, ok := adapters()[toolID]
 	return a, ok
 }
+
+// ProjectAdapter returns a built-in adapter or one declared under
+// .pacto/adapters of projectRoot.
+func ProjectAdapter(projectRoot, toolID string) (Adapter, bool) {
+	if a, ok := GetAdapter(toolID); ok {
+		return a, true
+	}
+	defs, _ := LoadAdapterDefinitions(projectRoot)
+	for _, d := range defs {
+		if d.Metadata.ID == toolID {
+			return d.adapter(), true
+		}
+	}
+	return nil, false
+}
+
+// ErrUnsupportedArtifact is returned by adapters for artifact kinds their
+// tool has no place for; generation skips those.
+var ErrUnsupportedArtifact = errors.New("artifact kind not supported by tool")
+
+// ArtifactFramer is implemented by adapters that put the managed block
+// inside a frame, such as front matter or a TOML prompt string.
+type ArtifactFramer interface {
+	ArtifactFrame(kind string, wf WorkflowSpec) (prefix, suffix string)
+}
+
+const (
+	FormatMarkdown = "markdown"
+	FormatTOML     = "toml"
+)
+
+type frontMatterField struct {
+	Key   string
+	Value string
+}
+
+// artifactTemplate places one artifact kind. Path is relative to the
+// project root, or to the home directory when it starts with "~/", and may
+// use {{workflow}}, {{command}}, {{title}}, {{summary}} and {{tool}}.
+type artifactTemplate struct {
+	Path        string
+	Format      string
+	FrontMatter []frontMatterField
+}
+
+type templateAdapter struct {
+	toolID  string
+	skill   *artifactTemplate
+	command *artifactTemplate
+}
+
+func (a templateAdapter) ToolID() string { return a.toolID }
+
+func (a templateAdapter) SkillFilePath(projectRoot, workflowID string) (string, error) {
+	if workflowID == "" {
+		return "", fmt.Errorf("workflow ID is required")
+	}
+	return a.path(a.skill, projectRoot, workflowSpec(workflowID, ""))
+}
+
+func (a templateAdapter) CommandFilePath(projectRoot, commandID string) (string, error) {
+	if commandID == "" {
+		return "", fmt.Errorf("command ID is required")
+	}
+	return a.path(a.command, projectRoot, workflowSpec("", commandID))
+}
+
+func (a templateAdapter) path(t *artifactTemplate, projectRoot string, wf WorkflowSpec) (string, error) {
+	if t == nil {
+		return "", ErrUnsupportedArtifact
+	}
+	rel := expandTemplate(t.Path, a.toolID, wf)
+	if home, ok := strings.CutPrefix(rel, "~/"); ok {
+		u, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(u, filepath.FromSlash(home)), nil
+	}
+	return filepath.Join(projectRoot, filepath.FromSlash(rel)), nil
+}
+
+func (a templateAdapter) ArtifactFrame(kind string, wf WorkflowSpec) (string, string) {
+	t := a.skill
+	if kind == "command" {
+		t = a.command
+	}
+	if t == nil {
+		return "", ""
+	}
+	if t.Format == FormatTOML {
+		// A literal multi-line string keeps the Markdown body verbatim.
+		return "description = " + strconv.Quote(wf.Summary) + "\nprompt = '''\n", "'''\n"
+	}
+	if len(t.FrontMatter) == 0 {
+		return "", ""
+	}
+	var b strings.Builder
+	b.WriteString("---\n")
+	for _, f := range t.FrontMatter {
+		b.WriteString(f.Key + ": " + yamlScalar(expandTemplate(f.Value, a.toolID, wf)) + "\n")
+	}
+	b.WriteString("---\n\n")
+	return b.String(), ""
+}
+
+// workflowSpec finds the workflow by id or command id so paths can use
+// every template variable.
+func workflowSpec(workflowID, commandID string) WorkflowSpec {
+	for _, wf := range Workflows() {
+		if wf.WorkflowID == workflowID || (commandID != "" && wf.CommandID == commandID) {
+			return wf
+		}
+	}
+	return WorkflowSpec{WorkflowID: workflowID, CommandID: commandID}
+}
+
+func expandTemplate(s, toolID string, wf WorkflowSpec) string {
+	return strings.NewReplacer(
+		"{{workflow}}", wf.WorkflowID,
+		"{{command}}", wf.CommandID,
+		"{{title}}", wf.Title,
+		"{{summary}}", wf.Summary,
+		"{{tool}}", toolID,
+	).Replace(s)
+}
+
+func yamlScalar(v string) string {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return strconv.Quote(v)
+	}
+	return strings.TrimSuffix(string(b), "\n")
+}
@@ -0,0 +1,194 @@
+package integrations
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Drift states reported by CheckTool.
+const (
+	DriftOK        = "ok"
+	DriftMissing   = "missing"
+	DriftOutdated  = "outdated"
+	DriftModified  = "modified"
+	DriftUnmanaged = "unmanaged"
+)
+
+// ArtifactCheck compares one artifact on disk with what install would
+// write. Current and Expected hold whole file contents.
+type ArtifactCheck struct {
+	Tool       string `json:"tool"`
+	Kind       string `json:"kind"`
+	WorkflowID string `json:"workflow"`
+	Path       string `json:"path"`
+	Status     string `json:"status"`
+	Current    string `json:"-"`
+	Expected   string `json:"-"`
+	Err        error  `json:"-"`
+}
+
+func (c ArtifactCheck) Drifted() bool {
+	return c.Err == nil && c.Status != DriftOK
+}
+
+// CheckTool renders every artifact of toolID and compares it with disk
+// without writing. A managed block whose content no longer matches the hash
+// in its start marker was edited by hand; one that still matches but differs
+// from the current render is outdated. The live plan context is left out of
+// the comparison, since it changes with every plan update.
+func CheckTool(projectRoot, toolID, live string) []ArtifactCheck {
+	planned, err := planArtifacts(projectRoot, toolID, live)
+	if err != nil {
+		return []ArtifactCheck{{Tool: toolID, Err: err}}
+	}
+	out := make([]ArtifactCheck, 0, len(planned))
+	for _, a := range planned {
+		c := ArtifactCheck{Tool: toolID, Kind: a.kind, WorkflowID: a.workflowID, Path: a.path, Err: a.err}
+		if a.err == nil {
+			c.Status, c.Current, c.Expected, c.Err = checkArtifact(a)
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func checkArtifact(a plannedArtifact) (status, current, expected string, err error) {
+	wrapped := WrapManaged(a.body)
+	b, err := os.ReadFile(a.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DriftMissing, "", a.prefix + wrapped + a.suffix, nil
+		}
+		return "", "", "", err
+	}
+	current = string(b)
+	mb, ok := findManaged(current)
+	if !ok {
+		return DriftUnmanaged, current, a.prefix + wrapped + a.suffix, nil
+	}
+	expected = current[:mb.start] + strings.TrimRight(wrapped, "\n") + current[mb.end:]
+	switch {
+	case withoutLiveContext(mb.body) == withoutLiveContext(strings.TrimSpace(a.body)):
+		return DriftOK, current, current, nil
+	case mb.hash != "" && mb.hash != managedHash(mb.body):
+		return DriftModified, current, expected, nil
+	default:
+		return DriftOutdated, current, expected, nil
+	}
+}
+
+// UnifiedDiff returns a unified diff from a to b with three lines of
+// context, or "" when they are equal.
+func UnifiedDiff(fromName, toName, a, b string) string {
+	if a == b {
+		return ""
+	}
+	x, y := diffSplit(a), diffSplit(b)
+	ops := diffOps(x, y)
+
+	const context = 3
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		// Extend the hunk while changes are at most 2*context lines apart.
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*context {
+				end += min(context, next-end)
+				break
+			}
+			end = next
+		}
+		ax, ay := ops[start].ax, ops[start].ay
+		var nx, ny int
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				nx++
+			}
+			if op.kind != '-' {
+				ny++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(ax, nx), hunkRange(ay, ny))
+		for _, op := range ops[start:end] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.line)
+			out.WriteByte('\n')
+		}
+		i = end
+	}
+	return out.String()
+}
+
+type diffOp struct {
+	kind   byte
+	line   string
+	ax, ay int
+}
+
+func diffSplit(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func diffOps(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	ops := make([]diffOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i], i, j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i], i, j})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j], i, j})
+			j++
+		}
+	}
+	return ops
+}
+
+func hunkRange(start, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if n == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
@@ -0,0 +1,233 @@
+package integrations
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AdapterDefinitionDir holds declarative adapters, one YAML file per tool.
+const AdapterDefinitionDir = ".pacto/adapters"
+
+var adapterIDRe = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// AdapterDefinition describes a tool that is not built in:
+//
+//	apiVersion: pacto/v1alpha1
+//	kind: Adapter
+//	metadata:
+//	  id: zed
+//	spec:
+//	  detect: [".zed"]
+//	  skill:
+//	    path: .zed/skills/pacto-{{workflow}}/SKILL.md
+//	    frontMatter:
+//	      description: "{{summary}}"
+//	  command:
+//	    path: .zed/commands/{{command}}.md
+type AdapterDefinition struct {
+	APIVersion string                `yaml:"apiVersion"`
+	Kind       string                `yaml:"kind"`
+	Metadata   AdapterMetadata       `yaml:"metadata"`
+	Spec       AdapterDefinitionSpec `yaml:"spec"`
+	File       string                `yaml:"-"`
+}
+
+type AdapterMetadata struct {
+	ID          string `yaml:"id"`
+	Description string `yaml:"description"`
+}
+
+type AdapterDefinitionSpec struct {
+	Detect  []string            `yaml:"detect"`
+	Skill   *ArtifactDefinition `yaml:"skill"`
+	Command *ArtifactDefinition `yaml:"command"`
+}
+
+type ArtifactDefinition struct {
+	Path   string `yaml:"path"`
+	Format string `yaml:"format"`
+	// FrontMatter is kept as a node so keys render in file order.
+	FrontMatter yaml.Node `yaml:"frontMatter"`
+}
+
+// LoadAdapterDefinitions reads .pacto/adapters/*.yaml under projectRoot,
+// sorted by file name. Invalid files are reported and skipped.
+func LoadAdapterDefinitions(projectRoot string) ([]AdapterDefinition, []error) {
+	files, _ := filepath.Glob(filepath.Join(projectRoot, filepath.FromSlash(AdapterDefinitionDir), "*.yaml"))
+	more, _ := filepath.Glob(filepath.Join(projectRoot, filepath.FromSlash(AdapterDefinitionDir), "*.yml"))
+	files = append(files, more...)
+	sort.Strings(files)
+
+	out := make([]AdapterDefinition, 0, len(files))
+	var errs []error
+	seen := map[string]string{}
+	for _, f := range files {
+		d, err := ParseAdapterDefinition(f)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if prev, ok := seen[d.Metadata.ID]; ok {
+			errs = append(errs, fmt.Errorf("%s: adapter %q is already defined in %s", f, d.Metadata.ID, prev))
+			continue
+		}
+		seen[d.Metadata.ID] = f
+		out = append(out, d)
+	}
+	return out, errs
+}
+
+func ParseAdapterDefinition(file string) (AdapterDefinition, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return AdapterDefinition{}, err
+	}
+	var d AdapterDefinition
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&d); err != nil {
+		return AdapterDefinition{}, fmt.Errorf("%s: %w", file, err)
+	}
+	d.File = file
+	d.Metadata.ID = normalize(d.Metadata.ID)
+	if err := d.validate(); err != nil {
+		return AdapterDefinition{}, fmt.Errorf("%s: %w", file, err)
+	}
+	return d, nil
+}
+
+func (d AdapterDefinition) validate() error {
+	if d.APIVersion != "pacto/v1alpha1" {
+		return fmt.Errorf("apiVersion must be pacto/v1alpha1")
+	}
+	if d.Kind != "Adapter" {
+		return fmt.Errorf("kind must be Adapter")
+	}
+	if !adapterIDRe.MatchString(d.Metadata.ID) {
+		return fmt.Errorf("metadata.id %q must match %s", d.Metadata.ID, adapterIDRe)
+	}
+	if _, ok := GetAdapter(d.Metadata.ID); ok {
+		return fmt.Errorf("metadata.id %q is a built-in tool", d.Metadata.ID)
+	}
+	if d.Spec.Skill == nil && d.Spec.Command == nil {
+		return fmt.Errorf("spec needs a skill or a command")
+	}
+	if s := d.Spec.Skill; s != nil {
+		if err := s.validate("spec.skill", "{{workflow}}"); err != nil {
+			return err
+		}
+	}
+	if c := d.Spec.Command; c != nil {
+		if err := c.validate("spec.command", "{{command}}", "{{workflow}}"); err != nil {
+			return err
+		}
+	}
+	for i, p := range d.Spec.Detect {
+		if err := checkTemplatePath(p); err != nil {
+			return fmt.Errorf("spec.detect[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (a *ArtifactDefinition) validate(field string, unique ...string) error {
+	if err := checkTemplatePath(a.Path); err != nil {
+		return fmt.Errorf("%s.path: %w", field, err)
+	}
+	ok := false
+	for _, u := range unique {
+		ok = ok || strings.Contains(a.Path, u)
+	}
+	if !ok {
+		return fmt.Errorf("%s.path must contain %s", field, strings.Join(unique, " or "))
+	}
+	switch a.Format {
+	case "", FormatMarkdown, FormatTOML:
+	default:
+		return fmt.Errorf("%s.format must be %s or %s", field, FormatMarkdown, FormatTOML)
+	}
+	if fm := a.FrontMatter; fm.Kind != 0 {
+		if fm.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s.frontMatter must be a mapping", field)
+		}
+		for i := 1; i < len(fm.Content); i += 2 {
+			if fm.Content[i].Kind != yaml.ScalarNode {
+				return fmt.Errorf("%s.frontMatter.%s must be a string", field, fm.Content[i-1].Value)
+			}
+		}
+	}
+	return nil
+}
+
+// checkTemplatePath accepts paths relative to the project root that stay
+// inside it, or paths under the home directory written as "~/...".
+func checkTemplatePath(p string) error {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return fmt.Errorf("is required")
+	}
+	rel := strings.TrimPrefix(p, "~/")
+	if strings.Contains(rel, `\`) || path.IsAbs(rel) || filepath.IsAbs(rel) {
+		return fmt.Errorf("%q must be relative", p)
+	}
+	if c := path.Clean(rel); c == ".." || strings.HasPrefix(c, "../") {
+		return fmt.Errorf("%q escapes the project root", p)
+	}
+	return nil
+}
+
+func (d AdapterDefinition) adapter() templateAdapter {
+	return templateAdapter{
+		toolID:  d.Metadata.ID,
+		skill:   d.Spec.Skill.template(),
+		command: d.Spec.Command.template(),
+	}
+}
+
+func (a *ArtifactDefinition) template() *artifactTemplate {
+	if a == nil {
+		return nil
+	}
+	t := &artifactTemplate{Path: strings.TrimSpace(a.Path), Format: a.Format}
+	for i := 1; i < len(a.FrontMatter.Content); i += 2 {
+		t.FrontMatter = append(t.FrontMatter, frontMatterField{a.FrontMatter.Content[i-1].Value, a.FrontMatter.Content[i].Value})
+	}
+	return t
+}
+
+// detected reports whether any detect path exists.
+func (d AdapterDefinition) detected(projectRoot string) bool {
+	for _, p := range d.Spec.Detect {
+		full := filepath.Join(projectRoot, filepath.FromSlash(p))
+		if rel, ok := strings.CutPrefix(p, "~/"); ok {
+			u, err := os.UserHomeDir()
+			if err != nil {
+				continue
+			}
+			full = filepath.Join(u, filepath.FromSlash(rel))
+		}
+		if _, err := os.Stat(full); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectTools returns the built-in tools followed by the tools declared
+// under .pacto/adapters, plus errors for definitions that failed to load.
+func ProjectTools(projectRoot string) ([]string, []error) {
+	out := SupportedTools()
+	defs, errs := LoadAdapterDefinitions(projectRoot)
+	for _, d := range defs {
+		out = append(out, d.Metadata.ID)
+	}
+	return out, errs
+}
@@ -16,6 +16,13 @@ func DetectTools(projectRoot string) ([]string, error) {
 		{id: "cursor", dir: ".cursor"},
 		{id: "claude", dir: ".claude"},
 		{id: "opencode", dir: ".opencode"},
+		{id: "windsurf", dir: ".windsurf"},
+		{id: "cline", dir: ".clinerules"},
+		{id: "roo", dir: ".roo"},
+		{id: "copilot", dir: ".github/instructions"},
+		{id: "copilot", dir: ".github/prompts"},
+		{id: "aider", dir: ".aider"},
+		{id: "gemini", dir: ".gemini"},
 	}
 
 	out := make([]string, 0)
@@ -24,6 +31,18 @@ func DetectTools(projectRoot string) ([]string, error) {
 			out = append(out, c.id)
 		}
 	}
+	if fileExists(filepath.Join(projectRoot, ".github", "copilot-instructions.md")) {
+		out = append(out, "copilot")
+	}
+	if fileExists(filepath.Join(projectRoot, ".aider.conf.yml")) {
+		out = append(out, "aider")
+	}
+	defs, _ := LoadAdapterDefinitions(projectRoot)
+	for _, d := range defs {
+		if d.detected(projectRoot) {
+			out = append(out, d.Metadata.ID)
+		}
+	}
 
 	if !contains(out, "codex") {
 		home := strings.TrimSpace(os.Getenv("CODEX_HOME"))
@@ -46,6 +65,11 @@ func dirExists(path string) bool {
 	return err == nil && st.IsDir()
 }
 
+func fileExists(path string) bool {
+	st, err := os.Stat(path)
+	return err == nil && !st.IsDir()
+}
+
 func contains(v []string, needle string) bool {
 	for _, x := range v {
 		if x == needle {
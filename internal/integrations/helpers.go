@@ -17,7 +17,3 @@ func splitCSV(v string) []string {
 	}
 	return out
 }
-
-func joinSupported() string {
-	return strings.Join(SupportedTools(), ",")
-}
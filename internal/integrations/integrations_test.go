@@ -1,11 +1,13 @@
 package integrations
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"pacto/internal/model"
 	"pacto/internal/plugins"
 )
 
@@ -102,6 +104,335 @@ func TestGetAdapterPaths(t *testing.T) {
 	}
 }
 
+func TestGenerateForNewBuiltinTools(t *testing.T) {
+	root := t.TempDir()
+	for _, tool := range []string{"windsurf", "cline", "roo", "copilot", "aider", "gemini"} {
+		for _, r := range GenerateForTool(root, tool, false, "") {
+			if r.Err != nil {
+				t.Fatalf("%s %s/%s: %v", tool, r.Kind, r.WorkflowID, r.Err)
+			}
+		}
+	}
+
+	b, err := os.ReadFile(filepath.Join(root, ".github", "instructions", "pacto-status.instructions.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "---\napplyTo: '**'\ndescription: ") || !strings.Contains(string(b), ManagedStart) {
+		t.Fatalf("unexpected copilot instructions:\n%s", b)
+	}
+
+	toml := filepath.Join(root, ".gemini", "commands", "pacto-status.toml")
+	b, err = os.ReadFile(toml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "description = \"") || !strings.Contains(string(b), "prompt = '''\n"+ManagedStart) || !strings.HasSuffix(string(b), ManagedEnd+"\n'''\n") {
+		t.Fatalf("unexpected gemini command:\n%s", b)
+	}
+	// Updates only rewrite the managed block and keep the frame.
+	if _, err := WriteManagedFramed(toml, "", "changed", "", false); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = os.ReadFile(toml)
+	if !strings.HasPrefix(string(b), "description = ") || !strings.Contains(string(b), "changed") {
+		t.Fatalf("frame lost on update:\n%s", b)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, ".aider", "pacto", "pacto-status.md")); err != nil {
+		t.Fatalf("expected aider conventions file: %v", err)
+	}
+	got, _ := DetectTools(root)
+	for _, tool := range []string{"windsurf", "cline", "roo", "copilot", "aider", "gemini"} {
+		if !contains(got, tool) {
+			t.Fatalf("expected %s to be detected, got %v", tool, got)
+		}
+	}
+}
+
+func TestDeclarativeAdapter(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, ".pacto", "adapters")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	def := `apiVersion: pacto/v1alpha1
+kind: Adapter
+metadata:
+  id: zed
+spec:
+  detect: [".zed"]
+  skill:
+    path: .zed/skills/pacto-{{workflow}}/SKILL.md
+    frontMatter:
+      name: pacto-{{workflow}}
+      description: "{{summary}}"
+  command:
+    path: .zed/commands/{{command}}.md
+`
+	if err := os.WriteFile(filepath.Join(dir, "zed.yaml"), []byte(def), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bad := "apiVersion: pacto/v1alpha1\nkind: Adapter\nmetadata:\n  id: claude\nspec:\n  skill:\n    path: ../x/{{workflow}}.md\n"
+	if err := os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte(bad), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tools, errs := ProjectTools(root)
+	if !contains(tools, "zed") || len(errs) != 1 || !strings.Contains(errs[0].Error(), "built-in") {
+		t.Fatalf("unexpected project tools %v, errors %v", tools, errs)
+	}
+	if got, err := ParseProjectToolsArg(root, "zed,claude"); err != nil || len(got) != 2 {
+		t.Fatalf("ParseProjectToolsArg = %v, %v", got, err)
+	}
+	if _, err := ParseToolsArg("zed"); err == nil {
+		t.Fatal("expected ParseToolsArg to reject project adapters")
+	}
+
+	for _, r := range GenerateForTool(root, "zed", false, "") {
+		if r.Err != nil {
+			t.Fatalf("%s/%s: %v", r.Kind, r.WorkflowID, r.Err)
+		}
+	}
+	b, err := os.ReadFile(filepath.Join(root, ".zed", "skills", "pacto-status", "SKILL.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "---\nname: pacto-status\ndescription: ") {
+		t.Fatalf("unexpected front matter:\n%s", b)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".zed", "commands", "pacto-exec.md")); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := DetectTools(root); !contains(got, "zed") {
+		t.Fatalf("expected zed to be detected, got %v", got)
+	}
+}
+
+func TestCheckToolReportsDrift(t *testing.T) {
+	root := t.TempDir()
+	GenerateForTool(root, "cursor", false, "")
+	for _, c := range CheckTool(root, "cursor", "") {
+		if c.Err != nil || c.Status != DriftOK {
+			t.Fatalf("expected fresh install to be ok, got %s %s %v", c.Path, c.Status, c.Err)
+		}
+	}
+
+	skill := filepath.Join(root, ".cursor", "skills", "pacto-status", "SKILL.md")
+	b, _ := os.ReadFile(skill)
+	if err := os.WriteFile(skill, []byte(strings.Replace(string(b), "## Objective", "## Goal", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A block rewritten with a matching hash looks like an older render.
+	command := filepath.Join(root, ".cursor", "commands", "pacto-status.md")
+	if err := os.WriteFile(command, []byte(WrapManaged("# old render")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(root, ".cursor", "commands", "pacto-new.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]ArtifactCheck{}
+	for _, c := range CheckTool(root, "cursor", "") {
+		got[c.Path] = c
+	}
+	if c := got[skill]; c.Status != DriftModified {
+		t.Fatalf("expected hand-edited skill to be modified, got %s", c.Status)
+	}
+	if c := got[command]; c.Status != DriftOutdated {
+		t.Fatalf("expected old render to be outdated, got %s", c.Status)
+	}
+	if c := got[filepath.Join(root, ".cursor", "commands", "pacto-new.md")]; c.Status != DriftMissing {
+		t.Fatalf("expected removed command to be missing, got %s", c.Status)
+	}
+	diff := UnifiedDiff("a", "b", got[skill].Current, got[skill].Expected)
+	if !strings.Contains(diff, "-## Goal\n+## Objective\n") || !strings.Contains(diff, "\n@@ -") {
+		t.Fatalf("unexpected diff:\n%s", diff)
+	}
+}
+
+func TestRemoveForToolUndoesGenerate(t *testing.T) {
+	root := t.TempDir()
+	GenerateForTool(root, "windsurf", false, "")
+	rule := filepath.Join(root, ".windsurf", "rules", "pacto-status.md")
+	b, _ := os.ReadFile(rule)
+	if err := os.WriteFile(rule, append(b, []byte("\n## Team notes\n\nKeep me.\n")...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range RemoveForTool(root, "windsurf", true) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, ".windsurf", "workflows", "pacto-status.md")); err != nil {
+		t.Fatalf("dry run must not remove files: %v", err)
+	}
+
+	got := map[WriteOutcome]int{}
+	for _, r := range RemoveForTool(root, "windsurf", false) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+		got[r.Outcome]++
+	}
+	if got[OutcomeStripped] != 1 || got[OutcomeRemoved] != 2*len(Workflows())-1 {
+		t.Fatalf("unexpected outcomes %v", got)
+	}
+	b, err := os.ReadFile(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "pacto:managed") || !strings.Contains(string(b), "Keep me.") {
+		t.Fatalf("expected only the managed block to be stripped:\n%s", b)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".windsurf", "workflows")); !os.IsNotExist(err) {
+		t.Fatalf("expected empty workflows dir to be pruned, got %v", err)
+	}
+	if _, err := os.Stat(root); err != nil {
+		t.Fatal("project root must never be pruned")
+	}
+}
+
+func TestRemoveForToolStripsFrontMatterWithBlock(t *testing.T) {
+	root := t.TempDir()
+	GenerateForTool(root, "copilot", false, "")
+	prompt := filepath.Join(root, ".github", "prompts", "pacto-exec.prompt.md")
+	b, _ := os.ReadFile(prompt)
+	if err := os.WriteFile(prompt, append([]byte("# Team prompt\n\n"), append(b, []byte("\nKeep me.\n")...)...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range RemoveForTool(root, "copilot", false) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+	}
+	b, err := os.ReadFile(prompt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "# Team prompt\n\nKeep me.\n"; got != want {
+		t.Fatalf("expected front matter to go with the block, got:\n%s", got)
+	}
+}
+
+func TestWorkflowOverridesMergeBeforeRendering(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, ".pacto", "workflows")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	override := `whenToUse: Before every release.
+validationChecklist:
+  append: ["CI is green on main"]
+failureModes:
+  - Release branch missing.
+tools:
+  cursor:
+    whenToUse:
+      append: Cursor users also run it on file open.
+`
+	if err := os.WriteFile(filepath.Join(dir, "status.yaml"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tool := range []string{"cursor", "claude"} {
+		for _, r := range GenerateForTool(root, tool, false, "") {
+			if r.Err != nil {
+				t.Fatal(r.Err)
+			}
+			if want := r.WorkflowID == "status"; (r.Override != "") != want {
+				t.Fatalf("%s %s/%s: override %q", tool, r.Kind, r.WorkflowID, r.Override)
+			}
+		}
+	}
+	cursor, _ := os.ReadFile(filepath.Join(root, ".cursor", "skills", "pacto-status", "SKILL.md"))
+	claude, _ := os.ReadFile(filepath.Join(root, ".claude", "skills", "pacto-status", "SKILL.md"))
+	for _, want := range []string{"Before every release.", "- CI is green on main", "- Release branch missing."} {
+		if !strings.Contains(string(claude), want) || !strings.Contains(string(cursor), want) {
+			t.Fatalf("expected %q in both skills", want)
+		}
+	}
+	if !strings.Contains(string(cursor), "Cursor users also run it") || strings.Contains(string(claude), "Cursor users") {
+		t.Fatal("tool override must only apply to cursor")
+	}
+	if strings.Contains(string(claude), "Root resolution failure") {
+		t.Fatal("failureModes list should have been replaced")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "status.yaml"), []byte("failureModes:\n  apend: [x]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if r := GenerateForTool(root, "cursor", false, ""); len(r) != 1 || r[0].Err == nil || !strings.Contains(r[0].Err.Error(), "apend") {
+		t.Fatalf("expected invalid override error, got %#v", r)
+	}
+}
+
+func TestCheckToolIgnoresLiveContext(t *testing.T) {
+	root := t.TempDir()
+	GenerateForTool(root, "cursor", false, "- auth: Finish 1.2")
+	for _, c := range CheckTool(root, "cursor", "- auth: Finish 1.3") {
+		if c.Err != nil || c.Status != DriftOK {
+			t.Fatalf("expected a changed live context not to drift, got %s %s %v", c.Path, c.Status, c.Err)
+		}
+	}
+	if c := CheckTool(root, "cursor", ""); !c[0].Drifted() {
+		t.Fatalf("expected a skill losing its live section to drift, got %s", c[0].Status)
+	}
+}
+
+func TestRenderLiveContextListsCurrentPlansWithinLimit(t *testing.T) {
+	pct := 40
+	rep := model.StatusReport{Plans: []model.PlanStatus{
+		{StateFolder: "done", Slug: "old"},
+		{StateFolder: "current", Slug: "auth", ProgressPct: &pct, Verification: "partial", NextActions: []string{"Finish 1.2"}, Blockers: []string{"Waiting on API keys"},
+			Claims: []model.ClaimResult{{SourceText: "src/auth.go", Result: "unverified"}, {SourceText: "README.md", Result: "verified"}}},
+	}}
+	for i := 0; i < 50; i++ {
+		rep.Plans = append(rep.Plans, model.PlanStatus{StateFolder: "current", Slug: fmt.Sprintf("plan-%02d", i), NextActions: []string{strings.Repeat("x", 100)}})
+	}
+
+	got := RenderLiveContext(rep, "current", 1000)
+	if len(got) > 1000 {
+		t.Fatalf("live context is %d bytes, want <= 1000", len(got))
+	}
+	for _, want := range []string{"### current/auth", "- Status: partial, 40%", "- Next: Finish 1.2", "- Blocker: Waiting on API keys", "- Unverified: src/auth.go", "more plan(s)"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "old") || strings.Contains(got, "README.md") {
+		t.Fatalf("only current plans and unverified claims belong in:\n%s", got)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\nTWO\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	want := `--- a
++++ b
+@@ -1,5 +1,5 @@
+ 1
+-2
++TWO
+ 3
+ 4
+ 5
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+`
+	if got := UnifiedDiff("a", "b", a, b); got != want {
+		t.Fatalf("unexpected diff:\n%s", got)
+	}
+	if UnifiedDiff("a", "b", a, a) != "" {
+		t.Fatal("expected no diff for equal input")
+	}
+}
+
 func TestRenderTemplatesIncludeContractSections(t *testing.T) {
 	for _, wf := range Workflows() {
 		skill := RenderSkill("codex", wf)
@@ -126,7 +457,7 @@ func TestRenderTemplatesIncludeContractSections(t *testing.T) {
 
 func TestGenerateForToolWritesContractAndExecCommand(t *testing.T) {
 	root := t.TempDir()
-	results := GenerateForTool(root, "opencode", false)
+	results := GenerateForTool(root, "opencode", false, "")
 	if len(results) == 0 {
 		t.Fatal("expected generation results")
 	}
@@ -170,7 +501,7 @@ func TestGenerateForToolIncludesPluginGuardrailsWhenActive(t *testing.T) {
 	root := t.TempDir()
 	writeIntegrationPlugin(t, root, "acme", true)
 
-	results := GenerateForTool(root, "opencode", false)
+	results := GenerateForTool(root, "opencode", false, "")
 	for _, r := range results {
 		if r.Err != nil {
 			t.Fatalf("unexpected generation error for %s/%s: %v", r.Kind, r.WorkflowID, r.Err)
@@ -197,7 +528,7 @@ func TestGenerateForToolSkipsPluginGuardrailsWhenDisabled(t *testing.T) {
 	root := t.TempDir()
 	writeIntegrationPlugin(t, root, "acme", false)
 
-	results := GenerateForTool(root, "opencode", false)
+	results := GenerateForTool(root, "opencode", false, "")
 	for _, r := range results {
 		if r.Err != nil {
 			t.Fatalf("unexpected generation error for %s/%s: %v", r.Kind, r.WorkflowID, r.Err)
@@ -0,0 +1,175 @@
+package integrations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pacto/internal/model"
+	"pacto/internal/yamlutil"
+)
+
+const (
+	LiveContextStart = "<!-- pacto:live-context:start -->"
+	LiveContextEnd   = "<!-- pacto:live-context:end -->"
+)
+
+// DefaultLiveContextMaxBytes bounds the live section of each skill.
+const DefaultLiveContextMaxBytes = 4000
+
+// maxLiveItems bounds next actions, blockers and claims listed per plan.
+const maxLiveItems = 3
+
+// LiveContextConfig is integrations.liveContext in .pacto/config.yaml.
+type LiveContextConfig struct {
+	Enabled  bool
+	MaxBytes int
+}
+
+func ReadLiveContextConfig(projectRoot string) (LiveContextConfig, error) {
+	cfg := LiveContextConfig{MaxBytes: DefaultLiveContextMaxBytes}
+	m, err := yamlutil.ReadFileMap(filepath.Join(projectRoot, ".pacto", "config.yaml"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	live := yamlutil.GetMap(yamlutil.GetMap(m, "integrations"), "liveContext")
+	if live == nil {
+		return cfg, nil
+	}
+	cfg.Enabled, _ = live["enabled"].(bool)
+	if n, ok := live["maxBytes"].(int); ok && n > 0 {
+		cfg.MaxBytes = n
+	}
+	return cfg, nil
+}
+
+// RenderLiveContext summarizes the plans of rep in state (next actions, open
+// blockers and unverified claims) as Markdown of at most maxBytes. Plans that
+// do not fit are counted in a trailing line.
+func RenderLiveContext(rep model.StatusReport, state string, maxBytes int) string {
+	if maxBytes <= 0 {
+		maxBytes = DefaultLiveContextMaxBytes
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plans in `%s` when artifacts were last refreshed. Run `pacto status` for the authoritative state.\n", state)
+	plans := make([]model.PlanStatus, 0)
+	for _, p := range rep.Plans {
+		if p.StateFolder == state {
+			plans = append(plans, p)
+		}
+	}
+	if len(plans) == 0 {
+		fmt.Fprintf(&b, "\n- No plans in `%s`.\n", state)
+		return b.String()
+	}
+	for i, p := range plans {
+		entry := renderLivePlan(p)
+		more := ""
+		if i < len(plans)-1 {
+			more = fmt.Sprintf("\n- ... %d more plan(s); run `pacto status`.\n", len(plans)-i-1)
+		}
+		if b.Len()+len(entry)+len(more) > maxBytes {
+			fmt.Fprintf(&b, "\n- ... %d more plan(s); run `pacto status`.\n", len(plans)-i)
+			break
+		}
+		b.WriteString(entry)
+	}
+	return b.String()
+}
+
+func renderLivePlan(p model.PlanStatus) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n### %s/%s\n\n", p.StateFolder, p.Slug)
+	status := p.Verification
+	if p.ProgressPct != nil {
+		status += fmt.Sprintf(", %d%%", *p.ProgressPct)
+	}
+	fmt.Fprintf(&b, "- Status: %s (%d pending, %d blocked)\n", status, p.PendingTasks, p.BlockedTasks)
+	for _, a := range firstN(p.NextActions, maxLiveItems) {
+		fmt.Fprintf(&b, "- Next: %s\n", oneLine(a))
+	}
+	for _, bl := range firstN(p.Blockers, maxLiveItems) {
+		fmt.Fprintf(&b, "- Blocker: %s\n", oneLine(bl))
+	}
+	unverified := make([]string, 0)
+	for _, c := range p.Claims {
+		if c.Result == "unverified" {
+			unverified = append(unverified, c.SourceText)
+		}
+	}
+	for _, c := range firstN(unverified, maxLiveItems) {
+		fmt.Fprintf(&b, "- Unverified: %s\n", oneLine(c))
+	}
+	return b.String()
+}
+
+func firstN(v []string, n int) []string {
+	if len(v) > n {
+		return v[:n]
+	}
+	return v
+}
+
+func oneLine(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > 200 {
+		return s[:200] + "..."
+	}
+	return s
+}
+
+// withoutLiveContext blanks the live section of s, leaving its markers, so
+// artifacts compare equal whatever plan status they were rendered with.
+func withoutLiveContext(s string) string {
+	start := strings.Index(s, LiveContextStart)
+	if start < 0 {
+		return s
+	}
+	start += len(LiveContextStart)
+	end := strings.Index(s[start:], LiveContextEnd)
+	if end < 0 {
+		return s
+	}
+	return s[:start] + "\n" + s[start+end:]
+}
+
+// appendLiveContext adds the live section at the end of a skill body, so it
+// stays inside the managed block.
+func appendLiveContext(body, live string) string {
+	if strings.TrimSpace(live) == "" {
+		return body
+	}
+	return strings.TrimRight(body, "\n") + "\n\n## Live Plan Context\n\n" + LiveContextStart + "\n" + strings.TrimSpace(live) + "\n" + LiveContextEnd + "\n"
+}
+
+// RefreshLiveContext rewrites the skills of every tool that already has a
+// managed skill file, leaving other tools and unmanaged files untouched.
+func RefreshLiveContext(projectRoot, live string) []ArtifactResult {
+	tools, _ := ProjectTools(projectRoot)
+	results := make([]ArtifactResult, 0)
+	for _, toolID := range tools {
+		planned, err := planArtifacts(projectRoot, toolID, live)
+		if err != nil {
+			continue
+		}
+		for _, a := range planned {
+			if a.err != nil || a.kind != "skill" {
+				continue
+			}
+			b, err := os.ReadFile(a.path)
+			if err != nil {
+				continue
+			}
+			if _, ok := findManaged(string(b)); !ok {
+				continue
+			}
+			wr, werr := WriteManagedFramed(a.path, a.prefix, a.body, a.suffix, false)
+			results = append(results, ArtifactResult{Tool: toolID, Kind: a.kind, WorkflowID: a.workflowID, Path: a.path, Outcome: wr.Outcome, Reason: wr.Reason, Override: a.override, Err: werr})
+		}
+	}
+	return results
+}
@@ -1,22 +1,77 @@
 package integrations
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
+// ManagedStart opens a managed block. The marker line also records the hash
+// of the block as written, e.g. "<!-- pacto:managed:start sha256=... -->",
+// so hand edits can be told apart from outdated renders.
 const (
-	ManagedStart = "<!-- pacto:managed:start -->"
+	ManagedStart = "<!-- pacto:managed:start"
 	ManagedEnd   = "<!-- pacto:managed:end -->"
 )
 
 func WrapManaged(body string) string {
-	return ManagedStart + "\n" + strings.TrimSpace(body) + "\n" + ManagedEnd + "\n"
+	body = strings.TrimSpace(body)
+	return ManagedStart + " sha256=" + managedHash(body) + " -->\n" + body + "\n" + ManagedEnd + "\n"
+}
+
+func managedHash(body string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(body)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// managedBlock locates a managed block in a file; start and end span both
+// markers. hash is empty for blocks written before hashes were recorded.
+type managedBlock struct {
+	start int
+	end   int
+	body  string
+	hash  string
+}
+
+func findManaged(s string) (managedBlock, bool) {
+	start := strings.Index(s, ManagedStart)
+	if start < 0 {
+		return managedBlock{}, false
+	}
+	open := strings.Index(s[start:], "-->")
+	if open < 0 {
+		return managedBlock{}, false
+	}
+	bodyStart := start + open + len("-->")
+	end := strings.Index(s[bodyStart:], ManagedEnd)
+	if end < 0 {
+		return managedBlock{}, false
+	}
+	b := managedBlock{
+		start: start,
+		end:   bodyStart + end + len(ManagedEnd),
+		body:  strings.TrimSpace(s[bodyStart : bodyStart+end]),
+	}
+	for _, attr := range strings.Fields(s[start+len(ManagedStart) : start+open]) {
+		if v, ok := strings.CutPrefix(attr, "sha256="); ok {
+			b.hash = v
+		}
+	}
+	return b, true
 }
 
 func WriteManaged(path, body string, force bool) (WriteResult, error) {
+	return WriteManagedFramed(path, "", body, "", force)
+}
+
+// WriteManagedFramed is WriteManaged for files whose managed block sits
+// between a prefix and suffix, such as front matter. The frame is written
+// when the file is created or overwritten; updates only touch the block.
+func WriteManagedFramed(path, prefix, body, suffix string, force bool) (WriteResult, error) {
 	wrapped := WrapManaged(body)
+	full := prefix + wrapped + suffix
 	if err := os.MkdirAll(filepath.Dir(path), 0o775); err != nil {
 		return WriteResult{}, err
 	}
@@ -24,7 +79,7 @@ func WriteManaged(path, body string, force bool) (WriteResult, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			if err := os.WriteFile(path, []byte(wrapped), 0o664); err != nil {
+			if err := os.WriteFile(path, []byte(full), 0o664); err != nil {
 				return WriteResult{}, err
 			}
 			return WriteResult{Outcome: OutcomeCreated}, nil
@@ -33,11 +88,8 @@ func WriteManaged(path, body string, force bool) (WriteResult, error) {
 	}
 
 	s := string(b)
-	start := strings.Index(s, ManagedStart)
-	end := strings.Index(s, ManagedEnd)
-	if start >= 0 && end > start {
-		end += len(ManagedEnd)
-		next := s[:start] + strings.TrimRight(wrapped, "\n") + s[end:]
+	if mb, ok := findManaged(s); ok {
+		next := s[:mb.start] + strings.TrimRight(wrapped, "\n") + s[mb.end:]
 		if next == s {
 			return WriteResult{Outcome: OutcomeSkipped, Reason: "unchanged"}, nil
 		}
@@ -50,7 +102,7 @@ func WriteManaged(path, body string, force bool) (WriteResult, error) {
 	if !force {
 		return WriteResult{Outcome: OutcomeSkipped, Reason: "unmanaged_exists"}, nil
 	}
-	if err := os.WriteFile(path, []byte(wrapped), 0o664); err != nil {
+	if err := os.WriteFile(path, []byte(full), 0o664); err != nil {
 		return WriteResult{}, err
 	}
 	return WriteResult{Outcome: OutcomeUpdated, Reason: "force_overwrite"}, nil
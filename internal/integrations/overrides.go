@@ -0,0 +1,206 @@
+package integrations
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowOverrideDir holds per-workflow overrides, one <workflow>.yaml each.
+const WorkflowOverrideDir = ".pacto/workflows"
+
+// WorkflowOverride customizes a workflow before its skill and command are
+// rendered. Fields at the top level apply to every tool; entries under tools
+// apply on top of them for that tool only:
+//
+//	whenToUse: Before any release branch is cut.
+//	validationChecklist:
+//	  append: ["CI is green on main"]
+//	tools:
+//	  cursor:
+//	    failureModes: ["Cursor rules are stale: run pacto install"]
+type WorkflowOverride struct {
+	OverrideFields `yaml:",inline"`
+	Tools          map[string]OverrideFields `yaml:"tools"`
+	File           string                    `yaml:"-"`
+}
+
+type OverrideFields struct {
+	Summary             *TextOverride `yaml:"summary"`
+	WhenToUse           *TextOverride `yaml:"whenToUse"`
+	FallbackAction      *TextOverride `yaml:"fallbackAction"`
+	RequiredInputs      *ListOverride `yaml:"requiredInputs"`
+	OptionalInputs      *ListOverride `yaml:"optionalInputs"`
+	OutputContract      *ListOverride `yaml:"outputContract"`
+	ValidationChecklist *ListOverride `yaml:"validationChecklist"`
+	FailureModes        *ListOverride `yaml:"failureModes"`
+}
+
+// TextOverride is a string that replaces the field, or a mapping with
+// replace and/or append (added as a new paragraph).
+type TextOverride struct {
+	Replace *string `yaml:"replace"`
+	Append  string  `yaml:"append"`
+}
+
+func (o *TextOverride) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		v := n.Value
+		o.Replace = &v
+		return nil
+	}
+	if err := checkOverrideKeys(n); err != nil {
+		return err
+	}
+	type plain TextOverride
+	return n.Decode((*plain)(o))
+}
+
+func (o *TextOverride) apply(v string) string {
+	if o == nil {
+		return v
+	}
+	if o.Replace != nil {
+		v = strings.TrimSpace(*o.Replace)
+	}
+	if a := strings.TrimSpace(o.Append); a != "" {
+		if v == "" {
+			return a
+		}
+		v += "\n\n" + a
+	}
+	return v
+}
+
+// ListOverride is a list that replaces the field, or a mapping with
+// replace and/or append.
+type ListOverride struct {
+	Replace []string `yaml:"replace"`
+	Append  []string `yaml:"append"`
+	set     bool
+}
+
+func (o *ListOverride) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.SequenceNode {
+		o.set = true
+		return n.Decode(&o.Replace)
+	}
+	if err := checkOverrideKeys(n); err != nil {
+		return err
+	}
+	type plain struct {
+		Replace *[]string `yaml:"replace"`
+		Append  []string  `yaml:"append"`
+	}
+	var p plain
+	if err := n.Decode(&p); err != nil {
+		return err
+	}
+	if p.Replace != nil {
+		o.Replace, o.set = *p.Replace, true
+	}
+	o.Append = p.Append
+	return nil
+}
+
+// checkOverrideKeys rejects unknown keys, which node.Decode would ignore.
+func checkOverrideKeys(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i < len(n.Content); i += 2 {
+		if k := n.Content[i].Value; k != "replace" && k != "append" {
+			return fmt.Errorf("line %d: unknown key %q (use replace or append)", n.Content[i].Line, k)
+		}
+	}
+	return nil
+}
+
+func (o *ListOverride) apply(v []string) []string {
+	if o == nil {
+		return v
+	}
+	out := append([]string{}, v...)
+	if o.set {
+		out = append([]string{}, o.Replace...)
+	}
+	return append(out, o.Append...)
+}
+
+func (f OverrideFields) apply(wf WorkflowSpec) WorkflowSpec {
+	wf.Summary = f.Summary.apply(wf.Summary)
+	wf.WhenToUse = f.WhenToUse.apply(wf.WhenToUse)
+	wf.FallbackAction = f.FallbackAction.apply(wf.FallbackAction)
+	wf.RequiredInputs = f.RequiredInputs.apply(wf.RequiredInputs)
+	wf.OptionalInputs = f.OptionalInputs.apply(wf.OptionalInputs)
+	wf.OutputContract = f.OutputContract.apply(wf.OutputContract)
+	wf.ValidationChecklist = f.ValidationChecklist.apply(wf.ValidationChecklist)
+	wf.FailureModes = f.FailureModes.apply(wf.FailureModes)
+	return wf
+}
+
+// Apply merges the override into wf for toolID.
+func (o WorkflowOverride) Apply(wf WorkflowSpec, toolID string) WorkflowSpec {
+	wf = o.OverrideFields.apply(wf)
+	if t, ok := o.Tools[toolID]; ok {
+		wf = t.apply(wf)
+	}
+	return wf
+}
+
+// LoadWorkflowOverrides reads .pacto/workflows/*.yaml keyed by workflow id.
+// Every invalid file is reported in the joined error.
+func LoadWorkflowOverrides(projectRoot string) (map[string]WorkflowOverride, error) {
+	dir := filepath.Join(projectRoot, filepath.FromSlash(WorkflowOverrideDir))
+	files, _ := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	more, _ := filepath.Glob(filepath.Join(dir, "*.yml"))
+	files = append(files, more...)
+	sort.Strings(files)
+
+	known := map[string]bool{}
+	for _, wf := range Workflows() {
+		known[wf.WorkflowID] = true
+	}
+	tools, _ := ProjectTools(projectRoot)
+
+	out := map[string]WorkflowOverride{}
+	var errs []error
+	for _, f := range files {
+		id := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(f), ".yaml"), ".yml")
+		if !known[id] {
+			errs = append(errs, fmt.Errorf("%s: unknown workflow %q", f, id))
+			continue
+		}
+		if _, dup := out[id]; dup {
+			errs = append(errs, fmt.Errorf("%s: workflow %q is already overridden", f, id))
+			continue
+		}
+		b, err := os.ReadFile(f)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var o WorkflowOverride
+		dec := yaml.NewDecoder(bytes.NewReader(b))
+		dec.KnownFields(true)
+		if err := dec.Decode(&o); err != nil && !errors.Is(err, io.EOF) {
+			errs = append(errs, fmt.Errorf("%s: %w", f, err))
+			continue
+		}
+		for tool := range o.Tools {
+			if !contains(tools, tool) {
+				errs = append(errs, fmt.Errorf("%s: tools.%s: unknown tool (allowed: %s)", f, tool, strings.Join(tools, ",")))
+			}
+		}
+		o.File = f
+		out[id] = o
+	}
+	return out, errors.Join(errs...)
+}
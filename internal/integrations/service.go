@@ -1,40 +1,93 @@
 package integrations
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"pacto/internal/plugins"
 )
 
-func GenerateForTool(projectRoot, toolID string, force bool) []ArtifactResult {
-	results := make([]ArtifactResult, 0)
-	adapter, ok := GetAdapter(toolID)
+// GenerateForTool writes the skills and commands of toolID. A non-empty live
+// context is appended to every skill as its Live Plan Context section.
+func GenerateForTool(projectRoot, toolID string, force bool, live string) []ArtifactResult {
+	planned, err := planArtifacts(projectRoot, toolID, live)
+	if err != nil {
+		return []ArtifactResult{{Tool: toolID, Err: err}}
+	}
+	results := make([]ArtifactResult, 0, len(planned))
+	for _, a := range planned {
+		r := ArtifactResult{Tool: toolID, Kind: a.kind, WorkflowID: a.workflowID, Path: a.path, Override: a.override, Err: a.err}
+		if a.err == nil {
+			wr, werr := WriteManagedFramed(a.path, a.prefix, a.body, a.suffix, force)
+			r.Outcome, r.Reason, r.Err = wr.Outcome, wr.Reason, werr
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// plannedArtifact is one rendered skill or command and where it belongs.
+type plannedArtifact struct {
+	kind       string
+	workflowID string
+	path       string
+	prefix     string
+	body       string
+	suffix     string
+	override   string
+	err        error
+}
+
+// planArtifacts renders every artifact of toolID. Kinds the tool does not
+// support are left out; path errors are kept per artifact.
+func planArtifacts(projectRoot, toolID, live string) ([]plannedArtifact, error) {
+	adapter, ok := ProjectAdapter(projectRoot, toolID)
 	if !ok {
-		return []ArtifactResult{{Tool: toolID, Err: errUnsupportedTool(toolID)}}
+		return nil, errUnsupportedTool(toolID)
+	}
+	overrides, err := LoadWorkflowOverrides(projectRoot)
+	if err != nil {
+		return nil, err
 	}
 	activePlugins, _ := plugins.LoadActive(projectRoot)
 
+	out := make([]plannedArtifact, 0)
 	for _, wf := range Workflows() {
+		override := ""
+		if o, ok := overrides[wf.WorkflowID]; ok {
+			wf = o.Apply(wf, toolID)
+			override = o.File
+		}
 		pluginSections := collectPluginSections(activePlugins, toolID, wf.WorkflowID)
 		skillPath, err := adapter.SkillFilePath(projectRoot, wf.WorkflowID)
-		if err != nil {
-			results = append(results, ArtifactResult{Tool: toolID, Kind: "skill", WorkflowID: wf.WorkflowID, Err: err})
-		} else {
-			wr, werr := WriteManaged(skillPath, RenderSkill(toolID, wf, pluginSections...), force)
-			results = append(results, ArtifactResult{Tool: toolID, Kind: "skill", WorkflowID: wf.WorkflowID, Path: skillPath, Outcome: wr.Outcome, Reason: wr.Reason, Err: werr})
+		if !errors.Is(err, ErrUnsupportedArtifact) {
+			a := plannedArtifact{kind: "skill", workflowID: wf.WorkflowID, path: skillPath, override: override, err: err}
+			if err == nil {
+				a.prefix, a.suffix = artifactFrame(adapter, "skill", wf)
+				a.body = appendLiveContext(RenderSkill(toolID, wf, pluginSections...), live)
+			}
+			out = append(out, a)
 		}
 
 		commandPath, err := adapter.CommandFilePath(projectRoot, wf.CommandID)
-		if err != nil {
-			results = append(results, ArtifactResult{Tool: toolID, Kind: "command", WorkflowID: wf.WorkflowID, Err: err})
-			continue
+		if !errors.Is(err, ErrUnsupportedArtifact) {
+			a := plannedArtifact{kind: "command", workflowID: wf.WorkflowID, path: commandPath, override: override, err: err}
+			if err == nil {
+				a.prefix, a.suffix = artifactFrame(adapter, "command", wf)
+				a.body = RenderCommand(toolID, wf, pluginSections...)
+			}
+			out = append(out, a)
 		}
-		wr, werr := WriteManaged(commandPath, RenderCommand(toolID, wf, pluginSections...), force)
-		results = append(results, ArtifactResult{Tool: toolID, Kind: "command", WorkflowID: wf.WorkflowID, Path: commandPath, Outcome: wr.Outcome, Reason: wr.Reason, Err: werr})
 	}
+	return out, nil
+}
 
-	return results
+func artifactFrame(a Adapter, kind string, wf WorkflowSpec) (string, string) {
+	if f, ok := a.(ArtifactFramer); ok {
+		return f.ArtifactFrame(kind, wf)
+	}
+	return "", ""
 }
 
 func errUnsupportedTool(toolID string) error {
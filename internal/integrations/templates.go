@@ -50,7 +50,7 @@ func Workflows() []WorkflowSpec {
 			Command:    "pacto new to-implement my-plan-slug",
 			WhenToUse:  "Use when a new plan slice must be created in one of the canonical states.",
 			RequiredInputs: []string{
-				"`<state>` in `current|to-implement|done|outdated`.",
+				"`<state>` in `current|to-implement|done|outdated`, or a state declared under `states` in `.pacto/config.yaml`.",
 				"`<slug>` matching `[a-z0-9][a-z0-9-]*`.",
 			},
 			OptionalInputs: []string{
@@ -219,9 +219,9 @@ func Workflows() []WorkflowSpec {
 			Command:    "pacto move <from-state> <slug> <to-state> [--root <path>] [--reason <text>] [--force]",
 			WhenToUse:  "Use for explicit state transitions such as `to-implement -> current` or `current -> done`.",
 			RequiredInputs: []string{
-				"`<from-state>` in `current|to-implement|done|outdated`.",
+				"`<from-state>` and `<to-state>` in `current|to-implement|done|outdated`, or states declared under `states` in `.pacto/config.yaml`.",
 				"`<slug>` matching `[a-z0-9][a-z0-9-]*`.",
-				"`<to-state>` in `current|to-implement|done|outdated`.",
+				"The transition must be allowed by the target state's `from` list when one is configured.",
 			},
 			OptionalInputs: []string{
 				"`--root <path>` to target a specific project root.",
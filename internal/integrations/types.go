@@ -1,6 +1,9 @@
 package integrations
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type Adapter interface {
 	ToolID() string
@@ -44,27 +47,41 @@ type ArtifactResult struct {
 	Path       string
 	Outcome    WriteOutcome
 	Reason     string
-	Err        error
+	// Override is the .pacto/workflows file merged into this artifact.
+	Override string
+	Err      error
 }
 
 func SupportedTools() []string {
-	return []string{"codex", "cursor", "claude", "opencode"}
+	return []string{"codex", "cursor", "claude", "opencode", "windsurf", "cline", "roo", "copilot", "aider", "gemini"}
 }
 
+// ParseToolsArg parses --tools against the built-in tools.
 func ParseToolsArg(raw string) ([]string, error) {
+	return parseTools(raw, SupportedTools())
+}
+
+// ParseProjectToolsArg parses --tools against the built-in tools and the
+// adapters declared under .pacto/adapters of projectRoot.
+func ParseProjectToolsArg(projectRoot, raw string) ([]string, error) {
+	tools, _ := ProjectTools(projectRoot)
+	return parseTools(raw, tools)
+}
+
+func parseTools(raw string, supported []string) ([]string, error) {
 	v := normalize(raw)
 	if v == "" {
 		return nil, fmt.Errorf("--tools expects a value: all, none, or comma-separated tool IDs")
 	}
 	if v == "all" {
-		return SupportedTools(), nil
+		return supported, nil
 	}
 	if v == "none" {
 		return []string{}, nil
 	}
 
 	valid := map[string]bool{}
-	for _, t := range SupportedTools() {
+	for _, t := range supported {
 		valid[t] = true
 	}
 
@@ -75,7 +92,7 @@ func ParseToolsArg(raw string) ([]string, error) {
 			return nil, fmt.Errorf("cannot combine %q with specific tool IDs", tok)
 		}
 		if !valid[tok] {
-			return nil, fmt.Errorf("invalid tool %q (allowed: %s)", tok, joinSupported())
+			return nil, fmt.Errorf("invalid tool %q (allowed: %s)", tok, strings.Join(supported, ","))
 		}
 		if !seen[tok] {
 			out = append(out, tok)
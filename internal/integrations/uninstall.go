@@ -0,0 +1,107 @@
+package integrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Outcomes of RemoveForTool.
+const (
+	OutcomeRemoved  WriteOutcome = "removed"
+	OutcomeStripped WriteOutcome = "stripped"
+)
+
+// RemoveForTool undoes GenerateForTool: entirely managed files are deleted,
+// files with user content lose only the managed block, and directories left
+// empty inside projectRoot are pruned. With dryRun nothing is written.
+func RemoveForTool(projectRoot, toolID string, dryRun bool) []ArtifactResult {
+	planned, err := planArtifacts(projectRoot, toolID, "")
+	if err != nil {
+		return []ArtifactResult{{Tool: toolID, Err: err}}
+	}
+	results := make([]ArtifactResult, 0, len(planned))
+	for _, a := range planned {
+		r := ArtifactResult{Tool: toolID, Kind: a.kind, WorkflowID: a.workflowID, Path: a.path, Err: a.err}
+		if a.err == nil {
+			wr, werr := RemoveMarkedBlock(a.path, ManagedStart, ManagedEnd, a.prefix, a.suffix, dryRun)
+			r.Outcome, r.Reason, r.Err = wr.Outcome, wr.Reason, werr
+			if werr == nil && wr.Outcome == OutcomeRemoved && !dryRun {
+				pruneEmptyDirs(filepath.Dir(a.path), projectRoot)
+			}
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// RemoveMarkedBlock strips the block from startMarker to endMarker out of
+// path, along with the prefix and suffix (the front matter written around
+// the block) when they still surround it. When nothing but whitespace
+// remains, the file is deleted instead.
+func RemoveMarkedBlock(path, startMarker, endMarker, prefix, suffix string, dryRun bool) (WriteResult, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return WriteResult{Outcome: OutcomeSkipped, Reason: "missing"}, nil
+		}
+		return WriteResult{}, err
+	}
+	s := string(b)
+	start := strings.Index(s, startMarker)
+	end := -1
+	if start >= 0 {
+		end = strings.Index(s[start:], endMarker)
+	}
+	if end < 0 {
+		return WriteResult{Outcome: OutcomeSkipped, Reason: "unmanaged_exists"}, nil
+	}
+	end += start + len(endMarker)
+
+	before := strings.TrimRight(s[:start], " \t\n")
+	after := strings.TrimLeft(s[end:], " \t\n")
+	if p := strings.TrimSpace(prefix); p != "" && strings.HasSuffix(before, p) {
+		before = strings.TrimRight(strings.TrimSuffix(before, p), " \t\n")
+	}
+	if sfx := strings.TrimSpace(suffix); sfx != "" && strings.HasPrefix(after, sfx) {
+		after = strings.TrimLeft(strings.TrimPrefix(after, sfx), " \t\n")
+	}
+	if strings.TrimSpace(before+after) == "" {
+		if !dryRun {
+			if err := os.Remove(path); err != nil {
+				return WriteResult{}, err
+			}
+		}
+		return WriteResult{Outcome: OutcomeRemoved}, nil
+	}
+
+	next := after
+	if before != "" && after != "" {
+		next = before + "\n\n" + after
+	} else if before != "" {
+		next = before + "\n"
+	}
+	if !dryRun {
+		if err := os.WriteFile(path, []byte(next), 0o664); err != nil {
+			return WriteResult{}, err
+		}
+	}
+	return WriteResult{Outcome: OutcomeStripped}, nil
+}
+
+// pruneEmptyDirs removes dir and its parents while they are empty, stopping
+// at root. Directories outside root are left alone.
+func pruneEmptyDirs(dir, root string) {
+	root = filepath.Clean(root)
+	for {
+		dir = filepath.Clean(dir)
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return
+		}
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
@@ -0,0 +1,428 @@
+package journal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"pacto/internal/fsutil"
+)
+
+const (
+	DirName    = "journal"
+	MaxEntries = 200
+
+	OpWrite  = "write"
+	OpRename = "rename"
+	OpRemove = "remove"
+	OpMkdir  = "mkdir"
+)
+
+var ErrDrift = errors.New("workspace changed since journal entry")
+
+// Op is a single reversible filesystem change. Paths are slash-separated and
+// relative to the journal base (the directory that contains .pacto).
+type Op struct {
+	Kind    string `json:"kind"`
+	Path    string `json:"path"`
+	To      string `json:"to,omitempty"`
+	Existed bool   `json:"existed,omitempty"`
+	Before  string `json:"before,omitempty"`
+	Mode    uint32 `json:"mode,omitempty"`
+	After   string `json:"after_sha256,omitempty"`
+}
+
+type Entry struct {
+	ID       string     `json:"id"`
+	Time     time.Time  `json:"time"`
+	Command  string     `json:"command"`
+	Args     []string   `json:"args,omitempty"`
+	Summary  string     `json:"summary,omitempty"`
+	Ops      []Op       `json:"ops"`
+	UndoneAt *time.Time `json:"undone_at,omitempty"`
+}
+
+func (e Entry) Undone() bool {
+	return e.UndoneAt != nil
+}
+
+// Recorder performs filesystem mutations and captures before-images so the
+// whole command can be reversed later with Undo.
+type Recorder struct {
+	stateDir string
+	base     string
+	entry    Entry
+}
+
+func NewRecorder(stateDir, command string, args []string) *Recorder {
+	return &Recorder{
+		stateDir: stateDir,
+		base:     filepath.Dir(stateDir),
+		entry: Entry{
+			Command: command,
+			Args:    append([]string{}, args...),
+		},
+	}
+}
+
+func (r *Recorder) rel(path string) (string, error) {
+	rel, err := filepath.Rel(r.base, path)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path outside workspace: %s", path)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// WriteChecked writes data over the file described by snap, failing with
+// fsutil.ErrConflict if it changed since it was read. A zero-value snapshot
+// with only Path set asserts that the file does not exist yet.
+func (r *Recorder) WriteChecked(snap fsutil.Snapshot, data []byte, perm os.FileMode) error {
+	rel, err := r.rel(snap.Path)
+	if err != nil {
+		return err
+	}
+	op := Op{Kind: OpWrite, Path: rel, Existed: snap.Exists}
+	if snap.Exists {
+		before, err := os.ReadFile(snap.Path)
+		if err != nil {
+			return err
+		}
+		op.Before = string(before)
+		if info, err := os.Stat(snap.Path); err == nil {
+			op.Mode = uint32(info.Mode().Perm())
+		}
+	}
+	if err := fsutil.WriteFileChecked(snap, data, perm); err != nil {
+		return err
+	}
+	op.After = digest(data)
+	r.entry.Ops = append(r.entry.Ops, op)
+	return nil
+}
+
+func (r *Recorder) Mkdir(dir string, perm os.FileMode) error {
+	rel, err := r.rel(dir)
+	if err != nil {
+		return err
+	}
+	if err := os.Mkdir(dir, perm); err != nil {
+		return err
+	}
+	r.entry.Ops = append(r.entry.Ops, Op{Kind: OpMkdir, Path: rel})
+	return nil
+}
+
+// MkdirAll creates dir and any missing parents, recording each created level.
+func (r *Recorder) MkdirAll(dir string, perm os.FileMode) error {
+	missing := make([]string, 0, 2)
+	for cur := filepath.Clean(dir); ; cur = filepath.Dir(cur) {
+		if _, err := os.Stat(cur); err == nil {
+			break
+		}
+		missing = append(missing, cur)
+		if filepath.Dir(cur) == cur {
+			break
+		}
+	}
+	for i := len(missing) - 1; i >= 0; i-- {
+		if err := r.Mkdir(missing[i], perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Recorder) Rename(from, to string) error {
+	relFrom, err := r.rel(from)
+	if err != nil {
+		return err
+	}
+	relTo, err := r.rel(to)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(from, to); err != nil {
+		return err
+	}
+	r.entry.Ops = append(r.entry.Ops, Op{Kind: OpRename, Path: relFrom, To: relTo})
+	return nil
+}
+
+// RemoveAll deletes a file tree after recording every file so it can be
+// recreated on undo.
+func (r *Recorder) RemoveAll(path string) error {
+	ops := make([]Op, 0, 8)
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := r.rel(p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			ops = append(ops, Op{Kind: OpRemove, Path: rel + "/", Mode: uint32(info.Mode().Perm())})
+			return nil
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, Op{Kind: OpRemove, Path: rel, Existed: true, Before: string(b), Mode: uint32(info.Mode().Perm())})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+	r.entry.Ops = append(r.entry.Ops, ops...)
+	return nil
+}
+
+// Touched returns absolute paths of every file or directory the recorder changed.
+func (r *Recorder) Touched() []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(r.entry.Ops))
+	add := func(rel string) {
+		if rel == "" {
+			return
+		}
+		p := filepath.Join(r.base, filepath.FromSlash(strings.TrimSuffix(rel, "/")))
+		if seen[p] {
+			return
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	for _, op := range r.entry.Ops {
+		add(op.Path)
+		add(op.To)
+	}
+	return out
+}
+
+// Commit persists the entry. Recorders without operations write nothing.
+func (r *Recorder) Commit(summary string) (Entry, error) {
+	if len(r.entry.Ops) == 0 {
+		return r.entry, nil
+	}
+	now := time.Now().UTC()
+	r.entry.Time = now
+	r.entry.ID = now.Format("20060102T150405.000000000Z")
+	r.entry.Summary = summary
+	dir := filepath.Join(r.stateDir, DirName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return r.entry, err
+	}
+	if err := writeEntry(dir, r.entry); err != nil {
+		return r.entry, err
+	}
+	return r.entry, prune(dir, MaxEntries)
+}
+
+// List returns journal entries, newest first.
+func List(stateDir string) ([]Entry, error) {
+	dir := filepath.Join(stateDir, DirName)
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	out := make([]Entry, 0, len(ents))
+	for _, e := range ents {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var entry Entry
+		if err := json.Unmarshal(b, &entry); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", e.Name(), err)
+		}
+		out = append(out, entry)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID > out[j].ID })
+	return out, nil
+}
+
+// Check reports operations of e whose current on-disk state no longer matches
+// what the entry left behind. A write is only compared when no later op of the
+// entry rewrote, moved or removed the same path.
+func Check(stateDir string, e Entry) []string {
+	base := filepath.Dir(stateDir)
+	problems := make([]string, 0)
+	for i, op := range e.Ops {
+		p := filepath.Join(base, filepath.FromSlash(strings.TrimSuffix(op.Path, "/")))
+		switch op.Kind {
+		case OpWrite:
+			if superseded(e.Ops[i+1:], op.Path) {
+				continue
+			}
+			b, err := os.ReadFile(p)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: missing", op.Path))
+				continue
+			}
+			if digest(b) != op.After {
+				problems = append(problems, fmt.Sprintf("%s: modified after %s", op.Path, e.Command))
+			}
+		case OpRename:
+			to := filepath.Join(base, filepath.FromSlash(op.To))
+			if _, err := os.Stat(to); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: missing", op.To))
+			}
+		case OpRemove:
+			if strings.HasSuffix(op.Path, "/") {
+				continue
+			}
+			if _, err := os.Stat(p); err == nil {
+				problems = append(problems, fmt.Sprintf("%s: recreated after %s", op.Path, e.Command))
+			}
+		}
+	}
+	return problems
+}
+
+// superseded reports whether a later op replaced the file at path, either by
+// writing it again or by moving or removing it or one of its parents.
+func superseded(later []Op, path string) bool {
+	for _, op := range later {
+		switch op.Kind {
+		case OpWrite:
+			if op.Path == path {
+				return true
+			}
+		case OpRename, OpRemove:
+			from := strings.TrimSuffix(op.Path, "/")
+			if path == from || strings.HasPrefix(path, from+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Undo reverses e's operations in reverse order and marks it undone. Unless
+// force is set, it refuses to run when Check reports drift.
+func Undo(stateDir string, e Entry, force bool) error {
+	if e.Undone() {
+		return fmt.Errorf("entry %s already undone", e.ID)
+	}
+	if !force {
+		if problems := Check(stateDir, e); len(problems) > 0 {
+			return fmt.Errorf("%w: %s", ErrDrift, strings.Join(problems, "; "))
+		}
+	}
+	base := filepath.Dir(stateDir)
+	abs := func(rel string) string {
+		return filepath.Join(base, filepath.FromSlash(strings.TrimSuffix(rel, "/")))
+	}
+	for i := len(e.Ops) - 1; i >= 0; i-- {
+		op := e.Ops[i]
+		p := abs(op.Path)
+		switch op.Kind {
+		case OpWrite:
+			if !op.Existed {
+				if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return err
+				}
+				continue
+			}
+			if err := fsutil.WriteFileAtomic(p, []byte(op.Before), modeOr(op.Mode, 0o664)); err != nil {
+				return err
+			}
+		case OpMkdir:
+			if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) && !force {
+				return err
+			}
+		case OpRename:
+			if err := os.MkdirAll(filepath.Dir(p), 0o775); err != nil {
+				return err
+			}
+			if err := os.Rename(abs(op.To), p); err != nil {
+				return err
+			}
+		case OpRemove:
+			if strings.HasSuffix(op.Path, "/") {
+				if err := os.MkdirAll(p, modeOr(op.Mode, 0o775)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(p), 0o775); err != nil {
+				return err
+			}
+			if err := fsutil.WriteFileAtomic(p, []byte(op.Before), modeOr(op.Mode, 0o664)); err != nil {
+				return err
+			}
+		}
+	}
+	now := time.Now().UTC()
+	e.UndoneAt = &now
+	return writeEntry(filepath.Join(stateDir, DirName), e)
+}
+
+func writeEntry(dir string, e Entry) error {
+	b, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	return fsutil.WriteFileAtomic(filepath.Join(dir, e.ID+".json"), b, 0o644)
+}
+
+func prune(dir string, keep int) error {
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(ents))
+	for _, e := range ents {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) <= keep {
+		return nil
+	}
+	sort.Strings(names)
+	for _, n := range names[:len(names)-keep] {
+		if err := os.Remove(filepath.Join(dir, n)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func modeOr(mode uint32, def os.FileMode) os.FileMode {
+	if mode == 0 {
+		return def
+	}
+	return os.FileMode(mode)
+}
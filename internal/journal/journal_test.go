@@ -0,0 +1,143 @@
+package journal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"pacto/internal/fsutil"
+)
+
+func TestUndoRestoresWritesRenamesAndRemovals(t *testing.T) {
+	base := t.TempDir()
+	stateDir := filepath.Join(base, ".pacto")
+	plans := filepath.Join(stateDir, "plans")
+	for _, d := range []string{"a/one", "b/two"} {
+		if err := os.MkdirAll(filepath.Join(plans, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	index := filepath.Join(plans, "README.md")
+	if err := os.WriteFile(index, []byte("index v1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(plans, "b", "two", "README.md"), []byte("two\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := NewRecorder(stateDir, "move", []string{"a", "one", "b"})
+	if err := rec.RemoveAll(filepath.Join(plans, "b", "two")); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Rename(filepath.Join(plans, "a", "one"), filepath.Join(plans, "b", "one")); err != nil {
+		t.Fatal(err)
+	}
+	_, snap, err := fsutil.ReadSnapshot(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.WriteChecked(snap, []byte("index v2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.WriteChecked(fsutil.Snapshot{Path: filepath.Join(plans, "b", "one", "NEW.md")}, []byte("new\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rec.Commit("a/one -> b/one"); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := List(stateDir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one entry, got %d (%v)", len(entries), err)
+	}
+	if err := Undo(stateDir, entries[0], false); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+
+	if b, _ := os.ReadFile(index); string(b) != "index v1\n" {
+		t.Fatalf("index not restored: %q", string(b))
+	}
+	if _, err := os.Stat(filepath.Join(plans, "a", "one")); err != nil {
+		t.Fatalf("rename not reversed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(plans, "b", "one")); !os.IsNotExist(err) {
+		t.Fatalf("expected renamed dir gone, got %v", err)
+	}
+	if b, _ := os.ReadFile(filepath.Join(plans, "b", "two", "README.md")); string(b) != "two\n" {
+		t.Fatalf("removed tree not restored: %q", string(b))
+	}
+
+	entries, _ = List(stateDir)
+	if !entries[0].Undone() {
+		t.Fatal("expected entry marked undone")
+	}
+	if err := Undo(stateDir, entries[0], false); err == nil {
+		t.Fatal("expected second undo of same entry to fail")
+	}
+}
+
+func TestUndoRefusesDriftUnlessForced(t *testing.T) {
+	base := t.TempDir()
+	stateDir := filepath.Join(base, ".pacto")
+	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(base, "doc.md")
+	if err := os.WriteFile(p, []byte("v1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rec := NewRecorder(stateDir, "exec", nil)
+	_, snap, _ := fsutil.ReadSnapshot(p)
+	if err := rec.WriteChecked(snap, []byte("v2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := rec.Commit("exec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte("v3 by hand\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Undo(stateDir, entry, false); !errors.Is(err, ErrDrift) {
+		t.Fatalf("expected drift error, got %v", err)
+	}
+	if err := Undo(stateDir, entry, true); err != nil {
+		t.Fatalf("forced undo: %v", err)
+	}
+	if b, _ := os.ReadFile(p); string(b) != "v1\n" {
+		t.Fatalf("expected v1 restored, got %q", string(b))
+	}
+}
+
+func TestCheckComparesOnlyLastWriteOfAPath(t *testing.T) {
+	base := t.TempDir()
+	stateDir := filepath.Join(base, ".pacto")
+	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(base, "doc.md")
+	if err := os.WriteFile(p, []byte("v1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rec := NewRecorder(stateDir, "archive", nil)
+	for _, v := range []string{"v2\n", "v3\n"} {
+		_, snap, _ := fsutil.ReadSnapshot(p)
+		if err := rec.WriteChecked(snap, []byte(v), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entry, err := rec.Commit("archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := Check(stateDir, entry); len(problems) != 0 {
+		t.Fatalf("expected no drift, got %v", problems)
+	}
+	if err := Undo(stateDir, entry, false); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	if b, _ := os.ReadFile(p); string(b) != "v1\n" {
+		t.Fatalf("expected v1 restored, got %q", string(b))
+	}
+}
@@ -33,6 +33,7 @@ const (
 	ClaimEndpoint ClaimType = "endpoint"
 	ClaimTestRef  ClaimType = "test_ref"
 	ClaimDelta    ClaimType = "delta"
+	ClaimPlugin   ClaimType = "plugin"
 )
 
 type ClaimResult struct {
@@ -41,24 +42,27 @@ type ClaimResult struct {
 	Evidence   string    `json:"evidence"`
 	Result     string    `json:"result"`
 	References []string  `json:"references,omitempty"`
+	Source     string    `json:"source,omitempty"`
 }
 
 type PlanStatus struct {
-	StateFolder    string        `json:"state_folder"`
-	Slug           string        `json:"slug"`
-	Readme         string        `json:"readme"`
-	DeclaredStatus string        `json:"declared_status"`
-	DerivedStatus  string        `json:"derived_status"`
-	ProgressPct    *int          `json:"progress_percent,omitempty"`
-	PendingTasks   int           `json:"pending_tasks"`
-	BlockedTasks   int           `json:"blocked_tasks"`
-	Blockers       []string      `json:"blockers"`
-	NextActions    []string      `json:"next_actions"`
-	Verification   string        `json:"verification"`
-	Confidence     string        `json:"confidence"`
-	Claims         []ClaimResult `json:"claims,omitempty"`
-	ParseWarnings  []string      `json:"parse_warnings,omitempty"`
-	ParseError     string        `json:"parse_error,omitempty"`
+	Workspace      string            `json:"workspace,omitempty"`
+	StateFolder    string            `json:"state_folder"`
+	Slug           string            `json:"slug"`
+	Readme         string            `json:"readme"`
+	DeclaredStatus string            `json:"declared_status"`
+	DerivedStatus  string            `json:"derived_status"`
+	ProgressPct    *int              `json:"progress_percent,omitempty"`
+	PendingTasks   int               `json:"pending_tasks"`
+	BlockedTasks   int               `json:"blocked_tasks"`
+	Blockers       []string          `json:"blockers"`
+	NextActions    []string          `json:"next_actions"`
+	Verification   string            `json:"verification"`
+	Confidence     string            `json:"confidence"`
+	Claims         []ClaimResult     `json:"claims,omitempty"`
+	ParseWarnings  []string          `json:"parse_warnings,omitempty"`
+	ParseError     string            `json:"parse_error,omitempty"`
+	Annotations    map[string]string `json:"annotations,omitempty"`
 }
 
 type Summary struct {
@@ -70,11 +74,15 @@ type Summary struct {
 }
 
 type StatusReport struct {
-	GeneratedAt time.Time    `json:"generated_at"`
-	Root        string       `json:"root"`
-	PlansRoot   string       `json:"plans_root,omitempty"`
-	RepoRoot    string       `json:"repo_root,omitempty"`
-	Mode        string       `json:"mode"`
-	Summary     Summary      `json:"summary"`
-	Plans       []PlanStatus `json:"plans"`
+	GeneratedAt time.Time         `json:"generated_at"`
+	Root        string            `json:"root"`
+	PlansRoot   string            `json:"plans_root,omitempty"`
+	RepoRoot    string            `json:"repo_root,omitempty"`
+	Mode        string            `json:"mode"`
+	Summary     Summary           `json:"summary"`
+	States      []string          `json:"states,omitempty"`
+	Workspaces  []string          `json:"workspaces,omitempty"`
+	Plans       []PlanStatus      `json:"plans"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	NextActions []string          `json:"next_actions,omitempty"`
 }
@@ -10,13 +10,16 @@ import (
 )
 
 func DetectProfile(projectRoot string) Profile {
-	langs := detectLanguages(projectRoot)
+	layout := detectLayout(projectRoot)
 	tools := detectLocalTools(projectRoot)
 	sort.Strings(tools)
 	return Profile{
-		Languages:  langs,
-		Tools:      tools,
-		UILanguage: string(i18n.English),
+		Languages:   layout.languages,
+		Frameworks:  layout.frameworks,
+		Subprojects: layout.subprojects,
+		Workspaces:  layout.workspaces,
+		Tools:       tools,
+		UILanguage:  string(i18n.English),
 		Sources: Sources{
 			Languages: "auto",
 			Tools:     "auto",
@@ -35,17 +38,27 @@ func detectLocalTools(root string) []string {
 		{id: "cursor", dir: ".cursor"},
 		{id: "claude", dir: ".claude"},
 		{id: "opencode", dir: ".opencode"},
+		{id: "windsurf", dir: ".windsurf"},
+		{id: "cline", dir: ".clinerules"},
+		{id: "roo", dir: ".roo"},
+		{id: "copilot", dir: ".github/instructions"},
+		{id: "copilot", dir: ".github/prompts"},
+		{id: "aider", dir: ".aider"},
+		{id: "gemini", dir: ".gemini"},
 	}
 	out := make([]string, 0, len(checks))
+	seen := map[string]bool{}
 	for _, c := range checks {
-		if dirExists(filepath.Join(root, c.dir)) {
+		if !seen[c.id] && dirExists(filepath.Join(root, c.dir)) {
 			out = append(out, c.id)
+			seen[c.id] = true
 		}
 	}
 	return out
 }
 
-func detectLanguages(root string) []string {
+// detectLanguages looks for build manifests directly inside dir.
+func detectLanguages(dir string) []string {
 	found := map[string]bool{}
 	checks := map[string][]string{
 		"go":         {"go.mod"},
@@ -62,14 +75,14 @@ func detectLanguages(root string) []string {
 	for lang, files := range checks {
 		for _, f := range files {
 			if strings.Contains(f, "*") {
-				m, _ := filepath.Glob(filepath.Join(root, f))
+				m, _ := filepath.Glob(filepath.Join(dir, f))
 				if len(m) > 0 {
 					found[lang] = true
 					break
 				}
 				continue
 			}
-			if fileExists(filepath.Join(root, f)) {
+			if fileExists(filepath.Join(dir, f)) {
 				found[lang] = true
 				break
 			}
@@ -0,0 +1,209 @@
+package onboarding
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxDetectDepth bounds how deep below the project root manifests are found.
+const maxDetectDepth = 4
+
+// ignoredDirs are never walked during detection, in addition to hidden
+// directories and entries of the root .gitignore.
+var ignoredDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "dist": true, "build": true,
+	"target": true, "out": true, "bin": true, "obj": true, "venv": true,
+	"__pycache__": true, "coverage": true, "testdata": true,
+}
+
+type frameworkHint struct {
+	file      string
+	framework string
+	pattern   *regexp.Regexp
+}
+
+var frameworkHints = []frameworkHint{
+	{"go.mod", "gin", regexp.MustCompile(`github\.com/gin-gonic/gin\b`)},
+	{"go.mod", "echo", regexp.MustCompile(`github\.com/labstack/echo\b`)},
+	{"go.mod", "fiber", regexp.MustCompile(`github\.com/gofiber/fiber\b`)},
+	{"go.mod", "chi", regexp.MustCompile(`github\.com/go-chi/chi\b`)},
+	{"package.json", "react", regexp.MustCompile(`"react"\s*:`)},
+	{"package.json", "next", regexp.MustCompile(`"next"\s*:`)},
+	{"package.json", "vue", regexp.MustCompile(`"vue"\s*:`)},
+	{"package.json", "svelte", regexp.MustCompile(`"svelte"\s*:`)},
+	{"package.json", "angular", regexp.MustCompile(`"@angular/core"\s*:`)},
+	{"package.json", "express", regexp.MustCompile(`"express"\s*:`)},
+	{"package.json", "nestjs", regexp.MustCompile(`"@nestjs/core"\s*:`)},
+	{"requirements.txt", "django", regexp.MustCompile(`(?im)^\s*django\b`)},
+	{"requirements.txt", "flask", regexp.MustCompile(`(?im)^\s*flask\b`)},
+	{"requirements.txt", "fastapi", regexp.MustCompile(`(?im)^\s*fastapi\b`)},
+	{"pyproject.toml", "django", regexp.MustCompile(`(?i)["'\s]django\b`)},
+	{"pyproject.toml", "flask", regexp.MustCompile(`(?i)["'\s]flask\b`)},
+	{"pyproject.toml", "fastapi", regexp.MustCompile(`(?i)["'\s]fastapi\b`)},
+	{"Cargo.toml", "axum", regexp.MustCompile(`(?m)^\s*axum\s*=`)},
+	{"Cargo.toml", "actix", regexp.MustCompile(`(?m)^\s*actix-web\s*=`)},
+	{"Cargo.toml", "rocket", regexp.MustCompile(`(?m)^\s*rocket\s*=`)},
+	{"Gemfile", "rails", regexp.MustCompile(`gem\s+["']rails["']`)},
+	{"composer.json", "laravel", regexp.MustCompile(`"laravel/framework"\s*:`)},
+	{"composer.json", "symfony", regexp.MustCompile(`"symfony/framework-bundle"\s*:`)},
+	{"pom.xml", "spring", regexp.MustCompile(`spring-boot`)},
+	{"build.gradle", "spring", regexp.MustCompile(`spring-boot`)},
+	{"build.gradle.kts", "spring", regexp.MustCompile(`spring-boot`)},
+}
+
+var cargoWorkspace = regexp.MustCompile(`(?m)^\s*\[workspace\]`)
+
+type projectLayout struct {
+	languages   []string
+	frameworks  []string
+	subprojects []Subproject
+	workspaces  []Workspace
+}
+
+// detectLayout walks root for build manifests. The root directory itself
+// contributes languages and frameworks but is not listed as a subproject.
+func detectLayout(root string) projectLayout {
+	ignore := readGitignore(root)
+	langs := map[string]bool{}
+	frameworks := map[string]bool{}
+	var layout projectLayout
+
+	_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		rel = filepath.ToSlash(rel)
+		if rel != "." {
+			name := d.Name()
+			if strings.HasPrefix(name, ".") || ignoredDirs[name] || ignore.match(rel, name) {
+				return filepath.SkipDir
+			}
+			if strings.Count(rel, "/")+1 > maxDetectDepth {
+				return filepath.SkipDir
+			}
+		}
+		if kind := detectWorkspace(p); kind != "" {
+			layout.workspaces = append(layout.workspaces, Workspace{Kind: kind, Path: rel})
+		}
+		dirLangs := detectLanguages(p)
+		if len(dirLangs) == 0 {
+			return nil
+		}
+		dirFrameworks := detectFrameworks(p)
+		for _, l := range dirLangs {
+			langs[l] = true
+		}
+		for _, f := range dirFrameworks {
+			frameworks[f] = true
+		}
+		if rel != "." {
+			layout.subprojects = append(layout.subprojects, Subproject{Path: rel, Languages: dirLangs, Frameworks: dirFrameworks})
+		}
+		return nil
+	})
+
+	layout.languages = sortedKeys(langs)
+	layout.frameworks = sortedKeys(frameworks)
+	return layout
+}
+
+func detectFrameworks(dir string) []string {
+	found := map[string]bool{}
+	contents := map[string]string{}
+	for _, h := range frameworkHints {
+		text, ok := contents[h.file]
+		if !ok {
+			b, _ := os.ReadFile(filepath.Join(dir, h.file))
+			text = string(b)
+			contents[h.file] = text
+		}
+		if text != "" && h.pattern.MatchString(text) {
+			found[h.framework] = true
+		}
+	}
+	return sortedKeys(found)
+}
+
+// detectWorkspace reports the kind of multi-module manifest in dir, if any.
+func detectWorkspace(dir string) string {
+	if fileExists(filepath.Join(dir, "go.work")) {
+		return "go"
+	}
+	if fileExists(filepath.Join(dir, "pnpm-workspace.yaml")) {
+		return "pnpm"
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		var pkg struct {
+			Workspaces json.RawMessage `json:"workspaces"`
+		}
+		if json.Unmarshal(b, &pkg) == nil && len(pkg.Workspaces) > 0 && string(pkg.Workspaces) != "null" {
+			if fileExists(filepath.Join(dir, "yarn.lock")) {
+				return "yarn"
+			}
+			return "npm"
+		}
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "Cargo.toml")); err == nil {
+		if cargoWorkspace.Match(b) {
+			return "cargo"
+		}
+	}
+	return ""
+}
+
+// gitignore holds the patterns of a root .gitignore that detection can
+// apply to directories. Negations and ** patterns are skipped.
+type gitignore []string
+
+func readGitignore(root string) gitignore {
+	f, err := os.Open(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var out gitignore
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		ln := strings.TrimSpace(s.Text())
+		if ln == "" || strings.HasPrefix(ln, "#") || strings.HasPrefix(ln, "!") || strings.Contains(ln, "**") {
+			continue
+		}
+		ln = strings.TrimSuffix(ln, "/")
+		if ln != "" {
+			out = append(out, ln)
+		}
+	}
+	return out
+}
+
+func (g gitignore) match(rel, name string) bool {
+	for _, pat := range g {
+		if strings.HasPrefix(pat, "/") || strings.Contains(pat, "/") {
+			if ok, _ := path.Match(strings.TrimPrefix(pat, "/"), rel); ok {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pat, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,82 @@
+package onboarding
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectProfileMonorepo(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.work":                         "go 1.22\n\nuse ./services/api\n",
+		".gitignore":                      "# local\nscratch/\n",
+		"services/api/go.mod":             "module api\n\nrequire github.com/gin-gonic/gin v1.9.1\n",
+		"web/package.json":                `{"workspaces": ["packages/*"], "dependencies": {"react": "^18.0.0"}}`,
+		"web/tsconfig.json":               "{}\n",
+		"web/node_modules/x/package.json": "{}\n",
+		"ml/requirements.txt":             "Django==5.0\n",
+		"scratch/go.mod":                  "module scratch\n",
+		".hidden/Cargo.toml":              "[package]\n",
+		"a/b/c/d/e/go.mod":                "module deep\n",
+	}
+	for rel, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := DetectProfile(root)
+	if want := []string{"go", "javascript", "python", "typescript"}; !reflect.DeepEqual(p.Languages, want) {
+		t.Fatalf("languages = %v, want %v", p.Languages, want)
+	}
+	if want := []string{"django", "gin", "react"}; !reflect.DeepEqual(p.Frameworks, want) {
+		t.Fatalf("frameworks = %v, want %v", p.Frameworks, want)
+	}
+	wantSubs := []Subproject{
+		{Path: "ml", Languages: []string{"python"}, Frameworks: []string{"django"}},
+		{Path: "services/api", Languages: []string{"go"}, Frameworks: []string{"gin"}},
+		{Path: "web", Languages: []string{"javascript", "typescript"}, Frameworks: []string{"react"}},
+	}
+	if !reflect.DeepEqual(p.Subprojects, wantSubs) {
+		t.Fatalf("subprojects = %+v, want %+v", p.Subprojects, wantSubs)
+	}
+	wantWS := []Workspace{{Kind: "go", Path: "."}, {Kind: "npm", Path: "web"}}
+	if !reflect.DeepEqual(p.Workspaces, wantWS) {
+		t.Fatalf("workspaces = %+v, want %+v", p.Workspaces, wantWS)
+	}
+
+	cfgPath, err := WriteConfig(root, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(b)
+	for _, want := range []string{"path: services/api", "kind: go", "scopes:\n        - ml\n        - services/api\n        - web"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in config, got:\n%s", want, text)
+		}
+	}
+
+	// Edited scopes survive a second init.
+	edited := strings.Replace(text, "        - ml\n", "", 1)
+	if err := os.WriteFile(cfgPath, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteConfig(root, p); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = os.ReadFile(cfgPath)
+	if strings.Contains(string(b), "- ml\n        - services/api") {
+		t.Fatalf("expected edited scopes to be kept, got:\n%s", b)
+	}
+}
@@ -4,7 +4,7 @@ import "strings"
 
 var (
 	KnownLanguages = []string{"go", "typescript", "javascript", "python", "rust", "java", "dotnet", "ruby", "php"}
-	KnownTools     = []string{"codex", "cursor", "claude", "opencode"}
+	KnownTools     = []string{"codex", "cursor", "claude", "opencode", "windsurf", "cline", "roo", "copilot", "aider", "gemini"}
 )
 
 func IsKnownLanguage(v string) bool {
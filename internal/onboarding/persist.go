@@ -88,6 +88,7 @@ func mergeProfileConfig(m map[string]any, p Profile) {
 	} else {
 		delete(project, "custom_languages")
 	}
+	mergeLayoutConfig(m, project, p)
 	intents := yamlutil.EnsureMap(project, "intents")
 	intents["problem"] = strings.TrimSpace(p.Intents.Problem)
 
@@ -115,6 +116,45 @@ func mergeProfileConfig(m map[string]any, p Profile) {
 	}
 }
 
+// mergeLayoutConfig records detected frameworks, subprojects and workspaces.
+// verification.scopes is seeded from the subprojects once; later edits are
+// kept.
+func mergeLayoutConfig(m, project map[string]any, p Profile) {
+	if len(p.Frameworks) > 0 {
+		project["frameworks"] = normalizeForYAML(p.Frameworks)
+	} else {
+		delete(project, "frameworks")
+	}
+	if len(p.Subprojects) > 0 {
+		subs := make([]map[string]any, 0, len(p.Subprojects))
+		scopes := make([]string, 0, len(p.Subprojects))
+		for _, sp := range p.Subprojects {
+			entry := map[string]any{"path": sp.Path, "languages": normalizeForYAML(sp.Languages)}
+			if len(sp.Frameworks) > 0 {
+				entry["frameworks"] = normalizeForYAML(sp.Frameworks)
+			}
+			subs = append(subs, entry)
+			scopes = append(scopes, sp.Path)
+		}
+		project["subprojects"] = subs
+		verification := yamlutil.EnsureMap(m, "verification")
+		if _, ok := verification["scopes"]; !ok {
+			verification["scopes"] = scopes
+		}
+	} else {
+		delete(project, "subprojects")
+	}
+	if len(p.Workspaces) > 0 {
+		ws := make([]map[string]any, 0, len(p.Workspaces))
+		for _, w := range p.Workspaces {
+			ws = append(ws, map[string]any{"kind": w.Kind, "path": w.Path})
+		}
+		project["workspaces"] = ws
+	} else {
+		delete(project, "workspaces")
+	}
+}
+
 func normalizeForYAML(items []string) []string {
 	out := make([]string, 0, len(items))
 	seen := map[string]bool{}
@@ -10,9 +10,27 @@ type Sources struct {
 	UI        string
 }
 
+// Subproject is a directory below the project root with its own build
+// manifest (go.mod, package.json, ...). Path is slash-separated and relative.
+type Subproject struct {
+	Path       string
+	Languages  []string
+	Frameworks []string
+}
+
+// Workspace is a multi-module manifest found at Path. Kind is go (go.work),
+// pnpm (pnpm-workspace.yaml), yarn or npm (package.json workspaces) or cargo.
+type Workspace struct {
+	Kind string
+	Path string
+}
+
 type Profile struct {
 	Languages       []string
 	CustomLanguages []string
+	Frameworks      []string
+	Subprojects     []Subproject
+	Workspaces      []Workspace
 	Tools           []string
 	CustomTools     []string
 	UILanguage      string
@@ -24,7 +24,47 @@ func ReadActiveConfig(projectRoot string) (ActiveConfig, error) {
 	if plugins == nil {
 		return ActiveConfig{}, nil
 	}
-	return ActiveConfig{Enabled: yamlutil.ToStringSlice(plugins["enabled"])}, nil
+	cfg := ActiveConfig{
+		Enabled:     yamlutil.ToStringSlice(plugins["enabled"]),
+		Approved:    map[string]string{},
+		Parallelism: positiveInt(plugins["parallelism"]),
+		BudgetMS:    positiveInt(plugins["budgetMs"]),
+	}
+	for id, digest := range yamlutil.GetMap(plugins, "approved") {
+		if s, ok := digest.(string); ok {
+			cfg.Approved[strings.ToLower(id)] = s
+		}
+	}
+	return cfg, nil
+}
+
+// Approve records the permissions digest the user accepted for a plugin.
+// LoadActive refuses the plugin once its permissions no longer match.
+func Approve(projectRoot, id, digest string) error {
+	return updateApproval(projectRoot, strings.ToLower(strings.TrimSpace(id)), digest)
+}
+
+func updateApproval(projectRoot, id, digest string) error {
+	cfgPath := filepath.Join(projectRoot, ".pacto", "config.yaml")
+	m, err := yamlutil.ReadMapOrDefault(cfgPath)
+	if err != nil {
+		return err
+	}
+	plugins := yamlutil.GetMap(m, "plugins")
+	approved := yamlutil.GetMap(plugins, "approved")
+	if digest == "" && approved[id] == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0o775); err != nil {
+		return err
+	}
+	approved = yamlutil.EnsureMap(yamlutil.EnsureMap(m, "plugins"), "approved")
+	if digest == "" {
+		delete(approved, id)
+	} else {
+		approved[id] = digest
+	}
+	return yamlutil.WriteMap(cfgPath, m)
 }
 
 func WriteActiveConfig(projectRoot string, enabled []string) error {
@@ -89,5 +129,18 @@ func Disable(projectRoot, id string) error {
 		out = append(out, t)
 	}
 	sort.Strings(out)
-	return WriteActiveConfig(projectRoot, out)
+	if err := WriteActiveConfig(projectRoot, out); err != nil {
+		return err
+	}
+	return updateApproval(projectRoot, id, "")
+}
+
+func positiveInt(v any) int {
+	switch n := v.(type) {
+	case int:
+		return max(n, 0)
+	case float64:
+		return max(int(n), 0)
+	}
+	return 0
 }
@@ -0,0 +1,238 @@
+package plugins
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AuditFileName is the JSONL log under .pacto recording every guardrail
+// run, passes included, so reports have a total to compare failures with.
+const AuditFileName = "guardrails.audit.jsonl"
+
+// auditOutputLimit bounds stdout and stderr per audit entry.
+const auditOutputLimit = 1000
+
+// Audit outcomes.
+const (
+	AuditBlocked  = "blocked"
+	AuditBypassed = "bypassed"
+	AuditWarned   = "warned"
+	AuditFailed   = "failed"
+	AuditAllowed  = "allowed"
+	AuditPassed   = "passed"
+)
+
+type AuditEntry struct {
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"`
+	Args       []string  `json:"args,omitempty"`
+	Plugin     string    `json:"plugin"`
+	Guardrail  string    `json:"guardrail"`
+	Phase      string    `json:"phase"`
+	Outcome    string    `json:"outcome"`
+	Decision   string    `json:"decision"`
+	Allowed    bool      `json:"allowed,omitempty"`
+	TimedOut   bool      `json:"timed_out,omitempty"`
+	ExitCode   int       `json:"exit_code"`
+	DurationMS int64     `json:"duration_ms"`
+	Message    string    `json:"message,omitempty"`
+	Stdout     string    `json:"stdout,omitempty"`
+	Stderr     string    `json:"stderr,omitempty"`
+}
+
+func (e AuditEntry) FullID() string {
+	return e.Plugin + "/" + e.Guardrail
+}
+
+func AuditPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".pacto", AuditFileName)
+}
+
+// auditOutcome classifies a result: only pre-phase denies block or can be
+// bypassed; post and onError denies are reported as failed.
+func auditOutcome(v GuardrailViolation) string {
+	switch {
+	case v.Passed:
+		return AuditPassed
+	case v.Decision == DecisionAllow:
+		return AuditAllowed
+	case v.Decision == DecisionWarn:
+		return AuditWarned
+	case normalizePhase(v.Phase) != PhasePre:
+		return AuditFailed
+	case v.Allowed:
+		return AuditBypassed
+	default:
+		return AuditBlocked
+	}
+}
+
+// AppendAudit appends one entry per result to the audit log in a single
+// write, so concurrent pacto processes do not interleave lines.
+func AppendAudit(projectRoot, command string, args []string, results []GuardrailViolation, now time.Time) error {
+	if len(results) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, v := range results {
+		e := AuditEntry{
+			Time:       now.UTC(),
+			Command:    command,
+			Args:       args,
+			Plugin:     v.PluginID,
+			Guardrail:  v.GuardrailID,
+			Phase:      normalizePhase(v.Phase),
+			Outcome:    auditOutcome(v),
+			Decision:   v.Decision,
+			Allowed:    v.Allowed,
+			TimedOut:   v.TimedOut,
+			ExitCode:   v.ExitCode,
+			DurationMS: v.Duration.Milliseconds(),
+			Message:    strings.TrimSpace(v.Message),
+			Stdout:     trimAuditOutput(v.Stdout),
+			Stderr:     trimAuditOutput(v.Stderr),
+		}
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	path := AuditPath(projectRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func trimAuditOutput(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > auditOutputLimit {
+		return s[:auditOutputLimit] + "..."
+	}
+	return s
+}
+
+// ReadAudit returns the audit entries at or after since (all when zero),
+// oldest first. Lines that do not parse are skipped.
+func ReadAudit(projectRoot string, since time.Time) ([]AuditEntry, error) {
+	f, err := os.Open(AuditPath(projectRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	out := make([]AuditEntry, 0)
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for sc.Scan() {
+		var e AuditEntry
+		if json.Unmarshal(sc.Bytes(), &e) != nil || e.Plugin == "" {
+			continue
+		}
+		if !since.IsZero() && e.Time.Before(since) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, sc.Err()
+}
+
+// AuditStats counts outcomes for one plugin or guardrail.
+type AuditStats struct {
+	ID            string `json:"id"`
+	Runs          int    `json:"runs"`
+	Passed        int    `json:"passed"`
+	Blocked       int    `json:"blocked"`
+	Bypassed      int    `json:"bypassed"`
+	Warned        int    `json:"warned"`
+	Failed        int    `json:"failed"`
+	TimedOut      int    `json:"timed_out"`
+	Slow          int    `json:"slow"`
+	MaxDurationMS int64  `json:"max_duration_ms"`
+	AvgDurationMS int64  `json:"avg_duration_ms"`
+	totalMS       int64
+}
+
+func (s *AuditStats) add(e AuditEntry, slow time.Duration) {
+	s.Runs++
+	switch e.Outcome {
+	case AuditPassed, AuditAllowed:
+		s.Passed++
+	case AuditBlocked:
+		s.Blocked++
+	case AuditBypassed:
+		s.Bypassed++
+	case AuditWarned:
+		s.Warned++
+	case AuditFailed:
+		s.Failed++
+	}
+	if e.TimedOut {
+		s.TimedOut++
+	}
+	if slow > 0 && e.DurationMS >= slow.Milliseconds() {
+		s.Slow++
+	}
+	if e.DurationMS > s.MaxDurationMS {
+		s.MaxDurationMS = e.DurationMS
+	}
+	s.totalMS += e.DurationMS
+	s.AvgDurationMS = s.totalMS / int64(s.Runs)
+}
+
+// AuditPluginReport summarizes one plugin and each of its guardrails.
+type AuditPluginReport struct {
+	AuditStats
+	Guardrails []AuditStats `json:"guardrails"`
+}
+
+// SummarizeAudit groups entries by plugin and guardrail, sorted by id. Hooks
+// taking at least slow are counted as slow.
+func SummarizeAudit(entries []AuditEntry, slow time.Duration) []AuditPluginReport {
+	byPlugin := map[string]*AuditPluginReport{}
+	byGuardrail := map[string]*AuditStats{}
+	for _, e := range entries {
+		p := byPlugin[e.Plugin]
+		if p == nil {
+			p = &AuditPluginReport{AuditStats: AuditStats{ID: e.Plugin}}
+			byPlugin[e.Plugin] = p
+		}
+		p.add(e, slow)
+		g := byGuardrail[e.FullID()]
+		if g == nil {
+			g = &AuditStats{ID: e.FullID()}
+			byGuardrail[e.FullID()] = g
+		}
+		g.add(e, slow)
+	}
+	out := make([]AuditPluginReport, 0, len(byPlugin))
+	for _, p := range byPlugin {
+		for _, g := range byGuardrail {
+			if strings.HasPrefix(g.ID, p.ID+"/") {
+				p.Guardrails = append(p.Guardrails, *g)
+			}
+		}
+		sort.Slice(p.Guardrails, func(i, j int) bool { return p.Guardrails[i].ID < p.Guardrails[j].ID })
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
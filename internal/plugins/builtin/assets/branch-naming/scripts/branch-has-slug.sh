@@ -0,0 +1,65 @@
+#!/bin/sh
+set -eu
+
+SCRIPT_DIR=$(CDPATH= cd -- "$(dirname -- "$0")" && pwd)
+PLUGIN_DIR=$(dirname "$SCRIPT_DIR")
+CONFIG_FILE="$PLUGIN_DIR/config.env"
+
+EXEMPT_BRANCHES=""
+BRANCH_PREFIX="feat/"
+
+if [ -f "$CONFIG_FILE" ]; then
+  # shellcheck disable=SC1090
+  . "$CONFIG_FILE"
+fi
+
+msg() {
+  if [ "${PACTO_LANG:-en}" = "es" ]; then printf '%s' "$2"; else printf '%s' "$1"; fi
+}
+
+json_escape() {
+  printf '%s' "$1" | sed -e 's/\\/\\\\/g' -e 's/"/\\"/g' | awk 'NR > 1 { printf "\\n" } { printf "%s", $0 }'
+}
+
+deny() {
+  printf '{"version":"pacto.hook/v1","decision":"deny","message":"%s","next_actions":[{"plan":"%s","text":"%s"}]}\n' \
+    "$(json_escape "$1")" "$(json_escape "$PLAN")" "$(json_escape "$2")"
+  exit 0
+}
+
+PLAN=${PACTO_PLANS:-}
+PLAN=${PLAN%% *}
+if [ -z "$PLAN" ]; then
+  exit 0
+fi
+SLUG=${PLAN#*/}
+
+cd "${PACTO_PROJECT_ROOT:-.}"
+if ! git rev-parse --is-inside-work-tree >/dev/null 2>&1; then
+  exit 0
+fi
+
+BRANCH=$(git symbolic-ref --quiet --short HEAD 2>/dev/null || true)
+if [ -z "$BRANCH" ]; then
+  deny \
+    "$(msg "HEAD is detached; plan $PLAN needs a branch containing '$SLUG'." "HEAD está desacoplado; el plan $PLAN necesita una rama que contenga '$SLUG'.")" \
+    "$(msg "Run: git switch -c $BRANCH_PREFIX$SLUG" "Ejecuta: git switch -c $BRANCH_PREFIX$SLUG")"
+fi
+
+set -f
+for pattern in $EXEMPT_BRANCHES; do
+  case "$BRANCH" in
+    $pattern) exit 0 ;;
+  esac
+done
+set +f
+
+LOWER_BRANCH=$(printf '%s' "$BRANCH" | tr '[:upper:]' '[:lower:]')
+LOWER_SLUG=$(printf '%s' "$SLUG" | tr '[:upper:]' '[:lower:]')
+case "$LOWER_BRANCH" in
+  *"$LOWER_SLUG"*) exit 0 ;;
+esac
+
+deny \
+  "$(msg "Branch '$BRANCH' does not contain the plan slug '$SLUG'." "La rama '$BRANCH' no contiene el slug del plan '$SLUG'.")" \
+  "$(msg "Switch to a plan branch: git switch -c $BRANCH_PREFIX$SLUG" "Cambia a una rama del plan: git switch -c $BRANCH_PREFIX$SLUG")"
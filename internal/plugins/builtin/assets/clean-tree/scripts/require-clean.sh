@@ -0,0 +1,71 @@
+#!/bin/sh
+set -eu
+
+SCRIPT_DIR=$(CDPATH= cd -- "$(dirname -- "$0")" && pwd)
+PLUGIN_DIR=$(dirname "$SCRIPT_DIR")
+CONFIG_FILE="$PLUGIN_DIR/config.env"
+
+ALLOW_UNTRACKED=0
+IGNORE_PATHS=".pacto/guardrails.audit.jsonl .pacto/journal/ .pacto/plugins/*/config.env"
+
+if [ -f "$CONFIG_FILE" ]; then
+  # shellcheck disable=SC1090
+  . "$CONFIG_FILE"
+fi
+
+msg() {
+  if [ "${PACTO_LANG:-en}" = "es" ]; then printf '%s' "$2"; else printf '%s' "$1"; fi
+}
+
+json_escape() {
+  printf '%s' "$1" | sed -e 's/\\/\\\\/g' -e 's/"/\\"/g' | awk 'NR > 1 { printf "\\n" } { printf "%s", $0 }'
+}
+
+deny() {
+  printf '{"version":"pacto.hook/v1","decision":"deny","message":"%s","next_actions":[{"text":"%s"}]}\n' \
+    "$(json_escape "$1")" "$(json_escape "$2")"
+  exit 0
+}
+
+cd "${PACTO_PROJECT_ROOT:-.}"
+if ! git rev-parse --is-inside-work-tree >/dev/null 2>&1; then
+  exit 0
+fi
+
+UNTRACKED=all
+if [ "$ALLOW_UNTRACKED" = "1" ]; then
+  UNTRACKED=no
+fi
+# Porcelain paths are relative to the repository top level.
+PREFIX=$(git rev-parse --show-prefix)
+
+set -f
+DIRTY=$(git status --porcelain --untracked-files="$UNTRACKED" -- . | while IFS= read -r line; do
+  path=${line#???}
+  path=${path##* -> }
+  skip=0
+  for p in $IGNORE_PATHS; do
+    case "$path" in
+      "$PREFIX"$p*) skip=1 ;;
+    esac
+  done
+  if [ "$skip" = "0" ]; then
+    printf '%s\n' "${path#"$PREFIX"}"
+  fi
+done)
+set +f
+
+if [ -z "$DIRTY" ]; then
+  exit 0
+fi
+
+COUNT=$(printf '%s\n' "$DIRTY" | wc -l | tr -d ' ')
+SHOWN=$(printf '%s\n' "$DIRTY" | head -n 5 | paste -sd ',' - | sed 's/,/, /g')
+if [ "$COUNT" -gt 5 ]; then
+  SHOWN="$SHOWN, ..."
+fi
+CMD=${PACTO_COMMAND:-exec}
+
+deny \
+  "$(msg "Working tree has $COUNT uncommitted change(s): $SHOWN" "El árbol de trabajo tiene $COUNT cambio(s) sin confirmar: $SHOWN")" \
+  "$(msg "Commit or stash your changes before \`pacto $CMD\`." "Confirma o guarda (git stash) tus cambios antes de \`pacto $CMD\`.")"
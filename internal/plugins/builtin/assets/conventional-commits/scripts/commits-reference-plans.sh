@@ -0,0 +1,98 @@
+#!/bin/sh
+set -eu
+
+SCRIPT_DIR=$(CDPATH= cd -- "$(dirname -- "$0")" && pwd)
+PLUGIN_DIR=$(dirname "$SCRIPT_DIR")
+CONFIG_FILE="$PLUGIN_DIR/config.env"
+
+COMMIT_COUNT=5
+TYPES="feat fix docs style refactor perf test build ci chore revert"
+REQUIRE_SLUG=1
+DECISION=deny
+
+if [ -f "$CONFIG_FILE" ]; then
+  # shellcheck disable=SC1090
+  . "$CONFIG_FILE"
+fi
+
+msg() {
+  if [ "${PACTO_LANG:-en}" = "es" ]; then printf '%s' "$2"; else printf '%s' "$1"; fi
+}
+
+json_escape() {
+  printf '%s' "$1" | sed -e 's/\\/\\\\/g' -e 's/"/\\"/g' | awk 'NR > 1 { printf "\\n" } { printf "%s", $0 }'
+}
+
+respond() {
+  printf '{"version":"pacto.hook/v1","decision":"%s","message":"%s","next_actions":[{"text":"%s"}]}\n' \
+    "$DECISION" "$(json_escape "$1")" "$(json_escape "$2")"
+  exit 0
+}
+
+cd "${PACTO_PROJECT_ROOT:-.}"
+if ! git rev-parse --is-inside-work-tree >/dev/null 2>&1; then
+  exit 0
+fi
+if ! git rev-parse --verify --quiet HEAD >/dev/null; then
+  exit 0
+fi
+
+RANGE=HEAD
+if git rev-parse --abbrev-ref --symbolic-full-name '@{upstream}' >/dev/null 2>&1; then
+  RANGE='@{upstream}..HEAD'
+fi
+COMMITS=$(git log --no-merges --format=%h -n "$COMMIT_COUNT" "$RANGE")
+if [ -z "$COMMITS" ]; then
+  exit 0
+fi
+
+SLUGS=""
+PLANS_ROOT=${PACTO_PLANS_ROOT:-}
+if [ -n "$PLANS_ROOT" ] && [ -d "$PLANS_ROOT" ]; then
+  for dir in "$PLANS_ROOT"/*/*/; do
+    [ -d "$dir" ] || continue
+    state=$(basename "$(dirname "$dir")")
+    if [ "$state" = "archive" ]; then
+      continue
+    fi
+    SLUGS="$SLUGS $(basename "$dir")"
+  done
+fi
+
+TYPE_RE=$(printf '%s' "$TYPES" | tr -s ' ' '|')
+BAD=""
+COUNT=0
+for c in $COMMITS; do
+  subject=$(git log -1 --format=%s "$c")
+  reason=""
+  if ! printf '%s\n' "$subject" | grep -Eq "^($TYPE_RE)(\([^)]+\))?!?: .+"; then
+    reason=$(msg "not a conventional commit" "no es un conventional commit")
+  elif [ "$REQUIRE_SLUG" = "1" ]; then
+    body=$(git log -1 --format=%B "$c")
+    found=0
+    for s in $SLUGS; do
+      if printf '%s\n' "$body" | grep -Eiq "(^|[^a-z0-9-])$s([^a-z0-9-]|\$)"; then
+        found=1
+        break
+      fi
+    done
+    if [ "$found" = "0" ]; then
+      reason=$(msg "no plan slug" "sin slug de plan")
+    fi
+  fi
+  if [ -n "$reason" ]; then
+    COUNT=$((COUNT + 1))
+    if [ "$COUNT" -le 5 ]; then
+      BAD="$BAD; $c $subject ($reason)"
+    fi
+  fi
+done
+
+if [ "$COUNT" = "0" ]; then
+  exit 0
+fi
+BAD=${BAD#; }
+
+respond \
+  "$(msg "$COUNT recent commit(s) do not follow the convention: $BAD" "$COUNT commit(s) recientes no siguen la convención: $BAD")" \
+  "$(msg "Reword them as type(<plan-slug>): summary, e.g. git commit --amend or git rebase -i." "Reescríbelos como tipo(<slug-del-plan>): resumen, p. ej. con git commit --amend o git rebase -i.")"
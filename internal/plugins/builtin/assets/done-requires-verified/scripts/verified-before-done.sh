@@ -0,0 +1,68 @@
+#!/bin/sh
+set -eu
+
+SCRIPT_DIR=$(CDPATH= cd -- "$(dirname -- "$0")" && pwd)
+PLUGIN_DIR=$(dirname "$SCRIPT_DIR")
+CONFIG_FILE="$PLUGIN_DIR/config.env"
+
+DONE_STATE=done
+REQUIRED_VERIFICATION=verified
+
+if [ -f "$CONFIG_FILE" ]; then
+  # shellcheck disable=SC1090
+  . "$CONFIG_FILE"
+fi
+
+msg() {
+  if [ "${PACTO_LANG:-en}" = "es" ]; then printf '%s' "$2"; else printf '%s' "$1"; fi
+}
+
+json_escape() {
+  printf '%s' "$1" | sed -e 's/\\/\\\\/g' -e 's/"/\\"/g' | awk 'NR > 1 { printf "\\n" } { printf "%s", $0 }'
+}
+
+deny() {
+  printf '{"version":"pacto.hook/v1","decision":"deny","message":"%s","next_actions":[{"plan":"%s","text":"%s"}]}\n' \
+    "$(json_escape "$1")" "$(json_escape "$PLAN")" "$(json_escape "$2")"
+  exit 0
+}
+
+# positionals: <from-state> <slug> <to-state>
+REQUEST=$(cat)
+POSITIONALS=$(printf '%s' "$REQUEST" | sed -n 's/.*"positionals":\[\([^]]*\)\].*/\1/p' | tr -d '"' | tr ',' ' ')
+set -f
+# shellcheck disable=SC2086
+set -- $POSITIONALS
+set +f
+if [ "$#" -lt 3 ] || [ "$3" != "$DONE_STATE" ]; then
+  exit 0
+fi
+FROM=$1
+SLUG=$2
+PLAN="$FROM/$SLUG"
+
+ROOT=${PACTO_PLANS_ROOT:-}
+if [ -z "$ROOT" ]; then
+  ROOT=${PACTO_PROJECT_ROOT:-.}
+fi
+if ! REPORT=$("${PACTO_BIN:-pacto}" status --root "$ROOT" --state "$FROM" --format json 2>&1); then
+  deny \
+    "$(msg "Could not compute the verification of $PLAN: $REPORT" "No se pudo calcular la verificación de $PLAN: $REPORT")" \
+    "$(msg "Run pacto status and fix the reported errors." "Ejecuta pacto status y corrige los errores reportados.")"
+fi
+
+VERIFICATION=$(printf '%s\n' "$REPORT" | awk -v slug="\"slug\": \"$SLUG\"," '
+  index($0, slug) { found = 1; next }
+  found && /"verification": "/ { sub(/.*"verification": "/, ""); sub(/".*/, ""); print; exit }
+')
+
+if [ "$VERIFICATION" = "$REQUIRED_VERIFICATION" ]; then
+  exit 0
+fi
+if [ -z "$VERIFICATION" ]; then
+  VERIFICATION=unknown
+fi
+
+deny \
+  "$(msg "Plan $PLAN is $VERIFICATION, not $REQUIRED_VERIFICATION; it cannot move to $DONE_STATE yet." "El plan $PLAN está $VERIFICATION, no $REQUIRED_VERIFICATION; todavía no puede pasar a $DONE_STATE.")" \
+  "$(msg "Run pacto status to see unverified claims and add the missing evidence." "Ejecuta pacto status para ver las afirmaciones sin verificar y agrega la evidencia que falta.")"
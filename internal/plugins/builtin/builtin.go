@@ -38,6 +38,22 @@ var catalog = map[string]PluginInfo{
 		ID:      "git-sync",
 		Summary: "Sync git fetch/pull context before pacto status.",
 	},
+	"clean-tree": {
+		ID:      "clean-tree",
+		Summary: "Block exec/move on a dirty git working tree.",
+	},
+	"branch-naming": {
+		ID:      "branch-naming",
+		Summary: "Require the git branch to contain the plan slug on exec/move.",
+	},
+	"done-requires-verified": {
+		ID:      "done-requires-verified",
+		Summary: "Block moving a plan to done unless its verification is verified.",
+	},
+	"conventional-commits": {
+		ID:      "conventional-commits",
+		Summary: "Check recent commits are conventional and reference plan slugs.",
+	},
 }
 
 func ListAvailable() []PluginInfo {
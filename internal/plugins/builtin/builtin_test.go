@@ -2,8 +2,12 @@ package builtin
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"pacto/internal/plugins"
 )
 
 func TestListAvailableIncludesGitSync(t *testing.T) {
@@ -55,3 +59,171 @@ func TestInstallUnknownPluginReturnsError(t *testing.T) {
 		t.Fatalf("expected error for unknown plugin")
 	}
 }
+
+func TestCatalogPluginsInstallWithValidManifests(t *testing.T) {
+	root := t.TempDir()
+	for _, info := range ListAvailable() {
+		if _, err := Install(root, info.ID, InstallOptions{}); err != nil {
+			t.Fatalf("install %s: %v", info.ID, err)
+		}
+	}
+	d := plugins.Discover(root)
+	if len(d.Errors) > 0 {
+		t.Fatalf("invalid built-in manifests: %v", d.Errors)
+	}
+	if len(d.Plugins) != len(catalog) {
+		t.Fatalf("discovered %d plugins, want %d", len(d.Plugins), len(catalog))
+	}
+	for _, p := range d.Plugins {
+		if len(p.Manifest.Spec.AgentGuardrails) == 0 {
+			t.Fatalf("%s has no agent guardrail", p.Manifest.Metadata.ID)
+		}
+	}
+}
+
+func TestCleanTreeBlocksDirtyWorkingTree(t *testing.T) {
+	root := gitRepo(t)
+	if _, err := Install(root, "clean-tree", InstallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	git(t, root, "add", ".pacto")
+	git(t, root, "commit", "-q", "-m", "chore: add clean-tree")
+	req := plugins.HookRequest{Command: "exec", ProjectRoot: root}
+	if v := runBuiltin(t, root, "clean-tree", req); len(v) != 0 {
+		t.Fatalf("clean tree should pass, got %#v", v)
+	}
+	if err := os.WriteFile(filepath.Join(root, "notes.txt"), []byte("wip\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	req.Language = "es"
+	v := runBuiltin(t, root, "clean-tree", req)
+	if len(v) != 1 || v[0].Decision != plugins.DecisionDeny || !strings.Contains(v[0].Message, "1 cambio(s) sin confirmar: notes.txt") {
+		t.Fatalf("expected localized deny for dirty tree, got %#v", v)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".pacto", "plugins", "clean-tree", "config.env"), []byte("ALLOW_UNTRACKED=1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if v := runBuiltin(t, root, "clean-tree", req); len(v) != 0 {
+		t.Fatalf("untracked files should be allowed, got %#v", v)
+	}
+}
+
+func TestBranchNamingRequiresPlanSlug(t *testing.T) {
+	root := gitRepo(t)
+	req := plugins.HookRequest{Command: "move", ProjectRoot: root, Plans: []string{"current/auth-flow"}}
+	v := runBuiltin(t, root, "branch-naming", req)
+	if len(v) != 1 || !strings.Contains(v[0].Message, "does not contain the plan slug 'auth-flow'") || len(v[0].NextActions) != 1 {
+		t.Fatalf("expected deny on main, got %#v", v)
+	}
+	git(t, root, "switch", "-q", "-c", "feat/Auth-Flow")
+	if v := runBuiltin(t, root, "branch-naming", req); len(v) != 0 {
+		t.Fatalf("branch with slug should pass, got %#v", v)
+	}
+}
+
+func TestDoneRequiresVerifiedChecksStatus(t *testing.T) {
+	root := t.TempDir()
+	bin := filepath.Join(root, "fake-pacto")
+	report := `#!/bin/sh
+cat <<'JSON'
+{
+  "plans": [
+    {
+      "state_folder": "current",
+      "slug": "other",
+      "verification": "verified"
+    },
+    {
+      "state_folder": "current",
+      "slug": "auth",
+      "blockers": [],
+      "verification": "partial"
+    }
+  ]
+}
+JSON
+`
+	if err := os.WriteFile(bin, []byte(report), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PACTO_BIN", bin)
+	req := plugins.HookRequest{Command: "move", ProjectRoot: root, Positionals: []string{"current", "auth", "done"}, Plans: []string{"current/auth"}}
+	v := runBuiltin(t, root, "done-requires-verified", req)
+	if len(v) != 1 || !strings.Contains(v[0].Message, "Plan current/auth is partial, not verified") {
+		t.Fatalf("expected deny for partial plan, got %#v", v)
+	}
+	req.Positionals = []string{"current", "other", "done"}
+	if v := runBuiltin(t, root, "done-requires-verified", req); len(v) != 0 {
+		t.Fatalf("verified plan should pass, got %#v", v)
+	}
+	req.Positionals = []string{"current", "auth", "paused"}
+	if v := runBuiltin(t, root, "done-requires-verified", req); len(v) != 0 {
+		t.Fatalf("moves to other states should pass, got %#v", v)
+	}
+}
+
+func TestConventionalCommitsRequireFormatAndSlug(t *testing.T) {
+	root := gitRepo(t)
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+	if err := os.MkdirAll(filepath.Join(plansRoot, "current", "auth-flow"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	req := plugins.HookRequest{Command: "exec", ProjectRoot: root, PlansRoot: plansRoot}
+	if _, err := Install(root, "conventional-commits", InstallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".pacto", "plugins", "conventional-commits", "config.env"), []byte("COMMIT_COUNT=2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, root, "commit", "-q", "--allow-empty", "-m", "feat(auth-flow): add login form")
+	git(t, root, "commit", "-q", "--allow-empty", "-m", "fix: typo")
+	v := runBuiltin(t, root, "conventional-commits", req)
+	if len(v) != 1 || !strings.Contains(v[0].Message, "1 recent commit(s)") || !strings.Contains(v[0].Message, "fix: typo (no plan slug)") {
+		t.Fatalf("expected deny for commit without slug, got %#v", v)
+	}
+	git(t, root, "commit", "-q", "--allow-empty", "-m", "chore: tidy\n\nRefs auth-flow")
+	if v := runBuiltin(t, root, "conventional-commits", req); len(v) != 1 || !strings.Contains(v[0].Message, "fix: typo") {
+		t.Fatalf("expected older commit still checked, got %#v", v)
+	}
+	git(t, root, "commit", "-q", "--allow-empty", "-m", "docs(auth-flow): usage")
+	if v := runBuiltin(t, root, "conventional-commits", req); len(v) != 0 {
+		t.Fatalf("conventional commits with slugs should pass, got %#v", v)
+	}
+}
+
+func runBuiltin(t *testing.T, root, id string, req plugins.HookRequest) []plugins.GuardrailViolation {
+	t.Helper()
+	if _, err := Install(root, id, InstallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	d := plugins.Discover(root)
+	for _, p := range d.Plugins {
+		if p.Manifest.Metadata.ID == id {
+			return plugins.EvaluateGuardrails([]plugins.Plugin{p}, req)
+		}
+	}
+	t.Fatalf("plugin %s not discovered: %v", id, d.Errors)
+	return nil
+}
+
+func gitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	git(t, root, "init", "-q", "-b", "main")
+	git(t, root, "config", "user.email", "dev@example.com")
+	git(t, root, "config", "user.name", "Dev")
+	git(t, root, "commit", "-q", "--allow-empty", "-m", "chore: init")
+	return root
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
@@ -0,0 +1,179 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"pacto/internal/model"
+	"pacto/internal/parser"
+)
+
+type CheckRequest struct {
+	Version     string    `json:"version"`
+	Plugin      string    `json:"plugin"`
+	Check       string    `json:"check"`
+	ProjectRoot string    `json:"project_root"`
+	RepoRoot    string    `json:"repo_root"`
+	Plan        CheckPlan `json:"plan"`
+}
+
+// CheckPlan is the parsed plan sent to status checks.
+type CheckPlan struct {
+	State          string      `json:"state"`
+	Slug           string      `json:"slug"`
+	Dir            string      `json:"dir"`
+	Readme         string      `json:"readme"`
+	PlanDocs       []string    `json:"plan_docs"`
+	DeclaredStatus string      `json:"declared_status"`
+	Phases         []CheckTask `json:"phases"`
+	Tasks          []CheckTask `json:"tasks"`
+	Blockers       []string    `json:"blockers"`
+	Text           string      `json:"text"`
+}
+
+type CheckTask struct {
+	Ref       string `json:"ref"`
+	Text      string `json:"text"`
+	Completed bool   `json:"completed,omitempty"`
+	Progress  int    `json:"progress,omitempty"`
+}
+
+type CheckResponse struct {
+	Claims   []model.ClaimResult `json:"claims"`
+	Warnings []string            `json:"warnings"`
+}
+
+// RunStatusChecks runs every matching status check against plan. Script
+// failures become warnings so one broken check never hides the report.
+func RunStatusChecks(active []Plugin, projectRoot, repoRoot string, plan parser.ParsedPlan) ([]model.ClaimResult, []string) {
+	claims := make([]model.ClaimResult, 0)
+	warnings := make([]string, 0)
+	for _, p := range active {
+		pid := p.Manifest.Metadata.ID
+		for _, c := range p.Manifest.Spec.StatusChecks {
+			if len(c.States) > 0 && !matchesCommand(plan.Ref.State, c.States) {
+				continue
+			}
+			fullID := pid + "/" + c.ID
+			resp, err := runStatusCheck(p, c, projectRoot, repoRoot, plan)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("status check %s: %v", fullID, err))
+				continue
+			}
+			for _, cl := range resp.Claims {
+				cl.Result = strings.ToLower(strings.TrimSpace(cl.Result))
+				if cl.Result != "verified" && cl.Result != "partial" && cl.Result != "unverified" {
+					warnings = append(warnings, fmt.Sprintf("status check %s: invalid claim result %q", fullID, cl.Result))
+					continue
+				}
+				if cl.ClaimType == "" {
+					cl.ClaimType = model.ClaimPlugin
+				}
+				cl.Source = fullID
+				claims = append(claims, cl)
+			}
+			for _, w := range resp.Warnings {
+				if w = strings.TrimSpace(w); w != "" {
+					warnings = append(warnings, fullID+": "+w)
+				}
+			}
+		}
+	}
+	return claims, warnings
+}
+
+// checkEnv is the environment of a status check script. Its names must not
+// match the PACTO_* config overrides, or a check calling back into pacto
+// would reconfigure it; the repo root is PACTO_CHECK_REPO_ROOT for that reason.
+func checkEnv(pluginID, checkID, projectRoot, repoRoot string, plan parser.ParsedPlan) []string {
+	return []string{
+		"PACTO_PLUGIN_ID=" + pluginID,
+		"PACTO_CHECK_ID=" + checkID,
+		"PACTO_PROJECT_ROOT=" + projectRoot,
+		"PACTO_CHECK_REPO_ROOT=" + repoRoot,
+		"PACTO_PLAN_STATE=" + plan.Ref.State,
+		"PACTO_PLAN_SLUG=" + plan.Ref.Slug,
+		"PACTO_PLAN_DIR=" + plan.Ref.Dir,
+		"PACTO_BIN=" + pactoBin(),
+		InHookEnv + "=1",
+	}
+}
+
+func runStatusCheck(p Plugin, c StatusCheck, projectRoot, repoRoot string, plan parser.ParsedPlan) (CheckResponse, error) {
+	pluginID := p.Manifest.Metadata.ID
+	payload, err := json.Marshal(CheckRequest{
+		Version:     ProtocolVersion,
+		Plugin:      pluginID,
+		Check:       c.ID,
+		ProjectRoot: projectRoot,
+		RepoRoot:    repoRoot,
+		Plan:        checkPlan(plan),
+	})
+	if err != nil {
+		return CheckResponse{}, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), scriptTimeout(c.Run.TimeoutMS))
+	defer cancel()
+	cmd, cleanup, err := scriptCommand(ctx, p, filepath.Clean(filepath.Join(p.Dir, c.Run.Script)), projectRoot, checkEnv(pluginID, c.ID, projectRoot, repoRoot, plan))
+	if err != nil {
+		return CheckResponse{}, fmt.Errorf("sandbox: %w", err)
+	}
+	defer cleanup()
+	cmd.Stdin = bytes.NewReader(payload)
+	var outb, errb bytes.Buffer
+	cmd.Stdout = &outb
+	cmd.Stderr = &errb
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return CheckResponse{}, fmt.Errorf("timed out")
+		}
+		if msg := trimOutput(errb.String()); msg != "" {
+			return CheckResponse{}, fmt.Errorf("%v: %s", err, msg)
+		}
+		return CheckResponse{}, err
+	}
+	var resp CheckResponse
+	if out := strings.TrimSpace(outb.String()); out != "" {
+		if err := json.Unmarshal([]byte(out), &resp); err != nil {
+			return CheckResponse{}, fmt.Errorf("invalid JSON response: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+func checkPlan(p parser.ParsedPlan) CheckPlan {
+	out := CheckPlan{
+		State:          p.Ref.State,
+		Slug:           p.Ref.Slug,
+		Dir:            p.Ref.Dir,
+		Readme:         p.Ref.Readme,
+		PlanDocs:       append([]string{}, p.Ref.PlanDocs...),
+		DeclaredStatus: p.DeclaredStatus,
+		Phases:         make([]CheckTask, 0, len(p.Phases)),
+		Tasks:          make([]CheckTask, 0, len(p.Tasks)),
+		Blockers:       append([]string{}, p.BlockerHints...),
+		Text:           p.RawText,
+	}
+	for i, ph := range p.Phases {
+		out.Phases = append(out.Phases, CheckTask{Ref: fmt.Sprintf("%d", i+1), Text: ph.Name, Progress: ph.Progress})
+	}
+	for _, t := range p.Tasks {
+		out.Tasks = append(out.Tasks, CheckTask{Ref: t.StepRef, Text: t.Text, Completed: t.Completed})
+	}
+	return out
+}
+
+func scriptTimeout(ms int) time.Duration {
+	if ms < 500 {
+		ms = 500
+	}
+	if ms > 60000 {
+		ms = 60000
+	}
+	return time.Duration(ms) * time.Millisecond
+}
@@ -0,0 +1,121 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+var commandNameRe = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// reservedCommands are pacto's own commands; plugins cannot shadow them.
+var reservedCommands = map[string]bool{
+	"help": true, "version": true, "status": true, "new": true, "explore": true,
+	"init": true, "install": true, "uninstall": true, "update": true, "exec": true, "move": true,
+	"rename": true, "split": true, "merge": true, "archive": true, "unarchive": true,
+	"undo": true, "log": true, "plugin": true, "config": true,
+}
+
+func IsReservedCommand(name string) bool {
+	return reservedCommands[name]
+}
+
+// CommandEntry is a plugin command and the plugin that provides it.
+type CommandEntry struct {
+	Plugin  Plugin
+	Command PluginCommand
+}
+
+func (e CommandEntry) PluginID() string {
+	return e.Plugin.Manifest.Metadata.ID
+}
+
+// ListCommands returns the commands of the given plugins sorted by name.
+// When two plugins declare the same name the first in load order wins.
+func ListCommands(active []Plugin) []CommandEntry {
+	seen := map[string]bool{}
+	out := make([]CommandEntry, 0)
+	for _, p := range active {
+		for _, c := range p.Manifest.Spec.Commands {
+			if seen[c.Name] {
+				continue
+			}
+			seen[c.Name] = true
+			out = append(out, CommandEntry{Plugin: p, Command: c})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Command.Name < out[j].Command.Name })
+	return out
+}
+
+func FindCommand(active []Plugin, name string) (CommandEntry, bool) {
+	for _, e := range ListCommands(active) {
+		if e.Command.Name == name {
+			return e, true
+		}
+	}
+	return CommandEntry{}, false
+}
+
+// commandConflicts reports plugins declaring a command an earlier plugin in
+// load order already provides.
+func commandConflicts(ordered []Plugin) []error {
+	owner := map[string]string{}
+	var errs []error
+	for _, p := range ordered {
+		id := p.Manifest.Metadata.ID
+		for _, c := range p.Manifest.Spec.Commands {
+			if prev, ok := owner[c.Name]; ok {
+				errs = append(errs, fmt.Errorf("%s: command %q is already provided by plugin %s", id, c.Name, prev))
+				continue
+			}
+			owner[c.Name] = id
+		}
+	}
+	return errs
+}
+
+// RunCommand runs a plugin command with the hook JSON request on stdin and
+// returns the script's exit code. Errors mean the script could not run.
+func RunCommand(e CommandEntry, req HookRequest, stdout, stderr io.Writer) (int, error) {
+	p, c := e.Plugin, e.Command
+	ctx := context.Background()
+	if c.Run.TimeoutMS > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(c.Run.TimeoutMS)*time.Millisecond)
+		defer cancel()
+	}
+	req.Phase = ""
+	payload, err := buildProtocolRequest(e.PluginID(), CLIGuardrail{}, req)
+	if err != nil {
+		return 3, fmt.Errorf("encode request: %w", err)
+	}
+	env := append(scriptEnv(e.PluginID(), req), "PACTO_PLUGIN_COMMAND="+c.Name)
+	cmd, cleanup, err := scriptCommand(ctx, p, filepath.Clean(filepath.Join(p.Dir, c.Run.Script)), req.ProjectRoot, env)
+	if err != nil {
+		return 3, fmt.Errorf("sandbox: %w", err)
+	}
+	defer cleanup()
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return 124, fmt.Errorf("timed out after %s", time.Duration(c.Run.TimeoutMS)*time.Millisecond)
+	}
+	var ee *exec.ExitError
+	if errors.As(err, &ee) {
+		return ee.ExitCode(), nil
+	}
+	if err != nil {
+		return 3, err
+	}
+	return 0, nil
+}
@@ -4,32 +4,117 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+// Defaults for HookRequest.Parallelism and HookRequest.Budget.
+const (
+	DefaultParallelism = 4
+	DefaultBudget      = 30 * time.Second
+)
+
+type hookJob struct {
+	plugin  Plugin
+	g       CLIGuardrail
+	allowed bool
+}
+
+// EvaluateGuardrails runs the guardrails of req.Phase (pre when empty) that
+// match req.Command and returns the failed ones, sorted by plugin and
+// guardrail id. See RunGuardrails.
 func EvaluateGuardrails(active []Plugin, req HookRequest) []GuardrailViolation {
-	violations := make([]GuardrailViolation, 0)
+	results := RunGuardrails(active, req)
+	failed := results[:0]
+	for _, v := range results {
+		if !v.Passed {
+			failed = append(failed, v)
+		}
+	}
+	return failed
+}
+
+// RunGuardrails runs the guardrails of req.Phase (pre when empty) that match
+// req.Command and returns one result per guardrail, passes included, sorted
+// by plugin and guardrail id. Guardrails run concurrently up to
+// req.Parallelism; serial ones run alone, in load order. All of them share
+// req.Budget: guardrails still running when it runs out time out, and
+// unstarted ones fail.
+func RunGuardrails(active []Plugin, req HookRequest) []GuardrailViolation {
+	req.Phase = normalizePhase(req.Phase)
+	jobs := make([]hookJob, 0)
 	for _, p := range active {
 		pid := p.Manifest.Metadata.ID
 		for _, g := range p.Manifest.Spec.CLIGuardrails {
-			if !matchesCommand(req.Command, g.Commands) {
+			if normalizePhase(g.Phase) != req.Phase || !matchesCommand(req.Command, g.Commands) {
 				continue
 			}
-			fullID := pid + "/" + g.ID
-			allowed := req.Allow[fullID] || req.Allow[g.ID]
-			v := runHook(p.Dir, pid, g, req)
-			if v == nil {
+			if req.Protocol != "" && normalizeProtocol(g.Run.Protocol) != req.Protocol {
 				continue
 			}
-			v.Allowed = allowed
-			violations = append(violations, *v)
+			jobs = append(jobs, hookJob{plugin: p, g: g, allowed: req.Allow[pid+"/"+g.ID] || req.Allow[g.ID]})
 		}
 	}
-	sort.Slice(violations, func(i, j int) bool {
+	parallel := req.Parallelism
+	if parallel <= 0 {
+		parallel = DefaultParallelism
+	}
+	budget := req.Budget
+	if budget <= 0 {
+		budget = DefaultBudget
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), budget)
+	defer cancel()
+
+	results := make([]*GuardrailViolation, len(jobs))
+	run := func(i int) {
+		job := jobs[i]
+		if ctx.Err() != nil {
+			results[i] = budgetExhausted(job, req.Phase, budget)
+			return
+		}
+		results[i] = runHook(ctx, job.plugin, job.g, req)
+		if results[i].TimedOut && ctx.Err() != nil {
+			results[i].Message = fmt.Sprintf("guardrail %s stopped: guardrail time budget of %s exhausted", results[i].FullID(), budget)
+		}
+	}
+	for start := 0; start < len(jobs); {
+		if jobs[start].g.Serial {
+			run(start)
+			start++
+			continue
+		}
+		end := start
+		for end < len(jobs) && !jobs[end].g.Serial {
+			end++
+		}
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, parallel)
+		for i := start; i < end; i++ {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				run(i)
+			}(i)
+		}
+		wg.Wait()
+		start = end
+	}
+
+	violations := make([]GuardrailViolation, 0)
+	for i, v := range results {
+		v.Allowed = jobs[i].allowed
+		violations = append(violations, *v)
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
 		if violations[i].PluginID == violations[j].PluginID {
 			return violations[i].GuardrailID < violations[j].GuardrailID
 		}
@@ -38,60 +123,165 @@ func EvaluateGuardrails(active []Plugin, req HookRequest) []GuardrailViolation {
 	return violations
 }
 
-func runHook(pluginDir, pluginID string, g CLIGuardrail, req HookRequest) *GuardrailViolation {
-	scriptPath := filepath.Clean(filepath.Join(pluginDir, g.Run.Script))
-	timeout := g.Run.TimeoutMS
-	if timeout < 500 {
-		timeout = 500
-	}
-	if timeout > 60000 {
-		timeout = 60000
+func budgetExhausted(job hookJob, phase string, budget time.Duration) *GuardrailViolation {
+	v := &GuardrailViolation{
+		PluginID:    job.plugin.Manifest.Metadata.ID,
+		GuardrailID: job.g.ID,
+		Phase:       phase,
+		Script:      job.g.Run.Script,
+		Decision:    DecisionDeny,
+		TimedOut:    true,
+		ExitCode:    124,
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Millisecond)
+	v.Message = fmt.Sprintf("guardrail %s skipped: guardrail time budget of %s exhausted", v.FullID(), budget)
+	return v
+}
+
+func runHook(parent context.Context, p Plugin, g CLIGuardrail, req HookRequest) *GuardrailViolation {
+	pluginID := p.Manifest.Metadata.ID
+	scriptPath := filepath.Clean(filepath.Join(p.Dir, g.Run.Script))
+	ctx, cancel := context.WithTimeout(parent, scriptTimeout(g.Run.TimeoutMS))
 	defer cancel()
-	cmd := exec.CommandContext(ctx, "/bin/sh", scriptPath)
-	cmd.Dir = req.ProjectRoot
-	cmd.Env = append(cmd.Environ(),
-		"PACTO_PLUGIN_ID="+pluginID,
+	env := append(scriptEnv(pluginID, req),
 		"PACTO_GUARDRAIL_ID="+g.ID,
-		"PACTO_COMMAND="+req.Command,
-		"PACTO_PROJECT_ROOT="+req.ProjectRoot,
-		"PACTO_ARGS="+strings.Join(req.Args, " "),
+		"PACTO_HOOK_PHASE="+req.Phase,
+		InHookEnv+"=1",
 	)
-	var outb, errb bytes.Buffer
-	cmd.Stdout = &outb
-	cmd.Stderr = &errb
-	start := time.Now()
-	err := cmd.Run()
-	dur := time.Since(start)
-	if err == nil {
-		return nil
+	if req.Phase != PhasePre {
+		env = append(env, outcomeEnv(req)...)
 	}
+	jsonProtocol := normalizeProtocol(g.Run.Protocol) == ProtocolJSON
 	v := &GuardrailViolation{
 		PluginID:    pluginID,
 		GuardrailID: g.ID,
+		Phase:       req.Phase,
 		Message:     strings.TrimSpace(g.OnFail.Message),
 		Script:      g.Run.Script,
-		Stdout:      trimOutput(outb.String()),
-		Stderr:      trimOutput(errb.String()),
-		Duration:    dur,
+		Decision:    DecisionDeny,
 	}
 	if v.Message == "" {
 		v.Message = fmt.Sprintf("guardrail %s failed", v.FullID())
 	}
+	cmd, cleanup, err := scriptCommand(ctx, p, scriptPath, req.ProjectRoot, env)
+	if err != nil {
+		v.Message = fmt.Sprintf("guardrail %s: sandbox: %v", v.FullID(), err)
+		v.ExitCode = 1
+		return v
+	}
+	defer cleanup()
+	if jsonProtocol {
+		payload, err := buildProtocolRequest(pluginID, g, req)
+		if err != nil {
+			v.Message = fmt.Sprintf("guardrail %s: encode request: %v", v.FullID(), err)
+			v.ExitCode = 1
+			return v
+		}
+		cmd.Stdin = bytes.NewReader(payload)
+	}
+	var outb, errb bytes.Buffer
+	cmd.Stdout = &outb
+	cmd.Stderr = &errb
+	start := time.Now()
+	err = cmd.Run()
+	v.Duration = time.Since(start)
+	v.Stdout = trimOutput(outb.String())
+	v.Stderr = trimOutput(errb.String())
 	if ctx.Err() == context.DeadlineExceeded {
 		v.TimedOut = true
 		v.ExitCode = 124
 		return v
 	}
-	if ee, ok := err.(*exec.ExitError); ok {
-		v.ExitCode = ee.ExitCode()
-	} else {
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			v.ExitCode = ee.ExitCode()
+		} else {
+			v.ExitCode = 1
+		}
+		if jsonProtocol {
+			if resp, perr := parseProtocolResponse(outb.String()); perr == nil {
+				applyProtocolResponse(v, resp)
+				v.Decision = DecisionDeny
+			}
+		}
+		return v
+	}
+	if !jsonProtocol {
+		return passed(v)
+	}
+	resp, perr := parseProtocolResponse(outb.String())
+	if perr != nil {
+		v.Message = fmt.Sprintf("guardrail %s: %v", v.FullID(), perr)
 		v.ExitCode = 1
+		return v
+	}
+	if resp.Decision == DecisionAllow && len(resp.Annotations) == 0 && len(resp.NextActions) == 0 {
+		return passed(v)
 	}
+	applyProtocolResponse(v, resp)
 	return v
 }
 
+// passed marks v as a silent pass: it is audited but never reported.
+func passed(v *GuardrailViolation) *GuardrailViolation {
+	v.Passed = true
+	v.Decision = DecisionAllow
+	v.Message = ""
+	return v
+}
+
+// scriptEnv is the environment shared by guardrail and command scripts.
+func scriptEnv(pluginID string, req HookRequest) []string {
+	return []string{
+		"PACTO_PLUGIN_ID=" + pluginID,
+		"PACTO_COMMAND=" + req.Command,
+		"PACTO_PROJECT_ROOT=" + req.ProjectRoot,
+		"PACTO_ARGS=" + strings.Join(req.Args, " "),
+		"PACTO_LANG=" + req.Language,
+		"PACTO_PLANS_ROOT=" + req.PlansRoot,
+		"PACTO_PLANS=" + strings.Join(req.Plans, " "),
+		"PACTO_BIN=" + pactoBin(),
+	}
+}
+
+// InHookEnv is set for every hook script so pacto commands the script runs
+// skip guardrails instead of recursing into them.
+const InHookEnv = "PACTO_IN_HOOK"
+
+// pactoBin is the binary hook scripts should call back into: $PACTO_BIN when
+// set, otherwise the running executable.
+func pactoBin() string {
+	if bin := strings.TrimSpace(os.Getenv("PACTO_BIN")); bin != "" {
+		return bin
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return "pacto"
+	}
+	return exe
+}
+
+func applyProtocolResponse(v *GuardrailViolation, resp ProtocolResponse) {
+	v.Decision = resp.Decision
+	if resp.Message != "" {
+		v.Message = resp.Message
+	}
+	v.Annotations = resp.Annotations
+	v.NextActions = resp.NextActions
+}
+
+// outcomeEnv exposes the finished command to post and onError hooks. Touched
+// files are newline-separated and relative to the project root when inside it.
+func outcomeEnv(req HookRequest) []string {
+	touched := relativeTouched(req.ProjectRoot, req.Outcome.Touched)
+	return []string{
+		"PACTO_EXIT_CODE=" + strconv.Itoa(req.Outcome.ExitCode),
+		"PACTO_TOUCHED_FILES=" + strings.Join(touched, "\n"),
+		"PACTO_FROM_STATE=" + req.Outcome.FromState,
+		"PACTO_TO_STATE=" + req.Outcome.ToState,
+		"PACTO_SLUG=" + req.Outcome.Slug,
+	}
+}
+
 func matchesCommand(command string, allowed []string) bool {
 	if len(allowed) == 0 {
 		return true
@@ -111,3 +301,11 @@ func trimOutput(s string) string {
 	}
 	return s
 }
+
+func normalizeProtocol(v string) string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if v == "" {
+		return ProtocolEnv
+	}
+	return v
+}
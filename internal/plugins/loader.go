@@ -1,10 +1,10 @@
 package plugins
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 )
 
@@ -36,6 +36,13 @@ func Discover(projectRoot string) DiscoverResult {
 		}
 		m, err := parseManifest(manifestPath)
 		if err != nil {
+			var schemaErrs SchemaErrors
+			if errors.As(err, &schemaErrs) {
+				for _, fe := range schemaErrs {
+					result.Errors = append(result.Errors, fmt.Errorf("%s: plugin.yaml %w", ent.Name(), fe))
+				}
+				continue
+			}
 			result.Errors = append(result.Errors, fmt.Errorf("%s: parse manifest: %w", ent.Name(), err))
 			continue
 		}
@@ -53,14 +60,7 @@ func Discover(projectRoot string) DiscoverResult {
 		result.Plugins = append(result.Plugins, Plugin{Dir: dir, Manifest: m})
 	}
 
-	sort.Slice(result.Plugins, func(i, j int) bool {
-		pi := result.Plugins[i].Manifest.Metadata.Priority
-		pj := result.Plugins[j].Manifest.Metadata.Priority
-		if pi == pj {
-			return result.Plugins[i].Manifest.Metadata.ID < result.Plugins[j].Manifest.Metadata.ID
-		}
-		return pi < pj
-	})
+	result.Plugins, _ = orderPlugins(result.Plugins)
 	return result
 }
 
@@ -85,15 +85,34 @@ func LoadActive(projectRoot string) ([]Plugin, []error) {
 			errs = append(errs, fmt.Errorf("enabled plugin not found: %s", id))
 			continue
 		}
+		digest, approved := cfg.Approved[id]
+		if approved && digest != PluginPermissions(p).Digest() {
+			errs = append(errs, fmt.Errorf("plugin %s permissions changed since they were approved; run `pacto plugin enable %s` to review them", id, id))
+			continue
+		}
+		if !approved && p.Manifest.Spec.Sandbox != nil {
+			errs = append(errs, fmt.Errorf("plugin %s declares a sandbox policy that was never approved; run `pacto plugin enable %s` to review it", id, id))
+			continue
+		}
 		active = append(active, p)
 	}
-	sort.Slice(active, func(i, j int) bool {
-		pi := active[i].Manifest.Metadata.Priority
-		pj := active[j].Manifest.Metadata.Priority
-		if pi == pj {
-			return active[i].Manifest.Metadata.ID < active[j].Manifest.Metadata.ID
+	active, resolveErrs := Resolve(active)
+	return active, append(errs, resolveErrs...)
+}
+
+// Unapproved lists active plugins enabled without an approval record, as in
+// configs written before approvals existed or edited by hand. They run
+// unrestricted; sandboxed ones are refused by LoadActive instead.
+func Unapproved(projectRoot string, active []Plugin) []string {
+	cfg, err := ReadActiveConfig(projectRoot)
+	if err != nil {
+		return nil
+	}
+	out := make([]string, 0)
+	for _, p := range active {
+		if _, ok := cfg.Approved[p.Manifest.Metadata.ID]; !ok {
+			out = append(out, p.Manifest.Metadata.ID)
 		}
-		return pi < pj
-	})
-	return active, errs
+	}
+	return out
 }
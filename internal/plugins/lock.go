@@ -0,0 +1,263 @@
+package plugins
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"pacto/internal/fsutil"
+)
+
+// LockFileName is the lockfile under .pacto pinning installed plugins.
+const LockFileName = "plugins.lock.json"
+
+// localConfigFile is generated from config.env.example and edited per
+// workspace, so it is never pinned, overwritten or removed.
+const localConfigFile = "config.env"
+
+type Lockfile struct {
+	Version int         `json:"version"`
+	Plugins []LockEntry `json:"plugins"`
+}
+
+// LockEntry pins one installed plugin: where it came from and the SHA-256 of
+// every file installed from it. Tarball sources also record the SHA-256 of
+// the archive itself, comparable with a published checksum.
+type LockEntry struct {
+	ID            string            `json:"id"`
+	Version       string            `json:"version"`
+	Source        string            `json:"source"`
+	SourceType    string            `json:"source_type"`
+	SHA256        string            `json:"sha256"`
+	ArchiveSHA256 string            `json:"archive_sha256,omitempty"`
+	Files         map[string]string `json:"files"`
+	InstalledAt   string            `json:"installed_at"`
+}
+
+// Drift describes local changes to a pinned plugin, or a change of the
+// tarball it was installed from.
+type Drift struct {
+	ID             string   `json:"id"`
+	Missing        bool     `json:"missing,omitempty"`
+	ArchiveChanged bool     `json:"archive_changed,omitempty"`
+	Modified       []string `json:"modified,omitempty"`
+	Added          []string `json:"added,omitempty"`
+	Removed        []string `json:"removed,omitempty"`
+}
+
+func (d Drift) Clean() bool {
+	return !d.Missing && !d.ArchiveChanged && !d.FilesChanged()
+}
+
+// FilesChanged reports edits to the installed plugin directory.
+func (d Drift) FilesChanged() bool {
+	return len(d.Modified) > 0 || len(d.Added) > 0 || len(d.Removed) > 0
+}
+
+func LockPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".pacto", LockFileName)
+}
+
+func ReadLock(projectRoot string) (Lockfile, error) {
+	b, err := os.ReadFile(LockPath(projectRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Lockfile{Version: 1}, nil
+		}
+		return Lockfile{}, err
+	}
+	var lf Lockfile
+	if err := json.Unmarshal(b, &lf); err != nil {
+		return Lockfile{}, err
+	}
+	if lf.Version == 0 {
+		lf.Version = 1
+	}
+	return lf, nil
+}
+
+func WriteLock(projectRoot string, lf Lockfile) error {
+	if lf.Version == 0 {
+		lf.Version = 1
+	}
+	sort.Slice(lf.Plugins, func(i, j int) bool { return lf.Plugins[i].ID < lf.Plugins[j].ID })
+	b, err := json.MarshalIndent(lf, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := LockPath(projectRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0o775); err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, append(b, '\n'), 0o664)
+}
+
+func (lf Lockfile) Get(id string) (LockEntry, bool) {
+	for _, e := range lf.Plugins {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return LockEntry{}, false
+}
+
+func (lf *Lockfile) Put(entry LockEntry) {
+	for i, e := range lf.Plugins {
+		if e.ID == entry.ID {
+			lf.Plugins[i] = entry
+			return
+		}
+	}
+	lf.Plugins = append(lf.Plugins, entry)
+}
+
+// PinDir hashes every file under dir except the local config.env. The
+// overall digest covers the sorted relative paths and their file digests.
+func PinDir(dir string) (string, map[string]string, error) {
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == localConfigFile {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(b)
+		files[rel] = hex.EncodeToString(sum[:])
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return digestFiles(files), files, nil
+}
+
+// FileSHA256 is the hex SHA-256 of the file at path.
+func FileSHA256(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func digestFiles(files map[string]string) string {
+	keys := make([]string, 0, len(files))
+	for k := range files {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k + "\x00" + files[k] + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// VerifyEntry compares the installed plugin directory with its pin and, for
+// tarball sources still present on disk, the archive with its pinned digest.
+func VerifyEntry(projectRoot string, e LockEntry) (Drift, error) {
+	d := Drift{ID: e.ID}
+	if e.SourceType == SourceTarball && e.ArchiveSHA256 != "" {
+		src := filepath.FromSlash(e.Source)
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(projectRoot, src)
+		}
+		if sum, err := FileSHA256(src); err == nil {
+			d.ArchiveChanged = sum != e.ArchiveSHA256
+		} else if !os.IsNotExist(err) {
+			return d, err
+		}
+	}
+	dir := filepath.Join(projectRoot, ".pacto", "plugins", e.ID)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		d.Missing = true
+		return d, nil
+	}
+	sum, files, err := PinDir(dir)
+	if err != nil {
+		return d, err
+	}
+	if sum == e.SHA256 {
+		return d, nil
+	}
+	for rel, h := range files {
+		pinned, ok := e.Files[rel]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, rel)
+		case pinned != h:
+			d.Modified = append(d.Modified, rel)
+		}
+	}
+	for rel := range e.Files {
+		if _, ok := files[rel]; !ok {
+			d.Removed = append(d.Removed, rel)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Modified)
+	sort.Strings(d.Removed)
+	return d, nil
+}
+
+// DiffLines returns a minimal line diff of two texts as "- "/"+ " lines.
+func DiffLines(before, after string) []string {
+	a := strings.Split(strings.TrimRight(before, "\n"), "\n")
+	b := strings.Split(strings.TrimRight(after, "\n"), "\n")
+	if before == "" {
+		a = nil
+	}
+	if after == "" {
+		b = nil
+	}
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	out := make([]string, 0)
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			out = append(out, "- "+a[i])
+			i++
+		default:
+			out = append(out, "+ "+b[j])
+			j++
+		}
+	}
+	return out
+}
@@ -0,0 +1,130 @@
+package plugins
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Migration is the result of upgrading one manifest to a newer apiVersion.
+type Migration struct {
+	From   string
+	To     string
+	Before []byte
+	After  []byte
+	Notes  []string
+}
+
+func (m Migration) Changed() bool {
+	return !bytes.Equal(m.Before, m.After)
+}
+
+// MigrateManifest upgrades a pacto/v1alpha1 manifest to pacto/v1beta1,
+// keeping comments and key order. v1beta1 requires a semver
+// metadata.version and only accepts canonical phase names, so both are
+// rewritten; the result is validated against pluginDir before returning.
+func MigrateManifest(pluginDir string) (Migration, error) {
+	path := filepath.Join(pluginDir, "plugin.yaml")
+	before, err := os.ReadFile(path)
+	if err != nil {
+		return Migration{}, err
+	}
+	mig := Migration{Before: before, After: before, To: ManifestAPIVersionV1Beta1}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(before, &doc); err != nil {
+		return Migration{}, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return Migration{}, fmt.Errorf("manifest is not a mapping")
+	}
+	root := doc.Content[0]
+	api := mappingValue(root, "apiVersion")
+	if api == nil {
+		return Migration{}, fmt.Errorf("apiVersion is missing")
+	}
+	mig.From = strings.TrimSpace(api.Value)
+	switch mig.From {
+	case ManifestAPIVersionV1Beta1:
+		return mig, nil
+	case ManifestAPIVersion:
+	default:
+		return Migration{}, fmt.Errorf("cannot migrate apiVersion %q", mig.From)
+	}
+	if _, err := parseManifestBytes(before); err != nil {
+		return Migration{}, fmt.Errorf("fix the %s manifest before migrating: %w", ManifestAPIVersion, err)
+	}
+	api.Value = ManifestAPIVersionV1Beta1
+
+	meta := mappingValue(root, "metadata")
+	version := mappingValue(meta, "version")
+	switch {
+	case version == nil:
+		meta.Content = append(meta.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "version"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "0.1.0"})
+		mig.Notes = append(mig.Notes, "metadata.version was missing; set to 0.1.0")
+	default:
+		v, err := parseSemver(version.Value)
+		if err != nil {
+			return Migration{}, fmt.Errorf("metadata.version %q is not a semantic version; fix it before migrating", version.Value)
+		}
+		if canonical := v.String(); canonical != strings.TrimPrefix(version.Value, "v") {
+			mig.Notes = append(mig.Notes, fmt.Sprintf("metadata.version %q normalized to %q", version.Value, canonical))
+			version.Value = canonical
+		}
+		version.Tag = "!!str"
+		version.Style = 0
+	}
+
+	if guardrails := mappingValue(mappingValue(root, "spec"), "cliGuardrails"); guardrails != nil {
+		for _, g := range guardrails.Content {
+			phase := mappingValue(g, "phase")
+			if phase == nil {
+				continue
+			}
+			if canonical := normalizePhase(phase.Value); canonical != phase.Value {
+				id := ""
+				if n := mappingValue(g, "id"); n != nil {
+					id = n.Value
+				}
+				mig.Notes = append(mig.Notes, fmt.Sprintf("spec.cliGuardrails[%s].phase %q renamed to %q", id, phase.Value, canonical))
+				phase.Value = canonical
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return Migration{}, err
+	}
+	if err := enc.Close(); err != nil {
+		return Migration{}, err
+	}
+	mig.After = buf.Bytes()
+	m, err := parseManifestBytes(mig.After)
+	if err != nil {
+		return Migration{}, fmt.Errorf("migrated manifest is invalid: %w", err)
+	}
+	if err := validateManifest(m, pluginDir); err != nil {
+		return Migration{}, fmt.Errorf("migrated manifest is invalid: %w", err)
+	}
+	return mig, nil
+}
+
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -14,9 +14,22 @@ func parseManifest(path string) (Manifest, error) {
 	if err != nil {
 		return Manifest{}, err
 	}
+	return parseManifestBytes(b)
+}
 
+func parseManifestBytes(b []byte) (Manifest, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return Manifest{}, err
+	}
+	if len(doc.Content) == 0 {
+		return Manifest{}, fmt.Errorf("empty manifest")
+	}
+	if errs := validateSchema(&doc); len(errs) > 0 {
+		return Manifest{}, errs
+	}
 	var out Manifest
-	if err := yaml.Unmarshal(b, &out); err != nil {
+	if err := doc.Decode(&out); err != nil {
 		return Manifest{}, err
 	}
 	normalizeManifest(&out)
@@ -31,15 +44,50 @@ func normalizeManifest(m *Manifest) {
 	m.Kind = strings.TrimSpace(m.Kind)
 	m.Metadata.ID = strings.TrimSpace(m.Metadata.ID)
 	m.Metadata.Version = strings.TrimSpace(m.Metadata.Version)
+	m.Spec.Requires.Pacto = strings.TrimSpace(m.Spec.Requires.Pacto)
+	if sb := m.Spec.Sandbox; sb != nil {
+		sb.ProjectRoot = strings.ToLower(strings.TrimSpace(sb.ProjectRoot))
+		if sb.ProjectRoot == "" {
+			sb.ProjectRoot = SandboxReadOnly
+		}
+		for i := range sb.Env {
+			sb.Env[i] = strings.TrimSpace(sb.Env[i])
+		}
+	}
+	for i := range m.Spec.DependsOn {
+		d := &m.Spec.DependsOn[i]
+		d.ID = strings.ToLower(strings.TrimSpace(d.ID))
+		d.Version = strings.TrimSpace(d.Version)
+	}
 	for i := range m.Spec.CLIGuardrails {
 		g := &m.Spec.CLIGuardrails[i]
 		g.ID = strings.TrimSpace(g.ID)
+		g.Phase = normalizePhase(g.Phase)
+		g.Run.Protocol = normalizeProtocol(g.Run.Protocol)
 		g.Run.Script = strings.TrimSpace(g.Run.Script)
 		g.OnFail.Message = strings.TrimSpace(g.OnFail.Message)
 		if g.Run.TimeoutMS <= 0 {
 			g.Run.TimeoutMS = 5000
 		}
 	}
+	for i := range m.Spec.StatusChecks {
+		c := &m.Spec.StatusChecks[i]
+		c.ID = strings.TrimSpace(c.ID)
+		c.Run.Script = strings.TrimSpace(c.Run.Script)
+		if c.Run.TimeoutMS <= 0 {
+			c.Run.TimeoutMS = 5000
+		}
+	}
+	for i := range m.Spec.Commands {
+		c := &m.Spec.Commands[i]
+		c.Name = strings.ToLower(strings.TrimSpace(c.Name))
+		c.Summary = strings.TrimSpace(c.Summary)
+		c.Usage = strings.TrimSpace(c.Usage)
+		c.Run.Script = strings.TrimSpace(c.Run.Script)
+		if c.Usage == "" {
+			c.Usage = "pacto " + c.Name + " [args]"
+		}
+	}
 	for i := range m.Spec.AgentGuardrails {
 		g := &m.Spec.AgentGuardrails[i]
 		g.ID = strings.TrimSpace(g.ID)
@@ -48,8 +96,8 @@ func normalizeManifest(m *Manifest) {
 }
 
 func validateManifest(m Manifest, pluginDir string) error {
-	if m.APIVersion != ManifestAPIVersion {
-		return fmt.Errorf("apiVersion must be %q", ManifestAPIVersion)
+	if m.APIVersion != ManifestAPIVersion && m.APIVersion != ManifestAPIVersionV1Beta1 {
+		return fmt.Errorf("apiVersion must be one of %s", strings.Join(ManifestAPIVersions, ", "))
 	}
 	if m.Kind != ManifestKind {
 		return fmt.Errorf("kind must be %q", ManifestKind)
@@ -57,10 +105,45 @@ func validateManifest(m Manifest, pluginDir string) error {
 	if strings.TrimSpace(m.Metadata.ID) == "" {
 		return fmt.Errorf("metadata.id is required")
 	}
+	if m.APIVersion == ManifestAPIVersion && (m.Spec.Requires.Pacto != "" || len(m.Spec.DependsOn) > 0) {
+		return fmt.Errorf("spec.requires and spec.dependsOn need apiVersion %s (run `pacto plugin migrate %s`)", ManifestAPIVersionV1Beta1, m.Metadata.ID)
+	}
+	if m.Spec.Requires.Pacto != "" {
+		if _, err := parseConstraint(m.Spec.Requires.Pacto); err != nil {
+			return fmt.Errorf("spec.requires.pacto: %w", err)
+		}
+	}
+	for i, d := range m.Spec.DependsOn {
+		if d.ID == "" {
+			return fmt.Errorf("spec.dependsOn[%d].id is required", i)
+		}
+		if d.ID == strings.ToLower(m.Metadata.ID) {
+			return fmt.Errorf("spec.dependsOn[%d]: plugin cannot depend on itself", i)
+		}
+		if d.Version != "" {
+			if _, err := parseConstraint(d.Version); err != nil {
+				return fmt.Errorf("spec.dependsOn[%s].version: %w", d.ID, err)
+			}
+		}
+	}
+	if sb := m.Spec.Sandbox; sb != nil {
+		if sb.ProjectRoot != SandboxReadOnly && sb.ProjectRoot != SandboxReadWrite {
+			return fmt.Errorf("spec.sandbox.projectRoot must be %s or %s", SandboxReadOnly, SandboxReadWrite)
+		}
+		if sb.CPUSeconds < 0 || sb.MemoryMB < 0 {
+			return fmt.Errorf("spec.sandbox limits must not be negative")
+		}
+	}
 	for _, g := range m.Spec.CLIGuardrails {
 		if strings.TrimSpace(g.ID) == "" {
 			return fmt.Errorf("spec.cliGuardrails[].id is required")
 		}
+		if g.Phase != PhasePre && g.Phase != PhasePost && g.Phase != PhaseOnError {
+			return fmt.Errorf("spec.cliGuardrails[%s].phase must be one of pre|post|onError", g.ID)
+		}
+		if p := normalizeProtocol(g.Run.Protocol); p != ProtocolEnv && p != ProtocolJSON {
+			return fmt.Errorf("spec.cliGuardrails[%s].run.protocol must be env or json", g.ID)
+		}
 		if strings.TrimSpace(g.Run.Script) == "" {
 			return fmt.Errorf("spec.cliGuardrails[%s].run.script is required", g.ID)
 		}
@@ -72,6 +155,44 @@ func validateManifest(m Manifest, pluginDir string) error {
 			return fmt.Errorf("script not found: %s", g.Run.Script)
 		}
 	}
+	for _, c := range m.Spec.StatusChecks {
+		if c.ID == "" {
+			return fmt.Errorf("spec.statusChecks[].id is required")
+		}
+		if c.Run.Script == "" {
+			return fmt.Errorf("spec.statusChecks[%s].run.script is required", c.ID)
+		}
+		scriptPath := filepath.Clean(filepath.Join(pluginDir, c.Run.Script))
+		if !strings.HasPrefix(scriptPath, filepath.Clean(pluginDir)+string(os.PathSeparator)) {
+			return fmt.Errorf("script path escapes plugin directory: %s", c.Run.Script)
+		}
+		if _, err := os.Stat(scriptPath); err != nil {
+			return fmt.Errorf("script not found: %s", c.Run.Script)
+		}
+	}
+	names := map[string]bool{}
+	for _, c := range m.Spec.Commands {
+		if !commandNameRe.MatchString(c.Name) {
+			return fmt.Errorf("spec.commands[%s].name must match %s", c.Name, commandNameRe)
+		}
+		if IsReservedCommand(c.Name) {
+			return fmt.Errorf("spec.commands[%s]: %q is a built-in pacto command", c.Name, c.Name)
+		}
+		if names[c.Name] {
+			return fmt.Errorf("spec.commands[%s] is declared twice", c.Name)
+		}
+		names[c.Name] = true
+		if c.Run.Script == "" {
+			return fmt.Errorf("spec.commands[%s].run.script is required", c.Name)
+		}
+		scriptPath := filepath.Clean(filepath.Join(pluginDir, c.Run.Script))
+		if !strings.HasPrefix(scriptPath, filepath.Clean(pluginDir)+string(os.PathSeparator)) {
+			return fmt.Errorf("script path escapes plugin directory: %s", c.Run.Script)
+		}
+		if _, err := os.Stat(scriptPath); err != nil {
+			return fmt.Errorf("script not found: %s", c.Run.Script)
+		}
+	}
 	for _, g := range m.Spec.AgentGuardrails {
 		if strings.TrimSpace(g.ID) == "" {
 			return fmt.Errorf("spec.agentGuardrails[].id is required")
@@ -89,3 +210,16 @@ func validateManifest(m Manifest, pluginDir string) error {
 	}
 	return nil
 }
+
+func normalizePhase(v string) string {
+	v = strings.TrimSpace(v)
+	switch strings.ToLower(v) {
+	case "", PhasePre:
+		return PhasePre
+	case PhasePost:
+		return PhasePost
+	case strings.ToLower(PhaseOnError), "on-error":
+		return PhaseOnError
+	}
+	return v
+}
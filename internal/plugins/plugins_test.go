@@ -1,13 +1,28 @@
 package plugins
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"pacto/internal/config"
+	"pacto/internal/model"
+	"pacto/internal/parser"
 )
 
+func TestMain(m *testing.M) {
+	// Sandboxed scripts re-execute this test binary as the sandbox helper.
+	RunSandboxHelper()
+	os.Exit(m.Run())
+}
+
 func TestDiscoverAndLoadActive(t *testing.T) {
 	root := t.TempDir()
 	writePlugin(t, root, pluginSpec{
@@ -27,6 +42,9 @@ func TestDiscoverAndLoadActive(t *testing.T) {
 	if len(active) != 1 {
 		t.Fatalf("expected 1 active plugin, got %d", len(active))
 	}
+	if got := Unapproved(root, active); len(got) != 1 || got[0] != "acme" {
+		t.Fatalf("expected acme reported as unapproved, got %v", got)
+	}
 	contrib := CollectAgentContributions(active, "codex", "exec")
 	if len(contrib) != 1 {
 		t.Fatalf("expected 1 contribution, got %d", len(contrib))
@@ -161,6 +179,633 @@ func TestEvaluateGuardrailsSkipsNonMatchingCommand(t *testing.T) {
 	}
 }
 
+func TestEvaluateGuardrailsFiltersByPhase(t *testing.T) {
+	root := t.TempDir()
+	pluginDir := filepath.Join(root, ".pacto", "plugins", "acme")
+	if err := os.MkdirAll(filepath.Join(pluginDir, "scripts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginDir, "scripts", "fail.sh"), []byte("#!/bin/sh\nexit 2\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	m := Manifest{
+		APIVersion: ManifestAPIVersion,
+		Kind:       ManifestKind,
+		Metadata:   Metadata{ID: "acme"},
+		Spec:       Spec{CLIGuardrails: []CLIGuardrail{{ID: "after", Phase: "on-error", Commands: []string{"move"}, Run: RunSpec{Script: "scripts/fail.sh"}}}},
+	}
+	normalizeManifest(&m)
+	if m.Spec.CLIGuardrails[0].Phase != PhaseOnError {
+		t.Fatalf("expected normalized onError phase, got %q", m.Spec.CLIGuardrails[0].Phase)
+	}
+	if err := validateManifest(m, pluginDir); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	active := []Plugin{{Dir: pluginDir, Manifest: m}}
+	if v := EvaluateGuardrails(active, HookRequest{Command: "move", ProjectRoot: root}); len(v) != 0 {
+		t.Fatalf("expected onError hook skipped in pre phase, got %#v", v)
+	}
+	v := EvaluateGuardrails(active, HookRequest{Command: "move", ProjectRoot: root, Phase: PhaseOnError, Outcome: Outcome{ExitCode: 3}})
+	if len(v) != 1 || v[0].Phase != PhaseOnError {
+		t.Fatalf("expected onError hook failure, got %#v", v)
+	}
+
+	m.Spec.CLIGuardrails[0].Phase = "later"
+	if err := validateManifest(m, pluginDir); err == nil || !strings.Contains(err.Error(), "pre|post|onError") {
+		t.Fatalf("expected phase validation error, got %v", err)
+	}
+}
+
+func TestEvaluateGuardrailsJSONProtocol(t *testing.T) {
+	root := t.TempDir()
+	pluginDir := filepath.Join(root, ".pacto", "plugins", "acme")
+	if err := os.MkdirAll(filepath.Join(pluginDir, "scripts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\ncat > \"$PACTO_PROJECT_ROOT/request.json\"\n" +
+		`echo '{"version":"pacto.hook/v1","decision":"warn","message":"ticket missing","annotations":[{"plan":"current/auth","key":"jira","value":"PRJ-1"},{"key":"owner","value":"qa"}],"next_actions":[{"plan":"current/auth","text":"link ticket"},{"text":"sync tracker"}]}'` + "\n"
+	if err := os.WriteFile(filepath.Join(pluginDir, "scripts", "hook.sh"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginDir, "scripts", "bad.sh"), []byte("#!/bin/sh\necho not-json\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	m := Manifest{
+		APIVersion: ManifestAPIVersion,
+		Kind:       ManifestKind,
+		Metadata:   Metadata{ID: "acme"},
+		Spec: Spec{CLIGuardrails: []CLIGuardrail{
+			{ID: "tracker", Commands: []string{"move"}, Run: RunSpec{Script: "scripts/hook.sh", Protocol: ProtocolJSON}},
+			{ID: "broken", Commands: []string{"status"}, Run: RunSpec{Script: "scripts/bad.sh", Protocol: ProtocolJSON}},
+		}},
+	}
+	active := []Plugin{{Dir: pluginDir, Manifest: m}}
+	req := HookRequest{
+		Command:     "move",
+		Args:        []string{"current", "auth", "done", "--reason", "shipped it"},
+		Flags:       map[string]string{"reason": "shipped it"},
+		Positionals: []string{"current", "auth", "done"},
+		ProjectRoot: root,
+		Plans:       []string{"current/auth"},
+	}
+	v := EvaluateGuardrails(active, req)
+	if len(v) != 1 || v[0].Decision != DecisionWarn || v[0].Message != "ticket missing" {
+		t.Fatalf("expected warn decision, got %#v", v)
+	}
+	b, err := os.ReadFile(filepath.Join(root, "request.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got ProtocolRequest
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("invalid request %q: %v", string(b), err)
+	}
+	if got.Version != ProtocolVersion || got.Phase != PhasePre || got.Flags["reason"] != "shipped it" || len(got.Args) != 5 || got.Plans[0] != "current/auth" {
+		t.Fatalf("unexpected request: %#v", got)
+	}
+
+	rep := model.StatusReport{Plans: []model.PlanStatus{{StateFolder: "current", Slug: "auth"}}}
+	MergeIntoReport(&rep, v)
+	if rep.Plans[0].Annotations["jira"] != "PRJ-1" || rep.Annotations["owner"] != "qa" {
+		t.Fatalf("expected annotations merged, got %#v", rep)
+	}
+	if len(rep.Plans[0].NextActions) != 1 || len(rep.NextActions) != 1 || rep.NextActions[0] != "sync tracker" {
+		t.Fatalf("expected next actions merged, got %#v", rep)
+	}
+
+	v = EvaluateGuardrails(active, HookRequest{Command: "status", ProjectRoot: root, Protocol: ProtocolJSON})
+	if len(v) != 1 || v[0].Decision != DecisionDeny || !strings.Contains(v[0].Message, "invalid JSON response") {
+		t.Fatalf("expected deny for invalid response, got %#v", v)
+	}
+	if v := EvaluateGuardrails(active, HookRequest{Command: "status", ProjectRoot: root, Protocol: ProtocolEnv}); len(v) != 0 {
+		t.Fatalf("expected json guardrail skipped for env protocol, got %#v", v)
+	}
+}
+
+func TestRunStatusChecksReturnsClaimsAndWarnings(t *testing.T) {
+	root := t.TempDir()
+	pluginDir := filepath.Join(root, ".pacto", "plugins", "acme")
+	if err := os.MkdirAll(filepath.Join(pluginDir, "scripts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\n" +
+		"grep -q '\"slug\":\"schema-v2\"' || exit 4\n" +
+		`echo '{"claims":[{"source_text":"migration for users table","evidence":"db/migrations","result":"unverified"},{"result":"maybe"}],"warnings":["schema change without migration"]}'` + "\n"
+	if err := os.WriteFile(filepath.Join(pluginDir, "scripts", "migrations.sh"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginDir, "scripts", "broken.sh"), []byte("#!/bin/sh\nexit 3\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	m := Manifest{
+		APIVersion: ManifestAPIVersion,
+		Kind:       ManifestKind,
+		Metadata:   Metadata{ID: "acme"},
+		Spec: Spec{StatusChecks: []StatusCheck{
+			{ID: "migrations", States: []string{"current"}, Run: RunSpec{Script: "scripts/migrations.sh"}},
+			{ID: "broken", Run: RunSpec{Script: "scripts/broken.sh"}},
+		}},
+	}
+	if err := validateManifest(m, pluginDir); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	active := []Plugin{{Dir: pluginDir, Manifest: m}}
+	plan := parser.ParsedPlan{Ref: model.PlanRef{State: "current", Slug: "schema-v2"}}
+	claims, warnings := RunStatusChecks(active, root, root, plan)
+	if len(claims) != 1 || claims[0].Source != "acme/migrations" || claims[0].ClaimType != model.ClaimPlugin || claims[0].Result != "unverified" {
+		t.Fatalf("unexpected claims: %#v", claims)
+	}
+	joined := strings.Join(warnings, "\n")
+	if !strings.Contains(joined, "invalid claim result \"maybe\"") || !strings.Contains(joined, "acme/migrations: schema change without migration") || !strings.Contains(joined, "status check acme/broken") {
+		t.Fatalf("unexpected warnings: %q", joined)
+	}
+
+	plan.Ref.State = "done"
+	claims, warnings = RunStatusChecks(active, root, root, plan)
+	if len(claims) != 0 || len(warnings) != 1 {
+		t.Fatalf("expected state-filtered check skipped, got %#v %#v", claims, warnings)
+	}
+}
+
+func TestStageSourceTarballAndSyncInstall(t *testing.T) {
+	srcRoot := t.TempDir()
+	writePlugin(t, srcRoot, pluginSpec{dir: "acme", id: "acme"})
+	pluginDir := filepath.Join(srcRoot, ".pacto", "plugins", "acme")
+	if err := os.WriteFile(filepath.Join(pluginDir, "config.env.example"), []byte("TOKEN=\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tarball := filepath.Join(t.TempDir(), "acme.tar.gz")
+	writeTarball(t, tarball, pluginDir, "acme-0.1.0/")
+
+	st, err := StageSource(tarball)
+	if err != nil {
+		t.Fatalf("stage tarball: %v", err)
+	}
+	defer st.Close()
+	if st.SourceType != SourceTarball || st.Manifest.Metadata.ID != "acme" {
+		t.Fatalf("unexpected staged plugin: %#v", st)
+	}
+
+	root := t.TempDir()
+	res, err := SyncInstall(root, st, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	installed := filepath.Join(root, ".pacto", "plugins", "acme")
+	if _, err := os.Stat(filepath.Join(installed, "config.env")); err != nil {
+		t.Fatalf("expected config.env from example: %v", err)
+	}
+	if len(res.Created) == 0 {
+		t.Fatalf("expected created files")
+	}
+	if _, err := SyncInstall(root, st, false); !errors.Is(err, ErrAlreadyInstalled) {
+		t.Fatalf("expected ErrAlreadyInstalled, got %v", err)
+	}
+	srcSum, _, err := PinDir(st.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dstSum, _, err := PinDir(installed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srcSum != dstSum {
+		t.Fatalf("installed digest %s differs from source %s", dstSum, srcSum)
+	}
+
+	evil := filepath.Join(t.TempDir(), "evil.tgz")
+	writeTarball(t, evil, pluginDir, "../")
+	if _, err := StageSource(evil); err == nil || !strings.Contains(err.Error(), "escapes") {
+		t.Fatalf("expected path traversal error, got %v", err)
+	}
+}
+
+func TestVerifyEntryDetectsSwappedTarball(t *testing.T) {
+	srcRoot := t.TempDir()
+	writePlugin(t, srcRoot, pluginSpec{dir: "acme", id: "acme"})
+	pluginDir := filepath.Join(srcRoot, ".pacto", "plugins", "acme")
+	tarball := filepath.Join(t.TempDir(), "acme.tar.gz")
+	writeTarball(t, tarball, pluginDir, "acme-0.1.0/")
+
+	st, err := StageSource(tarball)
+	if err != nil {
+		t.Fatalf("stage tarball: %v", err)
+	}
+	defer st.Close()
+	root := t.TempDir()
+	if _, err := SyncInstall(root, st, false); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := PinInstalled(root, "acme", "0.1.0", tarball, SourceTarball, st.ArchiveSHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := FileSHA256(tarball)
+	if entry.ArchiveSHA256 != want {
+		t.Fatalf("archive digest %q, want %q", entry.ArchiveSHA256, want)
+	}
+	if d, err := VerifyEntry(root, entry); err != nil || !d.Clean() {
+		t.Fatalf("expected clean pin, got %#v (%v)", d, err)
+	}
+
+	// Same extracted tree, different archive bytes.
+	writeTarball(t, tarball, pluginDir, "acme/")
+	d, err := VerifyEntry(root, entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.ArchiveChanged || d.FilesChanged() {
+		t.Fatalf("expected only the archive to drift, got %#v", d)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	got := DiffLines("a\nb\nc\n", "a\nc\nd\n")
+	want := []string{"- b", "+ d"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("DiffLines=%q, want %q", got, want)
+	}
+}
+
+func writeTarball(t *testing.T, dst, dir, prefix string) {
+	t.Helper()
+	f, err := os.Create(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, walkErr error) error {
+		if walkErr != nil || d.IsDir() {
+			return walkErr
+		}
+		rel, _ := filepath.Rel(dir, path)
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: prefix + filepath.ToSlash(rel), Mode: 0o755, Size: int64(len(b)), Typeflag: tar.TypeReg}); err != nil {
+			return err
+		}
+		_, err = tw.Write(b)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseManifestReportsSchemaErrorsWithPaths(t *testing.T) {
+	manifest := "apiVersion: pacto/v1alpha1\n" +
+		"kind: Plugin\n" +
+		"metadata:\n" +
+		"  id: acme\n" +
+		"  priorty: 3\n" +
+		"spec:\n" +
+		"  cliGuardrails:\n" +
+		"    - id: clean\n" +
+		"      phase: later\n" +
+		"      run:\n" +
+		"        timeoutMs: \"5000\"\n"
+	_, err := parseManifestBytes([]byte(manifest))
+	var errs SchemaErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected schema errors, got %v", err)
+	}
+	want := []string{
+		`line 5: metadata.priorty: unknown field (did you mean "priority"?)`,
+		`line 9: spec.cliGuardrails[0].phase: must be one of pre|post|onError|on-error|onerror, got "later"`,
+		`line 10: spec.cliGuardrails[0].run.script: is required`,
+		`line 11: spec.cliGuardrails[0].run.timeoutMs: expected integer, got string`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors, want %d: %v", len(errs), len(want), errs)
+	}
+	for i, w := range want {
+		if errs[i].Error() != w {
+			t.Fatalf("error %d = %q, want %q", i, errs[i].Error(), w)
+		}
+	}
+
+	beta := strings.Replace(manifest, "pacto/v1alpha1", ManifestAPIVersionV1Beta1, 1)
+	_, err = parseManifestBytes([]byte(beta))
+	if err == nil || !strings.Contains(err.Error(), "metadata.version: is required") || !strings.Contains(err.Error(), "must be one of pre|post|onError,") {
+		t.Fatalf("expected stricter v1beta1 errors, got %v", err)
+	}
+}
+
+func TestSatisfiesVersion(t *testing.T) {
+	cases := []struct {
+		version, constraint string
+		want                bool
+	}{
+		{"0.2.0", ">=0.2.0 <1.0.0", true},
+		{"1.0.0", ">=0.2.0, <1.0.0", false},
+		{"v1.4.2", "^1.2", true},
+		{"2.0.0", "^1.2", false},
+		{"0.3.1", "^0.2.0 || ~0.3.0", true},
+		{"0.2.5", "^0.2.1", true},
+		{"0.3.0", "^0.2.1", false},
+		{"1.2.0-rc.1", ">=1.2.0", false},
+		{"1.2.0-rc.2", ">1.2.0-rc.1", true},
+		{"9.9.9", "*", true},
+	}
+	for _, c := range cases {
+		got, err := SatisfiesVersion(c.version, c.constraint)
+		if err != nil {
+			t.Fatalf("SatisfiesVersion(%q, %q): %v", c.version, c.constraint, err)
+		}
+		if got != c.want {
+			t.Fatalf("SatisfiesVersion(%q, %q) = %v, want %v", c.version, c.constraint, got, c.want)
+		}
+	}
+	if _, err := SatisfiesVersion("1.0.0", ">=one"); err == nil {
+		t.Fatalf("expected invalid constraint error")
+	}
+}
+
+func TestResolveOrdersDependenciesAndChecksConstraints(t *testing.T) {
+	old := PactoVersion
+	PactoVersion = "0.5.0"
+	defer func() { PactoVersion = old }()
+	plugin := func(id, version string, priority int, requires string, deps ...Dependency) Plugin {
+		return Plugin{Manifest: Manifest{
+			APIVersion: ManifestAPIVersionV1Beta1,
+			Metadata:   Metadata{ID: id, Version: version, Priority: priority},
+			Spec:       Spec{Requires: Requires{Pacto: requires}, DependsOn: deps},
+		}}
+	}
+	ids := func(list []Plugin) string {
+		out := make([]string, 0, len(list))
+		for _, p := range list {
+			out = append(out, p.Manifest.Metadata.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	ordered, errs := Resolve([]Plugin{
+		plugin("tracker", "1.0.0", 1, ">=0.4.0", Dependency{ID: "git-sync", Version: "^0.3"}),
+		plugin("git-sync", "0.3.2", 50, ""),
+		plugin("acme", "1.0.0", 10, ""),
+	})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if got := ids(ordered); got != "acme,git-sync,tracker" {
+		t.Fatalf("load order = %s, want dependencies before dependents", got)
+	}
+
+	ordered, errs = Resolve([]Plugin{
+		plugin("future", "1.0.0", 1, ">=1.0.0"),
+		plugin("tracker", "1.0.0", 1, "", Dependency{ID: "git-sync", Version: ">=0.4.0"}),
+		plugin("git-sync", "0.3.2", 50, ""),
+		plugin("needs-missing", "1.0.0", 1, "", Dependency{ID: "future"}),
+		plugin("a", "1.0.0", 1, "", Dependency{ID: "b"}),
+		plugin("b", "1.0.0", 1, "", Dependency{ID: "a"}),
+	})
+	if got := ids(ordered); got != "git-sync" {
+		t.Fatalf("kept %s, want only git-sync", got)
+	}
+	msgs := errorStrings(errs)
+	for _, want := range []string{
+		"future requires pacto >=1.0.0 (running 0.5.0)",
+		"tracker depends on git-sync >=0.4.0, found 0.3.2",
+		"needs-missing depends on future",
+		"plugin dependency cycle: a -> b -> a",
+	} {
+		if !strings.Contains(msgs, want) {
+			t.Fatalf("expected %q in errors:\n%s", want, msgs)
+		}
+	}
+}
+
+func TestMigrateManifestToV1Beta1(t *testing.T) {
+	root := t.TempDir()
+	writePlugin(t, root, pluginSpec{dir: "acme", id: "acme"})
+	dir := filepath.Join(root, ".pacto", "plugins", "acme")
+	path := filepath.Join(dir, "plugin.yaml")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := strings.Replace(string(b), "      run:\n", "      phase: on-error # after failures\n      run:\n", 1)
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mig, err := MigrateManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := string(mig.After)
+	if !mig.Changed() || mig.From != ManifestAPIVersion || mig.To != ManifestAPIVersionV1Beta1 {
+		t.Fatalf("unexpected migration: %#v", mig)
+	}
+	if !strings.Contains(after, "apiVersion: pacto/v1beta1") || !strings.Contains(after, "phase: onError # after failures") {
+		t.Fatalf("unexpected migrated manifest:\n%s", after)
+	}
+	if len(mig.Notes) != 1 || !strings.Contains(mig.Notes[0], `"on-error" renamed to "onError"`) {
+		t.Fatalf("unexpected notes: %v", mig.Notes)
+	}
+	if err := os.WriteFile(path, mig.After, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mig, err = MigrateManifest(dir)
+	if err != nil || mig.Changed() {
+		t.Fatalf("expected v1beta1 manifest to be left alone, got %v changed=%v", err, mig.Changed())
+	}
+}
+
+func TestSandboxedGuardrailIsIsolated(t *testing.T) {
+	root := t.TempDir()
+	pluginDir := filepath.Join(root, ".pacto", "plugins", "boxed")
+	if err := os.MkdirAll(filepath.Join(pluginDir, "scripts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\n" +
+		"echo \"secret=${SECRET_TOKEN:-unset} keep=${KEEP_ME:-unset}\"\n" +
+		"touch \"$PACTO_PROJECT_ROOT/written\" 2>/dev/null && echo root=rw || echo root=ro\n" +
+		"touch \"$PACTO_TMPDIR/scratch\" && echo tmp=rw\n" +
+		"echo \"ifaces=$(tail -n +3 /proc/net/dev | wc -l | tr -d ' ')\"\n" +
+		"exit 1\n"
+	if err := os.WriteFile(filepath.Join(pluginDir, "scripts", "probe.sh"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "apiVersion: pacto/v1beta1\n" +
+		"kind: Plugin\n" +
+		"metadata:\n" +
+		"  id: boxed\n" +
+		"  version: 0.1.0\n" +
+		"spec:\n" +
+		"  sandbox:\n" +
+		"    env: [KEEP_*]\n" +
+		"    cpuSeconds: 5\n" +
+		"    memoryMB: 512\n" +
+		"  cliGuardrails:\n" +
+		"    - id: probe\n" +
+		"      commands: [new]\n" +
+		"      run:\n" +
+		"        script: scripts/probe.sh\n"
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.yaml"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SECRET_TOKEN", "hunter2")
+	t.Setenv("KEEP_ME", "yes")
+
+	d := Discover(root)
+	if len(d.Errors) > 0 || len(d.Plugins) != 1 {
+		t.Fatalf("unexpected discover result: %#v", d)
+	}
+	p := d.Plugins[0]
+	perm := PluginPermissions(p)
+	if !perm.Sandboxed || perm.ProjectRoot != SandboxReadOnly || perm.Network || perm.MemoryMB != 512 {
+		t.Fatalf("unexpected permissions: %#v", perm)
+	}
+	v := EvaluateGuardrails([]Plugin{p}, HookRequest{Command: "new", ProjectRoot: root})
+	if len(v) != 1 {
+		t.Fatalf("expected one probe result, got %#v", v)
+	}
+	out := v[0].Stdout
+	if !strings.Contains(out, "secret=unset keep=yes") || !strings.Contains(out, "tmp=rw") {
+		t.Fatalf("unexpected sandbox environment: %q (stderr %q)", out, v[0].Stderr)
+	}
+	if perm.Isolation != "namespaces" {
+		t.Logf("namespaces unavailable, skipping filesystem and network checks: %q", out)
+	} else if !strings.Contains(out, "root=ro") || !strings.Contains(out, "ifaces=1") {
+		t.Fatalf("expected read-only root and loopback-only network: %q (stderr %q)", out, v[0].Stderr)
+	}
+	if _, err := os.Stat(filepath.Join(root, "written")); err == nil && perm.Isolation == "namespaces" {
+		t.Fatalf("sandboxed script wrote to the project root")
+	}
+
+	if err := WriteActiveConfig(root, []string{"boxed"}); err != nil {
+		t.Fatal(err)
+	}
+	if active, errs := LoadActive(root); len(active) != 0 || len(errs) != 1 || !strings.Contains(errs[0].Error(), "never approved") {
+		t.Fatalf("expected unapproved sandboxed plugin refused, got %d %v", len(active), errs)
+	}
+	if err := Approve(root, "boxed", perm.Digest()); err != nil {
+		t.Fatal(err)
+	}
+	if active, errs := LoadActive(root); len(errs) != 0 || len(active) != 1 {
+		t.Fatalf("expected approved plugin active, got %d %v", len(active), errs)
+	}
+	widened := strings.Replace(manifest, "    memoryMB: 512\n", "    memoryMB: 512\n    network: true\n", 1)
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.yaml"), []byte(widened), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	active, errs := LoadActive(root)
+	if len(active) != 0 || len(errs) != 1 || !strings.Contains(errs[0].Error(), "permissions changed") {
+		t.Fatalf("expected changed permissions to block the plugin, got %d %v", len(active), errs)
+	}
+}
+
+func TestEvaluateGuardrailsRunsConcurrentlyWithinBudget(t *testing.T) {
+	root := t.TempDir()
+	logPath := filepath.Join(root, "hooks.log")
+	plugin := func(id string, serial bool, body string) Plugin {
+		dir := filepath.Join(root, ".pacto", "plugins", id)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		script := "#!/bin/sh\necho " + id + "-start >> \"" + logPath + "\"\n" + body + "\necho " + id + "-end >> \"" + logPath + "\"\nexit 1\n"
+		if err := os.WriteFile(filepath.Join(dir, "check.sh"), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		return Plugin{Dir: dir, Manifest: Manifest{Metadata: Metadata{ID: id}, Spec: Spec{CLIGuardrails: []CLIGuardrail{{
+			ID: "check", Phase: PhasePre, Commands: []string{"status"}, Serial: serial,
+			Run: RunSpec{Script: "check.sh", TimeoutMS: 5000},
+		}}}}}
+	}
+
+	active := []Plugin{
+		plugin("c", false, "sleep 1"),
+		plugin("a", false, "sleep 1"),
+		plugin("b", false, "sleep 1"),
+		plugin("sync", true, "sleep 0.2"),
+		plugin("after", false, ""),
+	}
+	start := time.Now()
+	v := EvaluateGuardrails(active, HookRequest{Command: "status", ProjectRoot: root, Parallelism: 3})
+	if elapsed := time.Since(start); elapsed > 2500*time.Millisecond {
+		t.Fatalf("guardrails took %s, expected concurrent runs", elapsed)
+	}
+	var ids []string
+	for _, x := range v {
+		ids = append(ids, x.PluginID)
+	}
+	if strings.Join(ids, ",") != "a,after,b,c,sync" {
+		t.Fatalf("results not in sorted order: %v", ids)
+	}
+	b, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Fields(string(b))
+	if len(lines) != 10 || lines[6] != "sync-start" || lines[7] != "sync-end" || lines[8] != "after-start" {
+		t.Fatalf("serial guardrail must run alone after earlier ones and before later ones: %v", lines)
+	}
+
+	v = EvaluateGuardrails([]Plugin{plugin("slow", false, "sleep 2"), plugin("sync", true, "")}, HookRequest{Command: "status", ProjectRoot: root, Budget: 300 * time.Millisecond})
+	if len(v) != 2 || !v[0].TimedOut || !v[1].TimedOut {
+		t.Fatalf("expected both guardrails to hit the budget, got %#v", v)
+	}
+	if !strings.Contains(v[0].Message, "stopped: guardrail time budget of 300ms exhausted") || !strings.Contains(v[1].Message, "skipped") {
+		t.Fatalf("unexpected budget messages: %q / %q", v[0].Message, v[1].Message)
+	}
+}
+
+func TestPluginCommandsValidateAndConflict(t *testing.T) {
+	root := t.TempDir()
+	write := func(id, name string) {
+		dir := filepath.Join(root, ".pacto", "plugins", id)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "run.sh"), []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		manifest := "apiVersion: pacto/v1beta1\nkind: Plugin\nmetadata:\n  id: " + id + "\n  version: 1.0.0\nspec:\n  commands:\n    - name: " + name + "\n      summary: test\n      run:\n        script: run.sh\n"
+		if err := os.WriteFile(filepath.Join(dir, "plugin.yaml"), []byte(manifest), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("alpha", "sync-jira")
+	write("beta", "sync-jira")
+	write("gamma", "status")
+
+	d := Discover(root)
+	if errs := errorStrings(d.Errors); !strings.Contains(errs, `"status" is a built-in pacto command`) {
+		t.Fatalf("expected reserved command error, got %q", errs)
+	}
+	ordered, errs := Resolve(d.Plugins)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `beta: command "sync-jira" is already provided by plugin alpha`) {
+		t.Fatalf("expected conflict error, got %v", errs)
+	}
+	e, ok := FindCommand(ordered, "sync-jira")
+	if !ok || e.PluginID() != "alpha" {
+		t.Fatalf("expected alpha to own sync-jira, got %#v", e)
+	}
+	if e.Command.Usage != "pacto sync-jira [args]" {
+		t.Fatalf("expected default usage, got %q", e.Command.Usage)
+	}
+}
+
+func errorStrings(errs []error) string {
+	out := make([]string, 0, len(errs))
+	for _, err := range errs {
+		out = append(out, err.Error())
+	}
+	return strings.Join(out, "\n")
+}
+
 func TestActivationEnableDisableRoundTrip(t *testing.T) {
 	root := t.TempDir()
 	if err := Enable(root, "acme"); err != nil {
@@ -265,3 +910,18 @@ func writePlugin(t *testing.T, root string, spec pluginSpec) {
 		t.Fatal(err)
 	}
 }
+
+func TestScriptEnvDoesNotShadowConfigOverrides(t *testing.T) {
+	reserved := map[string]bool{}
+	for _, k := range config.Keys {
+		reserved[config.EnvName(k)] = true
+	}
+	env := append(scriptEnv("acme", HookRequest{}), outcomeEnv(HookRequest{})...)
+	env = append(env, checkEnv("acme", "check", "/p", "/r", parser.ParsedPlan{})...)
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		if reserved[name] {
+			t.Fatalf("script variable %s would override pacto config in nested pacto calls", name)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"pacto/internal/model"
+)
+
+// ProtocolVersion identifies the JSON request/response exchanged with
+// guardrails that set run.protocol: json.
+const ProtocolVersion = "pacto.hook/v1"
+
+const (
+	ProtocolEnv  = "env"
+	ProtocolJSON = "json"
+)
+
+const (
+	DecisionAllow = "allow"
+	DecisionWarn  = "warn"
+	DecisionDeny  = "deny"
+)
+
+type ProtocolRequest struct {
+	Version     string              `json:"version"`
+	Plugin      string              `json:"plugin"`
+	Guardrail   string              `json:"guardrail,omitempty"`
+	Phase       string              `json:"phase,omitempty"`
+	Command     string              `json:"command"`
+	Args        []string            `json:"args"`
+	Flags       map[string]string   `json:"flags"`
+	Positionals []string            `json:"positionals"`
+	ProjectRoot string              `json:"project_root"`
+	PlansRoot   string              `json:"plans_root,omitempty"`
+	Plans       []string            `json:"plans,omitempty"`
+	Language    string              `json:"language,omitempty"`
+	Outcome     *Outcome            `json:"outcome,omitempty"`
+	Report      *model.StatusReport `json:"report,omitempty"`
+}
+
+type ProtocolResponse struct {
+	Version     string       `json:"version"`
+	Decision    string       `json:"decision"`
+	Message     string       `json:"message"`
+	Annotations []Annotation `json:"annotations"`
+	NextActions []NextAction `json:"next_actions"`
+}
+
+// Annotation is a key/value merged into the status report, on the plan
+// (state/slug) when set or on the report otherwise.
+type Annotation struct {
+	Plan  string `json:"plan,omitempty"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// NextAction is a suggested follow-up, scoped to a plan like Annotation.
+type NextAction struct {
+	Plan string `json:"plan,omitempty"`
+	Text string `json:"text"`
+}
+
+func buildProtocolRequest(pluginID string, g CLIGuardrail, req HookRequest) ([]byte, error) {
+	pr := ProtocolRequest{
+		Version:     ProtocolVersion,
+		Plugin:      pluginID,
+		Guardrail:   g.ID,
+		Phase:       req.Phase,
+		Command:     req.Command,
+		Args:        req.Args,
+		Flags:       req.Flags,
+		Positionals: req.Positionals,
+		ProjectRoot: req.ProjectRoot,
+		PlansRoot:   req.PlansRoot,
+		Plans:       req.Plans,
+		Language:    req.Language,
+		Report:      req.Report,
+	}
+	if pr.Args == nil {
+		pr.Args = []string{}
+	}
+	if pr.Flags == nil {
+		pr.Flags = map[string]string{}
+	}
+	if pr.Positionals == nil {
+		pr.Positionals = []string{}
+	}
+	if req.Phase == PhasePost || req.Phase == PhaseOnError {
+		out := req.Outcome
+		out.Touched = relativeTouched(req.ProjectRoot, out.Touched)
+		pr.Outcome = &out
+	}
+	return json.Marshal(pr)
+}
+
+// parseProtocolResponse reads the script's stdout. Empty output means allow.
+func parseProtocolResponse(stdout string) (ProtocolResponse, error) {
+	stdout = strings.TrimSpace(stdout)
+	if stdout == "" {
+		return ProtocolResponse{Decision: DecisionAllow}, nil
+	}
+	var resp ProtocolResponse
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+		return ProtocolResponse{}, fmt.Errorf("invalid JSON response: %w", err)
+	}
+	if resp.Version != "" && resp.Version != ProtocolVersion {
+		return ProtocolResponse{}, fmt.Errorf("unsupported response version %q (want %s)", resp.Version, ProtocolVersion)
+	}
+	resp.Decision = strings.ToLower(strings.TrimSpace(resp.Decision))
+	switch resp.Decision {
+	case "":
+		resp.Decision = DecisionAllow
+	case DecisionAllow, DecisionWarn, DecisionDeny:
+	default:
+		return ProtocolResponse{}, fmt.Errorf("invalid decision %q (allowed: allow|warn|deny)", resp.Decision)
+	}
+	resp.Message = strings.TrimSpace(resp.Message)
+	return resp, nil
+}
+
+func relativeTouched(projectRoot string, paths []string) []string {
+	out := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if rel, err := filepath.Rel(projectRoot, p); err == nil && !strings.HasPrefix(rel, "..") {
+			p = rel
+		}
+		out = append(out, filepath.ToSlash(p))
+	}
+	return out
+}
+
+// MergeIntoReport applies annotations and next actions returned by JSON
+// protocol hooks to the status report.
+func MergeIntoReport(rep *model.StatusReport, results []GuardrailViolation) {
+	plans := map[string]int{}
+	for i, p := range rep.Plans {
+		plans[p.StateFolder+"/"+p.Slug] = i
+	}
+	for _, r := range results {
+		for _, a := range r.Annotations {
+			if strings.TrimSpace(a.Key) == "" {
+				continue
+			}
+			if i, ok := plans[a.Plan]; ok {
+				if rep.Plans[i].Annotations == nil {
+					rep.Plans[i].Annotations = map[string]string{}
+				}
+				rep.Plans[i].Annotations[a.Key] = a.Value
+				continue
+			}
+			if rep.Annotations == nil {
+				rep.Annotations = map[string]string{}
+			}
+			rep.Annotations[a.Key] = a.Value
+		}
+		for _, n := range r.NextActions {
+			if strings.TrimSpace(n.Text) == "" {
+				continue
+			}
+			if i, ok := plans[n.Plan]; ok {
+				rep.Plans[i].NextActions = append(rep.Plans[i].NextActions, n.Text)
+				continue
+			}
+			rep.NextActions = append(rep.NextActions, n.Text)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package plugins
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PactoVersion is the running pacto version checked against
+// spec.requires.pacto. Development builds ("dev" or any non-semver value)
+// satisfy every constraint.
+var PactoVersion = "dev"
+
+// Resolve drops plugins whose pacto requirement or dependencies are not met
+// by the given set and returns the rest in load order: dependencies first,
+// then by priority and id.
+func Resolve(candidates []Plugin) ([]Plugin, []error) {
+	var errs []error
+	byID := map[string]Plugin{}
+	for _, p := range candidates {
+		byID[p.Manifest.Metadata.ID] = p
+	}
+	host, hostErr := parseSemver(PactoVersion)
+	for _, p := range candidates {
+		req := p.Manifest.Spec.Requires.Pacto
+		if req == "" || hostErr != nil {
+			continue
+		}
+		c, err := parseConstraint(req)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: spec.requires.pacto: %w", p.Manifest.Metadata.ID, err))
+			delete(byID, p.Manifest.Metadata.ID)
+			continue
+		}
+		if !c.match(host) {
+			errs = append(errs, fmt.Errorf("%s requires pacto %s (running %s)", p.Manifest.Metadata.ID, req, PactoVersion))
+			delete(byID, p.Manifest.Metadata.ID)
+		}
+	}
+	for {
+		// Dropping a plugin can break its dependents, so repeat until stable.
+		for changed := true; changed; {
+			changed = false
+			for _, p := range candidates {
+				id := p.Manifest.Metadata.ID
+				if _, ok := byID[id]; !ok {
+					continue
+				}
+				if err := checkDependencies(p, byID); err != nil {
+					errs = append(errs, err)
+					delete(byID, id)
+					changed = true
+				}
+			}
+		}
+		kept := make([]Plugin, 0, len(byID))
+		for _, p := range candidates {
+			if _, ok := byID[p.Manifest.Metadata.ID]; ok {
+				kept = append(kept, p)
+			}
+		}
+		ordered, cycle := orderPlugins(kept)
+		if len(cycle) == 0 {
+			return ordered, append(errs, commandConflicts(ordered)...)
+		}
+		errs = append(errs, fmt.Errorf("plugin dependency cycle: %s", strings.Join(cycle, " -> ")))
+		for _, id := range cycle {
+			delete(byID, id)
+		}
+	}
+}
+
+func checkDependencies(p Plugin, available map[string]Plugin) error {
+	id := p.Manifest.Metadata.ID
+	for _, d := range p.Manifest.Spec.DependsOn {
+		dep, ok := available[d.ID]
+		if !ok {
+			return fmt.Errorf("%s depends on %s, which is not installed, enabled or loadable", id, d.ID)
+		}
+		if d.Version == "" {
+			continue
+		}
+		ok, err := SatisfiesVersion(dep.Manifest.Metadata.Version, d.Version)
+		if err != nil {
+			return fmt.Errorf("%s depends on %s %s: %w", id, d.ID, d.Version, err)
+		}
+		if !ok {
+			return fmt.Errorf("%s depends on %s %s, found %s", id, d.ID, d.Version, dep.Manifest.Metadata.Version)
+		}
+	}
+	return nil
+}
+
+// orderPlugins sorts plugins so dependencies load before their dependents,
+// breaking ties by priority then id. Dependencies outside the set are
+// ignored. If there is a cycle it is returned and its plugins are appended
+// in priority order.
+func orderPlugins(list []Plugin) ([]Plugin, []string) {
+	sorted := append([]Plugin(nil), list...)
+	sort.Slice(sorted, func(i, j int) bool {
+		pi := sorted[i].Manifest.Metadata.Priority
+		pj := sorted[j].Manifest.Metadata.Priority
+		if pi == pj {
+			return sorted[i].Manifest.Metadata.ID < sorted[j].Manifest.Metadata.ID
+		}
+		return pi < pj
+	})
+	index := map[string]int{}
+	for i, p := range sorted {
+		index[p.Manifest.Metadata.ID] = i
+	}
+	pending := map[string]int{}
+	dependents := map[string][]string{}
+	for _, p := range sorted {
+		id := p.Manifest.Metadata.ID
+		for _, d := range p.Manifest.Spec.DependsOn {
+			if _, ok := index[d.ID]; ok {
+				pending[id]++
+				dependents[d.ID] = append(dependents[d.ID], id)
+			}
+		}
+	}
+	out := make([]Plugin, 0, len(sorted))
+	done := map[string]bool{}
+	for len(out) < len(sorted) {
+		next := -1
+		for i, p := range sorted {
+			if !done[p.Manifest.Metadata.ID] && pending[p.Manifest.Metadata.ID] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			break
+		}
+		id := sorted[next].Manifest.Metadata.ID
+		done[id] = true
+		out = append(out, sorted[next])
+		for _, dep := range dependents[id] {
+			pending[dep]--
+		}
+	}
+	if len(out) == len(sorted) {
+		return out, nil
+	}
+	var rest []Plugin
+	for _, p := range sorted {
+		if !done[p.Manifest.Metadata.ID] {
+			rest = append(rest, p)
+		}
+	}
+	return append(out, rest...), findCycle(rest)
+}
+
+func findCycle(rest []Plugin) []string {
+	inRest := map[string]bool{}
+	for _, p := range rest {
+		inRest[p.Manifest.Metadata.ID] = true
+	}
+	deps := map[string][]string{}
+	for _, p := range rest {
+		for _, d := range p.Manifest.Spec.DependsOn {
+			if inRest[d.ID] {
+				deps[p.Manifest.Metadata.ID] = append(deps[p.Manifest.Metadata.ID], d.ID)
+			}
+		}
+	}
+	// Every remaining plugin still waits on another remaining one, so
+	// following first unresolved dependencies must revisit a plugin.
+	id := rest[0].Manifest.Metadata.ID
+	seenAt := map[string]int{}
+	var path []string
+	for {
+		if at, ok := seenAt[id]; ok {
+			return append(path[at:], id)
+		}
+		seenAt[id] = len(path)
+		path = append(path, id)
+		if len(deps[id]) == 0 {
+			return path
+		}
+		id = deps[id][0]
+	}
+}
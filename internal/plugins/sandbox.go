@@ -0,0 +1,225 @@
+package plugins
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+const (
+	SandboxReadOnly  = "read-only"
+	SandboxReadWrite = "read-write"
+)
+
+// sandboxHelperEnv carries the sandbox setup to the re-executed pacto
+// binary, which applies it and then execs the script.
+const sandboxHelperEnv = "PACTO_SANDBOX_EXEC"
+
+// sandboxBaseEnv is always passed to sandboxed scripts besides PACTO_*.
+var sandboxBaseEnv = []string{"PATH", "LANG", "LC_ALL", "TERM", "TZ"}
+
+// Permissions describes what a plugin's scripts may access.
+type Permissions struct {
+	Sandboxed   bool     `json:"sandboxed"`
+	Env         []string `json:"env"`
+	ProjectRoot string   `json:"project_root"`
+	TempDir     bool     `json:"temp_dir"`
+	Network     bool     `json:"network"`
+	CPUSeconds  int      `json:"cpu_seconds,omitempty"`
+	MemoryMB    int      `json:"memory_mb,omitempty"`
+	// Isolation is how the policy is enforced on this machine: namespaces,
+	// or none when the platform cannot isolate filesystem and network.
+	Isolation string `json:"isolation"`
+}
+
+// PluginPermissions returns the effective permissions of a plugin's scripts.
+func PluginPermissions(p Plugin) Permissions {
+	sb := p.Manifest.Spec.Sandbox
+	if sb == nil {
+		return Permissions{Env: []string{"*"}, ProjectRoot: SandboxReadWrite, Network: true, Isolation: "none"}
+	}
+	env := append(append([]string{}, sandboxBaseEnv...), sb.Env...)
+	env = append(env, "PACTO_*")
+	perm := Permissions{
+		Sandboxed:   true,
+		Env:         uniqueSorted(env),
+		ProjectRoot: sb.ProjectRoot,
+		TempDir:     true,
+		Network:     sb.Network,
+		CPUSeconds:  sb.CPUSeconds,
+		MemoryMB:    sb.MemoryMB,
+		Isolation:   "none",
+	}
+	if namespacesAvailable() {
+		perm.Isolation = "namespaces"
+	}
+	return perm
+}
+
+// Summary renders the permissions as short "name: value" lines.
+func (p Permissions) Summary() []string {
+	if !p.Sandboxed {
+		return []string{"unrestricted: full environment, read-write project root, network, no limits"}
+	}
+	limit := func(v int, unit string) string {
+		if v == 0 {
+			return "unlimited"
+		}
+		return fmt.Sprintf("%d%s", v, unit)
+	}
+	root, network := p.ProjectRoot, "off"
+	if p.Network {
+		network = "on"
+	}
+	if p.Isolation != "namespaces" {
+		if !p.Network {
+			network = "off (not enforced: namespaces unavailable)"
+		}
+		if p.ProjectRoot == SandboxReadOnly {
+			root += " (not enforced: namespaces unavailable)"
+		}
+	}
+	return []string{
+		"env: " + strings.Join(p.Env, ", "),
+		"project root: " + root,
+		"temp dir: private, writable ($PACTO_TMPDIR)",
+		"network: " + network,
+		"cpu: " + limit(p.CPUSeconds, "s"),
+		"memory: " + limit(p.MemoryMB, "MB"),
+	}
+}
+
+// Digest identifies the declared policy, independent of how this machine
+// enforces it, so approvals survive moving between hosts.
+func (p Permissions) Digest() string {
+	p.Isolation = ""
+	b, _ := json.Marshal(p)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+type sandboxExec struct {
+	Script     string   `json:"script"`
+	ReadOnly   []string `json:"read_only,omitempty"`
+	Namespaces bool     `json:"namespaces"`
+	Network    bool     `json:"network"`
+	CPUSeconds int      `json:"cpu_seconds,omitempty"`
+	MemoryMB   int      `json:"memory_mb,omitempty"`
+}
+
+// scriptCommand prepares `/bin/sh script` for a plugin, applying its sandbox
+// policy when it declares one. extraEnv is always passed. The returned
+// cleanup removes the sandbox temp dir.
+func scriptCommand(ctx context.Context, p Plugin, script, projectRoot string, extraEnv []string) (*exec.Cmd, func(), error) {
+	sb := p.Manifest.Spec.Sandbox
+	if sb == nil {
+		cmd := exec.CommandContext(ctx, "/bin/sh", script)
+		cmd.Dir = projectRoot
+		cmd.Env = append(cmd.Environ(), extraEnv...)
+		return cmd, func() {}, nil
+	}
+	tmp, err := os.MkdirTemp("", "pacto-sandbox-")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmp) }
+	env := filterEnv(os.Environ(), append(append([]string{}, sandboxBaseEnv...), sb.Env...))
+	env = append(env, "HOME="+tmp, "TMPDIR="+tmp, "PACTO_TMPDIR="+tmp, "PACTO_SANDBOX=1")
+	env = append(env, extraEnv...)
+
+	spec := sandboxExec{
+		Script:     script,
+		Namespaces: namespacesAvailable(),
+		Network:    sb.Network,
+		CPUSeconds: sb.CPUSeconds,
+		MemoryMB:   sb.MemoryMB,
+	}
+	if sb.ProjectRoot != SandboxReadWrite {
+		spec.ReadOnly = []string{projectRoot}
+	}
+	exe, err := os.Executable()
+	if err != nil || !sandboxHelperSupported() {
+		// Without the helper only the environment and temp dir apply.
+		cmd := exec.CommandContext(ctx, "/bin/sh", script)
+		cmd.Dir = projectRoot
+		cmd.Env = env
+		return cmd, cleanup, nil
+	}
+	payload, err := json.Marshal(spec)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	cmd := exec.CommandContext(ctx, exe)
+	cmd.Dir = projectRoot
+	cmd.Env = append(env, sandboxHelperEnv+"="+string(payload))
+	cmd.SysProcAttr = sandboxSysProcAttr(spec)
+	return cmd, cleanup, nil
+}
+
+// RunSandboxHelper must be called first thing in main: when the process was
+// started as a sandbox helper it applies the policy and replaces itself with
+// the plugin script, otherwise it returns immediately.
+func RunSandboxHelper() {
+	raw, ok := os.LookupEnv(sandboxHelperEnv)
+	if !ok {
+		return
+	}
+	_ = os.Unsetenv(sandboxHelperEnv)
+	var spec sandboxExec
+	if err := json.Unmarshal([]byte(raw), &spec); err != nil {
+		fmt.Fprintf(os.Stderr, "pacto sandbox: %v\n", err)
+		os.Exit(126)
+	}
+	if err := enterSandbox(spec); err != nil {
+		fmt.Fprintf(os.Stderr, "pacto sandbox: %v\n", err)
+		os.Exit(126)
+	}
+	fmt.Fprintln(os.Stderr, "pacto sandbox: exec returned")
+	os.Exit(126)
+}
+
+// filterEnv keeps variables whose name is listed in allow; "NAME*" entries
+// match prefixes. PACTO_* variables always pass.
+func filterEnv(environ, allow []string) []string {
+	out := make([]string, 0, len(environ))
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(name, "PACTO_") || envAllowed(name, allow) {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
+
+func envAllowed(name string, allow []string) bool {
+	for _, a := range allow {
+		if prefix, ok := strings.CutSuffix(a, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueSorted(list []string) []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(list))
+	for _, s := range list {
+		if s != "" && !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,81 @@
+//go:build linux
+
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+)
+
+var (
+	namespacesOnce sync.Once
+	namespacesOK   bool
+)
+
+// namespacesAvailable reports whether unprivileged user, mount and network
+// namespaces can be created, by starting /bin/true in them once.
+func namespacesAvailable() bool {
+	namespacesOnce.Do(func() {
+		cmd := exec.Command("/bin/true")
+		cmd.SysProcAttr = sandboxSysProcAttr(sandboxExec{Namespaces: true})
+		namespacesOK = cmd.Run() == nil
+	})
+	return namespacesOK
+}
+
+func sandboxHelperSupported() bool { return true }
+
+func sandboxSysProcAttr(spec sandboxExec) *syscall.SysProcAttr {
+	attr := &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+	if !spec.Namespaces {
+		return attr
+	}
+	attr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS
+	if !spec.Network {
+		attr.Cloneflags |= syscall.CLONE_NEWNET
+	}
+	attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}}
+	attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}}
+	attr.GidMappingsEnableSetgroups = false
+	return attr
+}
+
+// Mount flags a user namespace may not clear when remounting.
+const lockedMountFlags = syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC | syscall.MS_NOATIME | syscall.MS_NODIRATIME | syscall.MS_RELATIME
+
+func enterSandbox(spec sandboxExec) error {
+	if spec.Namespaces {
+		if err := syscall.Mount("none", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+			return fmt.Errorf("make mounts private: %w", err)
+		}
+		for _, dir := range spec.ReadOnly {
+			if err := syscall.Mount(dir, dir, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+				return fmt.Errorf("bind %s: %w", dir, err)
+			}
+			var st syscall.Statfs_t
+			if err := syscall.Statfs(dir, &st); err != nil {
+				return fmt.Errorf("statfs %s: %w", dir, err)
+			}
+			flags := uintptr(syscall.MS_REMOUNT|syscall.MS_BIND|syscall.MS_RDONLY) | uintptr(st.Flags)&lockedMountFlags
+			if err := syscall.Mount("none", dir, "", flags, ""); err != nil {
+				return fmt.Errorf("remount %s read-only: %w", dir, err)
+			}
+		}
+	}
+	if spec.CPUSeconds > 0 {
+		lim := uint64(spec.CPUSeconds)
+		if err := syscall.Setrlimit(syscall.RLIMIT_CPU, &syscall.Rlimit{Cur: lim, Max: lim}); err != nil {
+			return fmt.Errorf("cpu limit: %w", err)
+		}
+	}
+	if spec.MemoryMB > 0 {
+		lim := uint64(spec.MemoryMB) << 20
+		if err := syscall.Setrlimit(syscall.RLIMIT_AS, &syscall.Rlimit{Cur: lim, Max: lim}); err != nil {
+			return fmt.Errorf("memory limit: %w", err)
+		}
+	}
+	return syscall.Exec("/bin/sh", []string{"/bin/sh", spec.Script}, os.Environ())
+}
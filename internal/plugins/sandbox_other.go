@@ -0,0 +1,20 @@
+//go:build !linux
+
+package plugins
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Namespace isolation and limits are Linux-only; elsewhere sandboxed scripts
+// still get the filtered environment and a private temp dir.
+func namespacesAvailable() bool { return false }
+
+func sandboxHelperSupported() bool { return false }
+
+func sandboxSysProcAttr(sandboxExec) *syscall.SysProcAttr { return nil }
+
+func enterSandbox(sandboxExec) error {
+	return errors.New("sandbox helper is only supported on linux")
+}
@@ -0,0 +1,266 @@
+package plugins
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed schema/*.json
+var schemaFS embed.FS
+
+// ManifestAPIVersions lists the manifest versions pacto can load, oldest
+// first.
+var ManifestAPIVersions = []string{ManifestAPIVersion, ManifestAPIVersionV1Beta1}
+
+// FieldError is one schema violation in a manifest, located by its field
+// path and source line.
+type FieldError struct {
+	Path    string
+	Line    int
+	Message string
+}
+
+func (e FieldError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "(root)"
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s: %s", e.Line, path, e.Message)
+	}
+	return path + ": " + e.Message
+}
+
+// SchemaErrors collects every schema violation of a manifest.
+type SchemaErrors []FieldError
+
+func (e SchemaErrors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, fe := range e {
+		parts = append(parts, fe.Error())
+	}
+	return strings.Join(parts, "; ")
+}
+
+type schemaNode struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 string                 `json:"type"`
+	Required             []string               `json:"required"`
+	Properties           map[string]*schemaNode `json:"properties"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *schemaNode            `json:"items"`
+	Enum                 []string               `json:"enum"`
+	Pattern              string                 `json:"pattern"`
+	MinLength            int                    `json:"minLength"`
+	Minimum              *float64               `json:"minimum"`
+	Defs                 map[string]*schemaNode `json:"$defs"`
+}
+
+// ManifestSchema returns the JSON Schema of a manifest apiVersion
+// ("pacto/v1beta1" or just "v1beta1").
+func ManifestSchema(apiVersion string) ([]byte, error) {
+	name := strings.TrimPrefix(strings.TrimSpace(apiVersion), "pacto/")
+	for _, v := range ManifestAPIVersions {
+		if "pacto/"+name == v {
+			return schemaFS.ReadFile("schema/" + name + ".json")
+		}
+	}
+	return nil, fmt.Errorf("unknown manifest apiVersion %q (supported: %s)", apiVersion, strings.Join(ManifestAPIVersions, ", "))
+}
+
+// validateSchema checks a manifest document against the schema of its
+// apiVersion and returns every violation found.
+func validateSchema(doc *yaml.Node) SchemaErrors {
+	root := doc
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return SchemaErrors{{Line: root.Line, Message: "expected object, got " + nodeType(root)}}
+	}
+	apiVersion := ""
+	apiLine := root.Line
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "apiVersion" {
+			apiVersion = strings.TrimSpace(root.Content[i+1].Value)
+			apiLine = root.Content[i+1].Line
+		}
+	}
+	b, err := ManifestSchema(apiVersion)
+	if err != nil || !strings.HasPrefix(apiVersion, "pacto/") {
+		return SchemaErrors{{Path: "apiVersion", Line: apiLine, Message: "must be one of " + strings.Join(ManifestAPIVersions, ", ")}}
+	}
+	var s schemaNode
+	if err := json.Unmarshal(b, &s); err != nil {
+		return SchemaErrors{{Message: "load schema: " + err.Error()}}
+	}
+	v := schemaValidator{defs: s.Defs}
+	v.check(&s, root, "", root.Line)
+	sort.SliceStable(v.errs, func(i, j int) bool { return v.errs[i].Line < v.errs[j].Line })
+	return v.errs
+}
+
+type schemaValidator struct {
+	defs map[string]*schemaNode
+	errs SchemaErrors
+}
+
+func (v *schemaValidator) fail(n *yaml.Node, path, format string, args ...any) {
+	v.errs = append(v.errs, FieldError{Path: path, Line: n.Line, Message: fmt.Sprintf(format, args...)})
+}
+
+// check validates n against s; line is where the field starts (its key) and
+// locates "is required" errors.
+func (v *schemaValidator) check(s *schemaNode, n *yaml.Node, path string, line int) {
+	if s.Ref != "" {
+		ref := v.defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if ref == nil {
+			v.fail(n, path, "unknown schema reference %s", s.Ref)
+			return
+		}
+		s = ref
+	}
+	if n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	if s.Type != "" && !nodeMatchesType(n, s.Type) {
+		v.fail(n, path, "expected %s, got %s", s.Type, nodeType(n))
+		return
+	}
+	switch n.Kind {
+	case yaml.MappingNode:
+		seen := map[string]bool{}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			seen[key.Value] = true
+			child := joinPath(path, key.Value)
+			if ps, ok := s.Properties[key.Value]; ok {
+				v.check(ps, val, child, key.Line)
+				continue
+			}
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				v.fail(key, child, "unknown field%s", suggestField(key.Value, s.Properties))
+			}
+		}
+		for _, req := range s.Required {
+			if !seen[req] {
+				v.errs = append(v.errs, FieldError{Path: joinPath(path, req), Line: line, Message: "is required"})
+			}
+		}
+	case yaml.SequenceNode:
+		if s.Items != nil {
+			for i, item := range n.Content {
+				v.check(s.Items, item, fmt.Sprintf("%s[%d]", path, i), item.Line)
+			}
+		}
+	case yaml.ScalarNode:
+		if len(s.Enum) > 0 && !containsString(s.Enum, n.Value) {
+			v.fail(n, path, "must be one of %s, got %q", strings.Join(s.Enum, "|"), n.Value)
+		}
+		if s.MinLength > 0 && len(strings.TrimSpace(n.Value)) < s.MinLength {
+			v.fail(n, path, "must not be empty")
+		}
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(n.Value) {
+				v.fail(n, path, "%q does not match %s", n.Value, s.Pattern)
+			}
+		}
+		if s.Minimum != nil {
+			if f, err := strconv.ParseFloat(n.Value, 64); err == nil && f < *s.Minimum {
+				v.fail(n, path, "must be >= %v", *s.Minimum)
+			}
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func nodeType(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	case yaml.ScalarNode:
+		switch n.ShortTag() {
+		case "!!int":
+			return "integer"
+		case "!!float":
+			return "number"
+		case "!!bool":
+			return "boolean"
+		case "!!null":
+			return "null"
+		}
+		return "string"
+	}
+	return "unknown"
+}
+
+func nodeMatchesType(n *yaml.Node, typ string) bool {
+	got := nodeType(n)
+	switch typ {
+	case "string":
+		// Unquoted scalars such as `version: 1.0` still decode as strings.
+		return n.Kind == yaml.ScalarNode && got != "null"
+	case "number":
+		return got == "number" || got == "integer"
+	}
+	return got == typ
+}
+
+// suggestField names a known field within two edits of an unknown key.
+func suggestField(key string, props map[string]*schemaNode) string {
+	best, bestDist := "", 3
+	for name := range props {
+		d := editDistance(strings.ToLower(key), strings.ToLower(name))
+		if d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func containsString(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,231 @@
+package plugins
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type semver struct {
+	major, minor, patch int
+	pre                 string
+}
+
+// parseSemver accepts MAJOR[.MINOR[.PATCH]][-PRERELEASE][+BUILD] with an
+// optional leading "v"; missing minor/patch parts count as 0.
+func parseSemver(s string) (semver, error) {
+	raw := s
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	var v semver
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.pre = s[i+1:]
+		s = s[:i]
+		if v.pre == "" {
+			return semver{}, fmt.Errorf("invalid version %q", raw)
+		}
+	}
+	parts := strings.Split(s, ".")
+	if s == "" || len(parts) > 3 {
+		return semver{}, fmt.Errorf("invalid version %q", raw)
+	}
+	nums := []*int{&v.major, &v.minor, &v.patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return semver{}, fmt.Errorf("invalid version %q", raw)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+func (v semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+	if v.pre != "" {
+		s += "-" + v.pre
+	}
+	return s
+}
+
+func compareSemver(a, b semver) int {
+	for _, d := range [][2]int{{a.major, b.major}, {a.minor, b.minor}, {a.patch, b.patch}} {
+		if d[0] != d[1] {
+			if d[0] < d[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case a.pre == b.pre:
+		return 0
+	case a.pre == "":
+		return 1
+	case b.pre == "":
+		return -1
+	}
+	ap, bp := strings.Split(a.pre, "."), strings.Split(b.pre, ".")
+	for i := 0; i < len(ap) && i < len(bp); i++ {
+		if ap[i] == bp[i] {
+			continue
+		}
+		an, aErr := strconv.Atoi(ap[i])
+		bn, bErr := strconv.Atoi(bp[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an < bn {
+				return -1
+			}
+			return 1
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		case ap[i] < bp[i]:
+			return -1
+		default:
+			return 1
+		}
+	}
+	switch {
+	case len(ap) < len(bp):
+		return -1
+	case len(ap) > len(bp):
+		return 1
+	}
+	return 0
+}
+
+type comparator struct {
+	op string
+	v  semver
+}
+
+func (c comparator) match(v semver) bool {
+	cmp := compareSemver(v, c.v)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// constraint is a list of alternatives ("||"), each a list of comparators
+// that must all match.
+type constraint [][]comparator
+
+// parseConstraint understands comparators (=, !=, >, >=, <, <=), caret and
+// tilde ranges and "*", separated by spaces or commas, with "||" between
+// alternatives. For example ">=0.2.0 <1.0.0" or "^1.4 || ^2".
+func parseConstraint(s string) (constraint, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, fmt.Errorf("empty version constraint")
+	}
+	var out constraint
+	for _, alt := range strings.Split(s, "||") {
+		fields := strings.Fields(strings.ReplaceAll(alt, ",", " "))
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q", s)
+		}
+		var all []comparator
+		for i := 0; i < len(fields); i++ {
+			tok := fields[i]
+			if isConstraintOp(tok) && i+1 < len(fields) {
+				i++
+				tok += fields[i]
+			}
+			cs, err := parseComparator(tok)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+			}
+			all = append(all, cs...)
+		}
+		out = append(out, all)
+	}
+	return out, nil
+}
+
+func isConstraintOp(s string) bool {
+	switch s {
+	case "=", "!=", ">", ">=", "<", "<=", "^", "~":
+		return true
+	}
+	return false
+}
+
+func parseComparator(tok string) ([]comparator, error) {
+	if tok == "*" || tok == "x" {
+		return nil, nil
+	}
+	for _, op := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if !strings.HasPrefix(tok, op) {
+			continue
+		}
+		v, err := parseSemver(tok[len(op):])
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case "^":
+			upper := semver{major: v.major + 1}
+			if v.major == 0 {
+				upper = semver{minor: v.minor + 1}
+				if v.minor == 0 && strings.Count(strings.TrimPrefix(tok[1:], "v"), ".") == 2 {
+					upper = semver{patch: v.patch + 1}
+				}
+			}
+			return []comparator{{">=", v}, {"<", upper}}, nil
+		case "~":
+			return []comparator{{">=", v}, {"<", semver{major: v.major, minor: v.minor + 1}}}, nil
+		}
+		return []comparator{{op, v}}, nil
+	}
+	v, err := parseSemver(tok)
+	if err != nil {
+		return nil, err
+	}
+	return []comparator{{"=", v}}, nil
+}
+
+func (c constraint) match(v semver) bool {
+	for _, all := range c {
+		ok := true
+		for _, cmp := range all {
+			if !cmp.match(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SatisfiesVersion reports whether version matches a constraint such as
+// ">=0.2.0 <1.0.0" or "^1.4".
+func SatisfiesVersion(version, expr string) (bool, error) {
+	c, err := parseConstraint(expr)
+	if err != nil {
+		return false, err
+	}
+	v, err := parseSemver(version)
+	if err != nil {
+		return false, err
+	}
+	return c.match(v), nil
+}
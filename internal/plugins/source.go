@@ -0,0 +1,276 @@
+package plugins
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SourceBuiltin = "builtin"
+	SourcePath    = "path"
+	SourceTarball = "tarball"
+)
+
+var ErrAlreadyInstalled = errors.New("plugin already installed")
+
+// Staged is a plugin source unpacked into a directory and validated.
+// ArchiveSHA256 is set for tarball sources.
+type Staged struct {
+	Dir           string
+	Manifest      Manifest
+	SourceType    string
+	ArchiveSHA256 string
+	cleanup       func()
+}
+
+func (s Staged) Close() {
+	if s.cleanup != nil {
+		s.cleanup()
+	}
+}
+
+type SyncResult struct {
+	PluginID string
+	Created  []string
+	Updated  []string
+	Removed  []string
+}
+
+// IsLocalSource reports whether an install argument names a directory or
+// tarball rather than a built-in plugin id.
+func IsLocalSource(arg string) bool {
+	if isTarball(arg) || strings.ContainsAny(arg, `/\`) || strings.HasPrefix(arg, ".") {
+		return true
+	}
+	info, err := os.Stat(arg)
+	return err == nil && info.IsDir()
+}
+
+func isTarball(p string) bool {
+	p = strings.ToLower(p)
+	return strings.HasSuffix(p, ".tar.gz") || strings.HasSuffix(p, ".tgz")
+}
+
+// StageSource validates a plugin directory, or extracts a .tar.gz/.tgz into a
+// temporary directory first. Callers must Close the result.
+func StageSource(src string) (Staged, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return Staged{}, err
+	}
+	st := Staged{Dir: src, SourceType: SourcePath}
+	if !info.IsDir() {
+		if !isTarball(src) {
+			return Staged{}, fmt.Errorf("unsupported plugin source %s (expected a directory, .tar.gz or .tgz)", src)
+		}
+		tmp, err := os.MkdirTemp("", "pacto-plugin-")
+		if err != nil {
+			return Staged{}, err
+		}
+		st.cleanup = func() { _ = os.RemoveAll(tmp) }
+		if err := extractTarball(src, tmp); err != nil {
+			st.Close()
+			return Staged{}, err
+		}
+		st.Dir, err = manifestRoot(tmp)
+		if err != nil {
+			st.Close()
+			return Staged{}, err
+		}
+		st.SourceType = SourceTarball
+		st.ArchiveSHA256, err = FileSHA256(src)
+		if err != nil {
+			st.Close()
+			return Staged{}, err
+		}
+	}
+	m, err := parseManifest(filepath.Join(st.Dir, "plugin.yaml"))
+	if err != nil {
+		st.Close()
+		return Staged{}, fmt.Errorf("parse manifest: %w", err)
+	}
+	if err := validateManifest(m, st.Dir); err != nil {
+		st.Close()
+		return Staged{}, err
+	}
+	st.Manifest = m
+	return st, nil
+}
+
+func extractTarball(src, dst string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(os.PathSeparator)) {
+			return fmt.Errorf("tarball entry escapes plugin directory: %s", hdr.Name)
+		}
+		target := filepath.Join(dst, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o775); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o775); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode).Perm()|0o600)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return err
+			}
+			if err := out.Close(); err != nil {
+				return err
+			}
+		default:
+			// Links and special files are not part of a plugin.
+		}
+	}
+}
+
+// manifestRoot accepts tarballs with plugin.yaml at the top level or inside a
+// single wrapping directory.
+func manifestRoot(dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, "plugin.yaml")); err == nil {
+		return dir, nil
+	}
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(ents) == 1 && ents[0].IsDir() {
+		inner := filepath.Join(dir, ents[0].Name())
+		if _, err := os.Stat(filepath.Join(inner, "plugin.yaml")); err == nil {
+			return inner, nil
+		}
+	}
+	return "", fmt.Errorf("plugin.yaml not found in tarball")
+}
+
+// SyncInstall copies a staged plugin into .pacto/plugins/<id>, removing files
+// the new version no longer ships. config.env is created from
+// config.env.example when missing and otherwise left alone.
+func SyncInstall(projectRoot string, st Staged, replace bool) (SyncResult, error) {
+	id := st.Manifest.Metadata.ID
+	dst := filepath.Join(projectRoot, ".pacto", "plugins", id)
+	if _, err := os.Stat(dst); err == nil && !replace {
+		return SyncResult{}, fmt.Errorf("%w: %s", ErrAlreadyInstalled, id)
+	}
+	_, srcFiles, err := PinDir(st.Dir)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	res := SyncResult{PluginID: id}
+	for rel := range srcFiles {
+		from := filepath.Join(st.Dir, filepath.FromSlash(rel))
+		to := filepath.Join(dst, filepath.FromSlash(rel))
+		b, err := os.ReadFile(from)
+		if err != nil {
+			return SyncResult{}, err
+		}
+		info, err := os.Stat(from)
+		if err != nil {
+			return SyncResult{}, err
+		}
+		mode := info.Mode().Perm() | 0o644
+		if strings.HasSuffix(strings.ToLower(rel), ".sh") {
+			mode |= 0o111
+		}
+		old, readErr := os.ReadFile(to)
+		if readErr == nil && string(old) == string(b) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(to), 0o775); err != nil {
+			return SyncResult{}, err
+		}
+		if err := os.WriteFile(to, b, mode); err != nil {
+			return SyncResult{}, err
+		}
+		if readErr == nil {
+			res.Updated = append(res.Updated, to)
+		} else {
+			res.Created = append(res.Created, to)
+		}
+	}
+	_, dstFiles, err := PinDir(dst)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	for rel := range dstFiles {
+		if _, ok := srcFiles[rel]; ok {
+			continue
+		}
+		p := filepath.Join(dst, filepath.FromSlash(rel))
+		if err := os.Remove(p); err != nil {
+			return SyncResult{}, err
+		}
+		res.Removed = append(res.Removed, p)
+	}
+	configPath := filepath.Join(dst, localConfigFile)
+	if b, err := os.ReadFile(filepath.Join(dst, localConfigFile+".example")); err == nil {
+		if _, statErr := os.Stat(configPath); errors.Is(statErr, fs.ErrNotExist) {
+			if err := os.WriteFile(configPath, b, 0o664); err != nil {
+				return SyncResult{}, err
+			}
+			res.Created = append(res.Created, configPath)
+		}
+	}
+	sort.Strings(res.Created)
+	sort.Strings(res.Updated)
+	sort.Strings(res.Removed)
+	return res, nil
+}
+
+// PinInstalled records the installed plugin directory in the lockfile, with
+// archiveSum for tarball sources.
+func PinInstalled(projectRoot, id, version, source, sourceType, archiveSum string) (LockEntry, error) {
+	sum, files, err := PinDir(filepath.Join(projectRoot, ".pacto", "plugins", id))
+	if err != nil {
+		return LockEntry{}, err
+	}
+	lf, err := ReadLock(projectRoot)
+	if err != nil {
+		return LockEntry{}, err
+	}
+	entry := LockEntry{
+		ID:            id,
+		Version:       version,
+		Source:        source,
+		SourceType:    sourceType,
+		SHA256:        sum,
+		ArchiveSHA256: archiveSum,
+		Files:         files,
+		InstalledAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+	lf.Put(entry)
+	return entry, WriteLock(projectRoot, lf)
+}
@@ -1,10 +1,23 @@
 package plugins
 
-import "time"
+import (
+	"time"
 
+	"pacto/internal/model"
+)
+
+const (
+	ManifestAPIVersion        = "pacto/v1alpha1"
+	ManifestAPIVersionV1Beta1 = "pacto/v1beta1"
+	ManifestKind              = "Plugin"
+)
+
+// Lifecycle phases a CLI guardrail can run in. Only pre hooks can block a
+// command; post and onError hooks run after it with its outcome.
 const (
-	ManifestAPIVersion = "pacto/v1alpha1"
-	ManifestKind       = "Plugin"
+	PhasePre     = "pre"
+	PhasePost    = "post"
+	PhaseOnError = "onError"
 )
 
 type Plugin struct {
@@ -28,24 +41,79 @@ type Metadata struct {
 type Spec struct {
 	CLIGuardrails   []CLIGuardrail   `yaml:"cliGuardrails"`
 	AgentGuardrails []AgentGuardrail `yaml:"agentGuardrails"`
+	StatusChecks    []StatusCheck    `yaml:"statusChecks"`
+	Commands        []PluginCommand  `yaml:"commands"`
+	Requires        Requires         `yaml:"requires,omitempty"`
+	DependsOn       []Dependency     `yaml:"dependsOn,omitempty"`
+	Sandbox         *Sandbox         `yaml:"sandbox,omitempty"`
+}
+
+// Sandbox is the opt-in execution policy for a plugin's scripts. When set,
+// scripts only see allowlisted environment variables, get a private
+// writable temp dir and run under the declared limits.
+type Sandbox struct {
+	// Env lists variable names passed through; NAME* matches a prefix.
+	Env []string `yaml:"env"`
+	// ProjectRoot is read-only (default) or read-write.
+	ProjectRoot string `yaml:"projectRoot"`
+	Network     bool   `yaml:"network"`
+	CPUSeconds  int    `yaml:"cpuSeconds"`
+	MemoryMB    int    `yaml:"memoryMB"`
+}
+
+// Requires constrains the pacto version a plugin runs on (v1beta1).
+type Requires struct {
+	Pacto string `yaml:"pacto,omitempty"`
+}
+
+// Dependency names another plugin that must be active, and loaded first,
+// with an optional version constraint (v1beta1).
+type Dependency struct {
+	ID      string `yaml:"id"`
+	Version string `yaml:"version,omitempty"`
 }
 
 type CLIGuardrail struct {
 	ID       string   `yaml:"id"`
+	Phase    string   `yaml:"phase"`
 	Commands []string `yaml:"commands"`
-	Run      RunSpec  `yaml:"run"`
-	OnFail   OnFail   `yaml:"onFail"`
+	// Serial guardrails run alone, never alongside other guardrails; use it
+	// for scripts that mutate the working tree.
+	Serial bool    `yaml:"serial"`
+	Run    RunSpec `yaml:"run"`
+	OnFail OnFail  `yaml:"onFail"`
 }
 
 type RunSpec struct {
 	Script    string `yaml:"script"`
 	TimeoutMS int    `yaml:"timeoutMs"`
+	Protocol  string `yaml:"protocol"`
 }
 
 type OnFail struct {
 	Message string `yaml:"message"`
 }
 
+// PluginCommand adds `pacto <name>`, dispatched to a plugin script that gets
+// the hook JSON request on stdin and the terminal for its output.
+type PluginCommand struct {
+	Name        string   `yaml:"name"`
+	Summary     string   `yaml:"summary"`
+	Usage       string   `yaml:"usage"`
+	Description string   `yaml:"description"`
+	Examples    []string `yaml:"examples"`
+	// Run.TimeoutMS of zero means no timeout.
+	Run RunSpec `yaml:"run"`
+}
+
+// StatusCheck runs once per parsed plan during `pacto status` and returns
+// extra claim results or warnings for it.
+type StatusCheck struct {
+	ID     string   `yaml:"id"`
+	States []string `yaml:"states"`
+	Run    RunSpec  `yaml:"run"`
+}
+
 type AgentGuardrail struct {
 	ID           string   `yaml:"id"`
 	Tools        []string `yaml:"tools"`
@@ -55,6 +123,12 @@ type AgentGuardrail struct {
 
 type ActiveConfig struct {
 	Enabled []string
+	// Approved maps plugin ids to the permissions digest approved on enable.
+	Approved map[string]string
+	// Parallelism and BudgetMS come from plugins.parallelism and
+	// plugins.budgetMs; zero means the default.
+	Parallelism int
+	BudgetMS    int
 }
 
 type HookRequest struct {
@@ -63,11 +137,36 @@ type HookRequest struct {
 	ProjectRoot string
 	Allow       map[string]bool
 	Verbose     bool
+	Phase       string
+	Outcome     Outcome
+	// Protocol, when set, only runs guardrails using that protocol.
+	Protocol    string
+	Flags       map[string]string
+	Positionals []string
+	PlansRoot   string
+	Plans       []string
+	Report      *model.StatusReport
+	// Language is the UI language (en|es) scripts should answer in.
+	Language string
+	// Parallelism caps concurrently running guardrails and Budget bounds the
+	// whole evaluation; zero values use DefaultParallelism and DefaultBudget.
+	Parallelism int
+	Budget      time.Duration
+}
+
+// Outcome describes a finished command for post and onError hooks.
+type Outcome struct {
+	ExitCode  int      `json:"exit_code"`
+	Touched   []string `json:"touched_files"`
+	FromState string   `json:"from_state,omitempty"`
+	ToState   string   `json:"to_state,omitempty"`
+	Slug      string   `json:"slug,omitempty"`
 }
 
 type GuardrailViolation struct {
 	PluginID    string
 	GuardrailID string
+	Phase       string
 	Message     string
 	Script      string
 	ExitCode    int
@@ -76,6 +175,10 @@ type GuardrailViolation struct {
 	Stderr      string
 	Duration    time.Duration
 	Allowed     bool
+	Passed      bool
+	Decision    string
+	Annotations []Annotation
+	NextActions []NextAction
 }
 
 func (v GuardrailViolation) FullID() string {
@@ -3,6 +3,7 @@ package report
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -24,7 +25,10 @@ func RenderWithLanguage(r model.StatusReport, format string, lang i18n.Language)
 			RepoRoot    string             `json:"repo_root,omitempty"`
 			Mode        string             `json:"mode"`
 			Summary     model.Summary      `json:"summary"`
+			Workspaces  []string           `json:"workspaces,omitempty"`
 			Plans       []model.PlanStatus `json:"plans"`
+			Annotations map[string]string  `json:"annotations,omitempty"`
+			NextActions []string           `json:"next_actions,omitempty"`
 		}{
 			GeneratedAt: r.GeneratedAt.Format(time.RFC3339),
 			Root:        r.Root,
@@ -32,7 +36,10 @@ func RenderWithLanguage(r model.StatusReport, format string, lang i18n.Language)
 			RepoRoot:    r.RepoRoot,
 			Mode:        r.Mode,
 			Summary:     r.Summary,
+			Workspaces:  r.Workspaces,
 			Plans:       r.Plans,
+			Annotations: r.Annotations,
+			NextActions: r.NextActions,
 		}
 		b, err := json.MarshalIndent(out, "", "  ")
 		if err != nil {
@@ -62,7 +69,7 @@ func renderTable(r model.StatusReport, lang i18n.Language) string {
 	fmt.Fprintf(&b, "%-14s %-36s %-11s %-8s %-8s %-10s %-12s\n", i18n.T(lang, "STATE", "ESTADO"), i18n.T(lang, "PLAN", "PLAN"), i18n.T(lang, "VERIF", "VERIF"), i18n.T(lang, "PENDING", "PEND"), i18n.T(lang, "BLOCKED", "BLOQ"), i18n.T(lang, "CONF", "CONF"), i18n.T(lang, "DERIVED", "DERIVADO"))
 	fmt.Fprintf(&b, "%s\n", strings.Repeat("-", 130))
 	for _, p := range r.Plans {
-		fmt.Fprintf(&b, "%-14s %-36s %-11s %-8d %-8d %-10s %-12s\n", p.StateFolder, shorten(p.Slug, 36), p.Verification, p.PendingTasks, p.BlockedTasks, p.Confidence, shorten(p.DerivedStatus, 12))
+		fmt.Fprintf(&b, "%-14s %-36s %-11s %-8d %-8d %-10s %-12s\n", p.StateFolder, shorten(planLabel(p), 36), p.Verification, p.PendingTasks, p.BlockedTasks, p.Confidence, shorten(p.DerivedStatus, 12))
 		if len(p.Blockers) > 0 {
 			fmt.Fprintf(&b, "  %s: %s\n", i18n.T(lang, "blockers", "bloqueadores"), strings.Join(p.Blockers, " | "))
 		}
@@ -72,6 +79,9 @@ func renderTable(r model.StatusReport, lang i18n.Language) string {
 		if len(p.Claims) > 0 {
 			fmt.Fprintf(&b, "  %s: %d\n", i18n.T(lang, "claims", "afirmaciones"), len(p.Claims))
 		}
+		if len(p.Annotations) > 0 {
+			fmt.Fprintf(&b, "  %s: %s\n", i18n.T(lang, "annotations", "anotaciones"), joinAnnotations(p.Annotations))
+		}
 		if len(p.ParseWarnings) > 0 {
 			fmt.Fprintf(&b, "  %s: %s\n", i18n.T(lang, "warnings", "advertencias"), strings.Join(p.ParseWarnings, " | "))
 		}
@@ -79,9 +89,36 @@ func renderTable(r model.StatusReport, lang i18n.Language) string {
 			fmt.Fprintf(&b, "  %s: %s\n", i18n.T(lang, "parse_error", "error_parseo"), p.ParseError)
 		}
 	}
+	if len(r.Annotations) > 0 {
+		fmt.Fprintf(&b, "%s: %s\n", i18n.T(lang, "ANNOTATIONS", "ANOTACIONES"), joinAnnotations(r.Annotations))
+	}
+	if len(r.NextActions) > 0 {
+		fmt.Fprintf(&b, "%s: %s\n", i18n.T(lang, "NEXT", "SIGUIENTE"), strings.Join(r.NextActions, " | "))
+	}
 	return strings.TrimRight(b.String(), "\n")
 }
 
+// planLabel prefixes the slug with its workspace in recursive reports.
+func planLabel(p model.PlanStatus) string {
+	if p.Workspace == "" || p.Workspace == "." {
+		return p.Slug
+	}
+	return p.Workspace + ":" + p.Slug
+}
+
+func joinAnnotations(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+m[k])
+	}
+	return strings.Join(parts, ", ")
+}
+
 func shorten(s string, n int) string {
 	if len(s) <= n {
 		return s
@@ -0,0 +1,340 @@
+package states
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"pacto/internal/i18n"
+	"pacto/internal/yamlutil"
+)
+
+// Archive is reserved for `pacto archive`; it is never a configurable state.
+const Archive = "archive"
+
+var reStateID = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+type State struct {
+	ID     string
+	Folder string
+	Title  string
+	Emoji  string
+	// LabelEN/LabelES are written to plan README `**Status:**` lines.
+	LabelEN string
+	LabelES string
+	// SectionEN/SectionES are the parenthesised suffixes of the index heading.
+	SectionEN string
+	SectionES string
+	// From lists the states a plan may move from; empty means any.
+	From []string
+	// Active marks the state plans are worked in; see Machine.Active.
+	Active bool
+}
+
+type Machine struct {
+	States []State
+}
+
+var builtins = []State{
+	{ID: "current", Folder: "current", Title: "Current", Emoji: "🟢", LabelEN: "In Progress (Current)", LabelES: "En ejecución (Current)", SectionEN: "In Progress", SectionES: "En Ejecución"},
+	{ID: "to-implement", Folder: "to-implement", Title: "To Implement", Emoji: "🟡", LabelEN: "Pending (To Implement)", LabelES: "Pendiente (To Implement)", SectionEN: "Pending", SectionES: "Pendientes"},
+	{ID: "done", Folder: "done", Title: "Done", Emoji: "✅", LabelEN: "Completed (Done)", LabelES: "Completado (Done)", SectionEN: "Completed", SectionES: "Completados"},
+	{ID: "outdated", Folder: "outdated", Title: "Outdated", Emoji: "⚠️", LabelEN: "Outdated (Outdated)", LabelES: "Obsoleto (Outdated)", SectionEN: "Outdated", SectionES: "Obsoletos"},
+}
+
+var archiveState = State{ID: Archive, Folder: Archive, Title: "Archive", Emoji: "🗄️", LabelEN: "Archived (Archive)", LabelES: "Archivado (Archive)", SectionEN: "Archived", SectionES: "Archivados"}
+
+func Default() Machine {
+	out := make([]State, len(builtins))
+	copy(out, builtins)
+	return Machine{States: out}
+}
+
+// ConfigPath returns the workspace config that governs plansRoot: the sibling
+// config.yaml inside .pacto, or <plansRoot>/.pacto/config.yaml for roots that
+// live outside a .pacto workspace.
+func ConfigPath(plansRoot string) string {
+	parent := filepath.Dir(plansRoot)
+	if filepath.Base(parent) == ".pacto" {
+		return filepath.Join(parent, "config.yaml")
+	}
+	return filepath.Join(plansRoot, ".pacto", "config.yaml")
+}
+
+// LoadForPlansRoot reads the `states` list from the workspace config. Without
+// one the built-in current|to-implement|done|outdated machine is returned.
+func LoadForPlansRoot(plansRoot string) (Machine, error) {
+	m, err := yamlutil.ReadFileMap(ConfigPath(plansRoot))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Default(), nil
+		}
+		return Default(), err
+	}
+	return FromConfig(m)
+}
+
+// FromConfig builds a machine from a parsed config map. A declared list
+// replaces the built-ins; entries reusing a built-in id inherit its title,
+// emoji and labels unless they override them.
+func FromConfig(cfg map[string]any) (Machine, error) {
+	raw, ok := cfg["states"]
+	if !ok || raw == nil {
+		return Default(), nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return Default(), fmt.Errorf("states: expected a list")
+	}
+	if len(items) == 0 {
+		return Default(), fmt.Errorf("states: list is empty")
+	}
+	out := Machine{States: make([]State, 0, len(items))}
+	for i, it := range items {
+		m, ok := it.(map[string]any)
+		if !ok {
+			return Default(), fmt.Errorf("states[%d]: expected a mapping", i)
+		}
+		st, err := parseState(m)
+		if err != nil {
+			return Default(), fmt.Errorf("states[%d]: %w", i, err)
+		}
+		out.States = append(out.States, st)
+	}
+	if err := out.validate(); err != nil {
+		return Default(), err
+	}
+	return out, nil
+}
+
+func parseState(m map[string]any) (State, error) {
+	id := strings.ToLower(str(m["id"]))
+	if !reStateID.MatchString(id) {
+		return State{}, fmt.Errorf("invalid id %q (use lowercase letters, numbers, dashes)", id)
+	}
+	if id == Archive {
+		return State{}, fmt.Errorf("id %q is reserved", Archive)
+	}
+	st := State{ID: id, Folder: id, Title: titleFromID(id)}
+	for _, b := range builtins {
+		if b.ID == id {
+			st = b
+			break
+		}
+	}
+	if v := str(m["folder"]); v != "" {
+		st.Folder = v
+	}
+	if v := str(m["title"]); v != "" {
+		st.Title = v
+	}
+	if v, ok := m["emoji"]; ok {
+		st.Emoji = str(v)
+	}
+	if labels := yamlutil.GetMap(m, "labels"); labels != nil {
+		if v := str(labels["en"]); v != "" {
+			st.LabelEN = v
+		}
+		if v := str(labels["es"]); v != "" {
+			st.LabelES = v
+		}
+	}
+	if st.LabelEN == "" {
+		st.LabelEN = st.Title
+	}
+	if st.LabelES == "" {
+		st.LabelES = st.LabelEN
+	}
+	if v, ok := m["from"]; ok {
+		st.From = yamlutil.ToStringSlice(v)
+	}
+	st.Active, _ = m["active"].(bool)
+	if strings.ContainsAny(st.Folder, `/\`) || st.Folder == "." || st.Folder == ".." || st.Folder == Archive {
+		return State{}, fmt.Errorf("invalid folder %q", st.Folder)
+	}
+	return st, nil
+}
+
+func (m Machine) validate() error {
+	ids := map[string]bool{}
+	folders := map[string]bool{}
+	active := ""
+	for _, st := range m.States {
+		if st.Active {
+			if active != "" {
+				return fmt.Errorf("states: both %q and %q are marked active", active, st.ID)
+			}
+			active = st.ID
+		}
+		if ids[st.ID] {
+			return fmt.Errorf("states: duplicate id %q", st.ID)
+		}
+		if folders[st.Folder] {
+			return fmt.Errorf("states: duplicate folder %q", st.Folder)
+		}
+		ids[st.ID] = true
+		folders[st.Folder] = true
+	}
+	for _, st := range m.States {
+		for _, from := range st.From {
+			if !ids[from] {
+				return fmt.Errorf("states: %q allows moves from unknown state %q", st.ID, from)
+			}
+		}
+	}
+	return nil
+}
+
+func (m Machine) Get(id string) (State, bool) {
+	for _, st := range m.States {
+		if st.ID == id {
+			return st, true
+		}
+	}
+	if id == Archive {
+		return archiveState, true
+	}
+	return State{}, false
+}
+
+// Has reports whether id is a configured state; archive is not included.
+func (m Machine) Has(id string) bool {
+	if id == Archive {
+		return false
+	}
+	_, ok := m.Get(id)
+	return ok
+}
+
+func (m Machine) IDs() []string {
+	out := make([]string, 0, len(m.States))
+	for _, st := range m.States {
+		out = append(out, st.ID)
+	}
+	return out
+}
+
+func (m Machine) Folders() []string {
+	out := make([]string, 0, len(m.States))
+	for _, st := range m.States {
+		out = append(out, st.Folder)
+	}
+	return out
+}
+
+// Active returns the state plans are worked in: the one marked active,
+// otherwise current when configured, otherwise the first state.
+func (m Machine) Active() State {
+	for _, st := range m.States {
+		if st.Active {
+			return st
+		}
+	}
+	if st, ok := m.Get("current"); ok {
+		return st
+	}
+	return m.States[0]
+}
+
+// AllowedList renders the configured ids for usage and error messages.
+func (m Machine) AllowedList() string {
+	return strings.Join(m.IDs(), "|")
+}
+
+func (m Machine) Folder(id string) string {
+	if st, ok := m.Get(id); ok {
+		return st.Folder
+	}
+	return id
+}
+
+// Dir returns the folder of state id under plansRoot.
+func (m Machine) Dir(plansRoot, id string) string {
+	return filepath.Join(plansRoot, m.Folder(id))
+}
+
+// ByFolder maps a folder name back to its state id.
+func (m Machine) ByFolder(folder string) (State, bool) {
+	for _, st := range m.States {
+		if st.Folder == folder {
+			return st, true
+		}
+	}
+	if folder == Archive {
+		return archiveState, true
+	}
+	return State{}, false
+}
+
+func (m Machine) Label(id string, lang i18n.Language) string {
+	st, ok := m.Get(id)
+	if !ok {
+		return ""
+	}
+	return i18n.T(lang, st.LabelEN, st.LabelES)
+}
+
+// CanTransition reports whether a plan may move from one state to another.
+func (m Machine) CanTransition(from, to string) bool {
+	st, ok := m.Get(to)
+	if !ok {
+		return false
+	}
+	if len(st.From) == 0 {
+		return true
+	}
+	for _, f := range st.From {
+		if f == from {
+			return true
+		}
+	}
+	return false
+}
+
+// CountRow is the root README counts table row prefix, e.g. "| 🟢 **Current** |".
+func (st State) CountRow() string {
+	return strings.TrimSpace("| "+prefixed(st.Emoji, "**"+st.Title+"**")) + " |"
+}
+
+// Headings lists accepted index section headings, preferred first for lang.
+func (st State) Headings(lang i18n.Language) []string {
+	base := "## " + prefixed(st.Emoji, st.Title)
+	out := make([]string, 0, 3)
+	primary, secondary := st.SectionEN, st.SectionES
+	if lang == i18n.Spanish {
+		primary, secondary = st.SectionES, st.SectionEN
+	}
+	for _, s := range []string{primary, secondary} {
+		if s != "" {
+			out = append(out, base+" ("+s+")")
+		}
+	}
+	return append(out, base)
+}
+
+func prefixed(emoji, s string) string {
+	if emoji == "" {
+		return s
+	}
+	return emoji + " " + s
+}
+
+func titleFromID(id string) string {
+	parts := strings.Split(id, "-")
+	for i := range parts {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func str(v any) string {
+	if v == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprintf("%v", v))
+}
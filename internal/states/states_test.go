@@ -0,0 +1,70 @@
+package states
+
+import (
+	"testing"
+
+	"pacto/internal/i18n"
+	"pacto/internal/yamlutil"
+)
+
+func TestFromConfigInheritsBuiltinsAndChecksTransitions(t *testing.T) {
+	cfg, err := yamlutil.UnmarshalMap([]byte("states:\n  - id: current\n  - id: review\n    folder: in-review\n    from: [current]\n  - id: done\n    from: [review]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm, err := FromConfig(cfg)
+	if err != nil {
+		t.Fatalf("FromConfig: %v", err)
+	}
+	if got := sm.AllowedList(); got != "current|review|done" {
+		t.Fatalf("AllowedList = %q", got)
+	}
+	if got := sm.Label("done", i18n.Spanish); got != "Completado (Done)" {
+		t.Fatalf("inherited label = %q", got)
+	}
+	if got := sm.Folder("review"); got != "in-review" {
+		t.Fatalf("Folder(review) = %q", got)
+	}
+	if sm.Has("outdated") || sm.Has(Archive) {
+		t.Fatalf("expected only declared states")
+	}
+	if !sm.CanTransition("current", "review") || sm.CanTransition("current", "done") || !sm.CanTransition("done", "current") {
+		t.Fatalf("unexpected transition rules")
+	}
+	if got := sm.Active().ID; got != "current" {
+		t.Fatalf("Active = %q", got)
+	}
+}
+
+func TestActiveStateCanBeMarked(t *testing.T) {
+	cfg, err := yamlutil.UnmarshalMap([]byte("states:\n  - id: backlog\n  - id: doing\n    active: true\n  - id: shipped\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm, err := FromConfig(cfg)
+	if err != nil {
+		t.Fatalf("FromConfig: %v", err)
+	}
+	if got := sm.Active().ID; got != "doing" {
+		t.Fatalf("Active = %q", got)
+	}
+}
+
+func TestFromConfigRejectsInvalidStates(t *testing.T) {
+	cases := map[string]string{
+		"reserved": "states:\n  - id: archive\n",
+		"dup":      "states:\n  - id: done\n  - id: done\n",
+		"from":     "states:\n  - id: done\n    from: [review]\n",
+		"folder":   "states:\n  - id: done\n    folder: ../done\n",
+		"active":   "states:\n  - id: doing\n    active: true\n  - id: review\n    active: true\n",
+	}
+	for name, raw := range cases {
+		cfg, err := yamlutil.UnmarshalMap([]byte(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := FromConfig(cfg); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
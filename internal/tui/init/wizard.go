@@ -79,7 +79,7 @@ func New(p onboarding.Profile) model {
 		langOptions:       []i18n.Language{i18n.English, i18n.Spanish},
 		langCursor:        langCursor,
 		selectedLang:      selectedLang,
-		targetOptions:     []string{"codex", "cursor", "claude", "opencode", "other"},
+		targetOptions:     append(append([]string{}, onboarding.KnownTools...), "other"),
 		targetSelected:    selected,
 	}
 }
@@ -97,7 +97,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.searchInput.Focus()
 			return m, nil
 		case "f":
-			m.stateFilter = nextFilter(m.stateFilter)
+			m.stateFilter = nextFilter(m.stateFilter, m.report.States)
 			m.cursor = 0
 			return m, nil
 		}
@@ -106,8 +106,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func nextFilter(state string) string {
-	order := []string{"all", "current", "to-implement", "done", "outdated"}
+func nextFilter(state string, states []string) string {
+	if len(states) == 0 {
+		states = []string{"current", "to-implement", "done", "outdated"}
+	}
+	order := append([]string{"all"}, states...)
 	for i := range order {
 		if order[i] == state {
 			return order[(i+1)%len(order)]
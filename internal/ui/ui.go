@@ -62,6 +62,10 @@ func PathLine(kind, path string) string {
 		return fmt.Sprintf("%s %s", Warn("~"), path)
 	case "skipped":
 		return fmt.Sprintf("%s %s", Dim("="), path)
+	case "removed":
+		return fmt.Sprintf("%s %s", Err("-"), path)
+	case "stripped":
+		return fmt.Sprintf("%s %s", Warn("~"), path)
 	default:
 		return fmt.Sprintf("- %s", path)
 	}
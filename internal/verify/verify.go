@@ -10,19 +10,52 @@ import (
 	"strings"
 
 	"pacto/internal/model"
+	"pacto/internal/states"
+	"pacto/internal/yamlutil"
 )
 
+// Scopes are subproject directories that relative path claims are also
+// resolved against when they do not exist under Root.
 type Verifier struct {
 	Root          string
 	PlansRoot     string
+	Scopes        []string
 	ExcludedFiles map[string]struct{}
 }
 
 func New(repoRoot, plansRoot string) Verifier {
+	return NewWithStates(repoRoot, plansRoot, states.Default())
+}
+
+// NewWithStates excludes plan docs found in the folders of sm.
+func NewWithStates(repoRoot, plansRoot string, sm states.Machine) Verifier {
 	if strings.TrimSpace(plansRoot) == "" {
 		plansRoot = repoRoot
 	}
-	return Verifier{Root: repoRoot, PlansRoot: plansRoot, ExcludedFiles: collectPlanDocs(plansRoot)}
+	return Verifier{Root: repoRoot, PlansRoot: plansRoot, ExcludedFiles: collectPlanDocs(plansRoot, sm.Folders())}
+}
+
+// ScopesForPlansRoot reads verification.scopes from the workspace config,
+// resolved against the project root that holds .pacto.
+func ScopesForPlansRoot(plansRoot string) []string {
+	cfgPath := states.ConfigPath(plansRoot)
+	m, err := yamlutil.ReadFileMap(cfgPath)
+	if err != nil {
+		return nil
+	}
+	base := filepath.Dir(filepath.Dir(cfgPath))
+	out := []string{}
+	for _, s := range yamlutil.ToStringSlice(yamlutil.GetMap(m, "verification")["scopes"]) {
+		s = strings.TrimSpace(s)
+		if s == "" || s == "." {
+			continue
+		}
+		if !filepath.IsAbs(s) {
+			s = filepath.Join(base, filepath.FromSlash(s))
+		}
+		out = append(out, cleanAbs(s))
+	}
+	return out
 }
 
 func (v Verifier) VerifyClaim(plan model.PlanRef, c model.ClaimResult) model.ClaimResult {
@@ -54,6 +87,9 @@ func (v Verifier) verifyPath(c model.ClaimResult) model.ClaimResult {
 	} else {
 		raw2 := strings.TrimPrefix(raw, "./")
 		cand = append(cand, filepath.Join(v.Root, raw), filepath.Join(v.Root, raw2))
+		for _, scope := range v.Scopes {
+			cand = append(cand, filepath.Join(scope, raw2))
+		}
 	}
 
 	planOnly := []string{}
@@ -228,10 +264,10 @@ func (v Verifier) searchWalk(token string) (bool, []string, bool) {
 	return false, nil, false
 }
 
-func collectPlanDocs(root string) map[string]struct{} {
+func collectPlanDocs(root string, folders []string) map[string]struct{} {
 	excluded := map[string]struct{}{}
-	for _, st := range []string{"current", "to-implement", "done", "outdated"} {
-		dir := filepath.Join(root, st)
+	for _, folder := range folders {
+		dir := filepath.Join(root, folder)
 		entries, err := os.ReadDir(dir)
 		if err != nil {
 			continue
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"pacto/internal/model"
+	"pacto/internal/states"
 )
 
 func TestVerifyPathVerifiedInRoot(t *testing.T) {
@@ -24,6 +25,28 @@ func TestVerifyPathVerifiedInRoot(t *testing.T) {
 	}
 }
 
+func TestVerifyPathResolvesAgainstScopes(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".pacto", "config.yaml"), "verification:\n  scopes:\n    - services/api\n")
+	writeFile(t, filepath.Join(root, "services", "api", "internal", "auth.go"), "package internal\n")
+	plansRoot := filepath.Join(root, ".pacto", "plans")
+
+	v := New(root, plansRoot)
+	c := model.ClaimResult{ClaimType: model.ClaimPath, SourceText: "internal/auth.go"}
+	if got := v.VerifyClaim(model.PlanRef{}, c); got.Result != "unverified" {
+		t.Fatalf("expected unverified without scopes, got %q", got.Result)
+	}
+
+	v.Scopes = ScopesForPlansRoot(plansRoot)
+	got := v.VerifyClaim(model.PlanRef{}, c)
+	if got.Result != "verified" {
+		t.Fatalf("expected verified through scope, got %q", got.Result)
+	}
+	if len(got.References) != 1 || got.References[0] != filepath.Join(root, "services", "api", "internal", "auth.go") {
+		t.Fatalf("unexpected references: %v", got.References)
+	}
+}
+
 func TestVerifyPathRejectsTraversalOutsideRoot(t *testing.T) {
 	parent := t.TempDir()
 	root := filepath.Join(parent, "repo")
@@ -89,7 +112,7 @@ func TestCollectPlanDocsFallbackIncludesAllMarkdownExceptReadme(t *testing.T) {
 	writeFile(t, design, "design\n")
 	writeFile(t, notes, "notes\n")
 
-	excluded := collectPlanDocs(root)
+	excluded := collectPlanDocs(root, states.Default().Folders())
 
 	assertExcluded(t, excluded, readme, true)
 	assertExcluded(t, excluded, design, true)
@@ -105,7 +128,7 @@ func TestCollectPlanDocsPrefersPLANFiles(t *testing.T) {
 	writeFile(t, plan, "plan\n")
 	writeFile(t, design, "design\n")
 
-	excluded := collectPlanDocs(root)
+	excluded := collectPlanDocs(root, states.Default().Folders())
 
 	assertExcluded(t, excluded, readme, true)
 	assertExcluded(t, excluded, plan, true)